@@ -0,0 +1,228 @@
+// Package effect is a thin builder for Effect schemas (https://effect.website/docs/schema/introduction) as TypeScript
+// source.
+//
+// The schemas refer to the Schema module of the effect package, i.e. `import { Schema } from "effect"`.
+package effect
+
+import (
+	"regexp"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/internal/formats"
+	"github.com/softwaretechnik-berlin/goats/gotypes/ts"
+	"github.com/softwaretechnik-berlin/goats/gotypes/util"
+)
+
+// S is the Schema module of the effect package.
+var S = ts.ImportedName("effect", "Schema")
+
+var parseResult = ts.ImportedName("effect", "ParseResult")
+
+// Schema is an Effect schema along with the filters and annotations it is piped through, e.g.
+// `Schema.String.pipe(Schema.minLength(1))`, or a property signature if it is optional.
+//
+// Schemas are values: their methods return modified copies rather than modifying the schemas they are called on.
+type Schema struct {
+	schema ts.Source
+	pipe   []ts.Source
+	// signature wraps the schema into a property signature, e.g. `Schema.optional(…)`, unless it is nil.
+	signature func(schema ts.Source) ts.Source
+}
+
+// Expr is an escape hatch to create a Schema from an arbitrary ts.Source, e.g. the name of a declared schema.
+func Expr(schema ts.Source) Schema {
+	return Schema{schema: schema}
+}
+
+// Text is an escape hatch to create a Schema from a hand-written TypeScript expression, which may refer to `Schema`.
+func Text(expr string) Schema {
+	return Expr(ts.Importing(ts.AsSource(expr), "effect", "Schema"))
+}
+
+func member(name ts.Identifier) ts.Source {
+	return ts.Sourcef("%s.%s", S, name)
+}
+
+func call(name ts.Identifier, args ...ts.Source) ts.Source {
+	return ts.InvokeMethod(S, name, args...)
+}
+
+func schemaFunc(name ts.Identifier, args ...ts.Source) Schema {
+	return Expr(call(name, args...))
+}
+
+func Any() Schema     { return Expr(member("Any")) }
+func BigInt() Schema  { return Expr(member("BigIntFromSelf")) }
+func Boolean() Schema { return Expr(member("Boolean")) }
+func Date() Schema    { return Expr(member("DateFromSelf")) }
+func Null() Schema    { return Expr(member("Null")) }
+func Number() Schema  { return Expr(member("Number")) }
+func String() Schema  { return Expr(member("String")) }
+func Unknown() Schema { return Expr(member("Unknown")) }
+
+// Literal accepts only the given literal values.
+func Literal(values ...ts.Source) Schema {
+	return schemaFunc("Literal", values...)
+}
+
+func Array(item Schema) Schema {
+	return schemaFunc("Array", item.TypeScript())
+}
+
+func Record(key, value Schema) Schema {
+	return schemaFunc("Record", ts.Object(ts.Property{Name: "key", Value: key.TypeScript()}, ts.Property{Name: "value", Value: value.TypeScript()}))
+}
+
+// Field is a field of a Struct.
+type Field struct {
+	Name   string
+	Schema Schema
+	// Comment is rendered as a doc comment on the field.
+	Comment string
+}
+
+func Struct(fields ...Field) Schema {
+	return schemaFunc("Struct", ts.Object(util.Map(fields, func(f Field) ts.Property {
+		return ts.Property{Name: f.Name, Value: f.Schema.TypeScript(), Comment: f.Comment}
+	})...))
+}
+
+// Union accepts the values any of the given schemas accepts. It tells apart structs by their literal fields on its own,
+// so it is also the equivalent of discriminated unions.
+func Union(members ...Schema) Schema {
+	return schemaFunc("Union", util.Map(members, Schema.TypeScript)...)
+}
+
+// Suspend defers the evaluation of the given expression, e.g. the name of a schema that is still being declared.
+// The type of the expression is annotated, since TypeScript can't infer the types of schemas referring to themselves.
+func Suspend(getter ts.Source) Schema {
+	return schemaFunc("suspend", ts.Sourcef("(): %s.Schema<any, any> => %s", S, getter))
+}
+
+// Parse parses the given value with the schema, throwing if it is invalid, i.e. `Schema.decodeUnknownSync(schema)(value)`.
+func Parse(schema Schema, value ts.Source) ts.Source {
+	return ts.InvokeFunction(call("decodeUnknownSync", schema.TypeScript()), value)
+}
+
+// TypeScript renders the schema, piped through its filters and annotations if it has any.
+func (s Schema) TypeScript() ts.Source {
+	schema := s.schema
+	if len(s.pipe) > 0 {
+		schema = ts.InvokeMethod(schema, "pipe", s.pipe...)
+	}
+	if s.signature != nil {
+		return s.signature(schema)
+	}
+	return schema
+}
+
+// piped adds the given function of Schema to the pipe of the schema.
+func (s Schema) piped(name ts.Identifier, args ...ts.Source) Schema {
+	return Schema{s.schema, append(s.pipe[:len(s.pipe):len(s.pipe)], call(name, args...)), s.signature}
+}
+
+// NullOr accepts null in addition to the values the schema accepts.
+func (s Schema) NullOr() Schema {
+	return schemaFunc("NullOr", s.TypeScript())
+}
+
+// Optional makes the schema into an optional property signature for a Struct.
+func (s Schema) Optional() Schema {
+	s.signature = func(schema ts.Source) ts.Source { return call("optional", schema) }
+	return s
+}
+
+// Default makes the schema into an optional property signature for a Struct, which replaces undefined with the given
+// value.
+func (s Schema) Default(value ts.Source) Schema {
+	s.signature = func(schema ts.Source) ts.Source {
+		return call("optionalWith", schema, ts.Object(ts.Property{Name: "default", Value: ts.Sourcef("() => %s", value)}))
+	}
+	return s
+}
+
+// Or accepts the values either the schema or the given one accepts.
+func (s Schema) Or(other Schema) Schema {
+	return Union(s, other)
+}
+
+// Compose passes the output of the schema to the given one.
+func (s Schema) Compose(target Schema) Schema {
+	return schemaFunc("compose", s.TypeScript(), target.TypeScript(), ts.Object(nonStrict))
+}
+
+// nonStrict relaxes the type checks of composing and transforming schemas, whose types zod doesn't track precisely.
+var nonStrict = ts.Property{Name: "strict", Value: ts.AsSource("false")}
+
+// TransformTo transforms the output of the schema with the given function into values of the type of the given schema.
+// Encoding isn't supported, since the function can't be inverted.
+func (s Schema) TransformTo(to Schema, fn ts.Source) Schema {
+	return schemaFunc("transformOrFail", s.TypeScript(), to.TypeScript(), ts.Object(
+		nonStrict,
+		ts.Property{Name: "decode", Value: ts.Sourcef("(x) => %s.succeed((%s)(x))", parseResult, fn)},
+		ts.Property{Name: "encode", Value: ts.Sourcef(`(x, _, ast) => %s.fail(new %s.Forbidden(ast, x, "encoding isn't supported"))`, parseResult, parseResult)},
+	))
+}
+
+// TypeSchema is the schema of the values the schema produces, i.e. `Schema.typeSchema(schema)`.
+func (s Schema) TypeSchema() Schema {
+	return schemaFunc("typeSchema", s.TypeScript())
+}
+
+// Rename renames the fields of a Struct according to the given mapping from field names to new names.
+func (s Schema) Rename(names ...ts.Property) Schema {
+	return s.piped("rename", ts.Object(names...))
+}
+
+func (s Schema) Brand(brand string) Schema {
+	return s.piped("brand", ts.StringLiteral(brand))
+}
+
+func (s Schema) Description(text string) Schema {
+	return s.piped("annotations", ts.Object(ts.Property{Name: "description", Value: ts.StringLiteral(text)}))
+}
+
+// Filter rejects the values for which the given TypeScript function returns false.
+func (s Schema) Filter(check ts.Source) Schema {
+	return s.piped("filter", check)
+}
+
+func (s Schema) Int() Schema         { return s.piped("int") }
+func (s Schema) NonNegative() Schema { return s.piped("nonNegative") }
+func (s Schema) GreaterThanOrEqualTo(min ts.Source) Schema {
+	return s.piped("greaterThanOrEqualTo", min)
+}
+func (s Schema) LessThanOrEqualTo(max ts.Source) Schema { return s.piped("lessThanOrEqualTo", max) }
+func (s Schema) GreaterThan(min ts.Source) Schema       { return s.piped("greaterThan", min) }
+func (s Schema) LessThan(max ts.Source) Schema          { return s.piped("lessThan", max) }
+func (s Schema) Between(min, max ts.Source) Schema      { return s.piped("between", min, max) }
+func (s Schema) MinLength(length int) Schema {
+	return s.piped("minLength", ts.NumberLiteral(length))
+}
+func (s Schema) MaxLength(length int) Schema {
+	return s.piped("maxLength", ts.NumberLiteral(length))
+}
+func (s Schema) Length(length int) Schema  { return s.piped("length", ts.NumberLiteral(length)) }
+func (s Schema) MinItems(count int) Schema { return s.piped("minItems", ts.NumberLiteral(count)) }
+func (s Schema) MaxItems(count int) Schema { return s.piped("maxItems", ts.NumberLiteral(count)) }
+func (s Schema) ItemsCount(count int) Schema {
+	return s.piped("itemsCount", ts.NumberLiteral(count))
+}
+func (s Schema) Includes(substring string) Schema {
+	return s.piped("includes", ts.StringLiteral(substring))
+}
+func (s Schema) StartsWith(prefix string) Schema {
+	return s.piped("startsWith", ts.StringLiteral(prefix))
+}
+func (s Schema) EndsWith(suffix string) Schema    { return s.piped("endsWith", ts.StringLiteral(suffix)) }
+func (s Schema) Pattern(re *regexp.Regexp) Schema { return s.piped("pattern", ts.RegexLiteral(re)) }
+func (s Schema) URL() Schema                      { return s.Filter(ts.AsSource(`(s) => URL.canParse(s)`)) }
+
+// Effect has no filters for the following formats, so they are checked with regular expressions.
+func (s Schema) UUID() Schema  { return s.Pattern(formats.UUID) }
+func (s Schema) Email() Schema { return s.Pattern(formats.Email) }
+func (s Schema) IP() Schema    { return s.Pattern(formats.IP) }
+func (s Schema) IPv4() Schema  { return s.Pattern(formats.IPv4) }
+func (s Schema) IPv6() Schema  { return s.Pattern(formats.IPv6) }
+
+// DatetimeWithOffset requires an ISO 8601 date and time with a time zone offset, e.g. `2006-01-02T15:04:05+07:00`.
+func (s Schema) DatetimeWithOffset() Schema { return s.Pattern(formats.DatetimeWithOffset) }
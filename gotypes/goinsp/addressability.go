@@ -0,0 +1,41 @@
+package goinsp
+
+import "reflect"
+
+// Addressability tells whether encoding/json can take the address of the values in some position, in which case they
+// also have the methods with pointer receivers, e.g. MarshalJSON and MarshalText methods of pointer types.
+type Addressability uint8
+
+const (
+	// Unaddressable values only have the methods of their types' method sets. They are passed to json.Marshal
+	// directly, are map values or are held by interfaces.
+	Unaddressable Addressability = iota
+	// Addressable values also have the methods of their pointer types. They are pointed to, are slice elements, or are
+	// fields or array elements of addressable values.
+	Addressable
+	// MaybeAddressable values are fields or array elements of values whose addressability isn't known, e.g. the fields
+	// of struct types, whose values may be marshalled directly or through pointers.
+	MaybeAddressable
+)
+
+// Elem returns the addressability of the values the values of type t with addressability a point to, or of their
+// elements.
+func (a Addressability) Elem(t Type) Addressability {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice:
+		return Addressable
+	case reflect.Array:
+		return a
+	default:
+		return Unaddressable
+	}
+}
+
+// Implements tells whether the values of type t with addressability a implement the interface u. MaybeAddressable
+// values implement it if both unaddressable and addressable values do.
+func (a Addressability) Implements(t, u Type) bool {
+	if t.Implements(u) {
+		return true
+	}
+	return a == Addressable && t.Kind() != reflect.Pointer && t.Kind() != reflect.Interface && t.PointerTo().Implements(u)
+}
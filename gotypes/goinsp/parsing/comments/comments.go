@@ -4,8 +4,9 @@ package comments
 import (
 	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/token"
-	"slices"
+	"go/types"
 	"strings"
 
 	"golang.org/x/tools/go/packages"
@@ -14,8 +15,31 @@ import (
 	//"github.com/softwaretechnik-berlin/goats/internal/domain"
 )
 
+// Loader loads the comments of named types from the source of their packages.
+//
+// Its methods return an error if the package or declaration of the type can't be found.
 type Loader interface {
-	Load(t goinsp.Type) string
+	// Load returns the doc comment of the given named type.
+	Load(t goinsp.Type) (string, error)
+	// LoadField returns the doc comment of the field with the given name in the given named struct type.
+	// Where a field has no doc comment but a line comment, the line comment is returned.
+	LoadField(t goinsp.Type, field string) (string, error)
+	// LoadDirectives returns the directives in the doc comment of the given named type.
+	LoadDirectives(t goinsp.Type) ([]Directive, error)
+	// LoadMethodDirectives returns the directives in the doc comment of the method with the given name declared on the
+	// given named type. It returns nil if the type has no such method declaration, e.g. if the method is promoted.
+	LoadMethodDirectives(t goinsp.Type, method string) ([]Directive, error)
+	// LoadConstants returns the constants declared with the given named type in its package, in the order of their
+	// declarations.
+	LoadConstants(t goinsp.Type) ([]Constant, error)
+}
+
+// Constant is a constant declared with a named type.
+type Constant struct {
+	Name  string
+	Value constant.Value
+	// Comment is the doc comment of the constant, or its line comment if it has no doc comment.
+	Comment string
 }
 
 type loader struct {
@@ -25,11 +49,15 @@ type loader struct {
 
 type packageInfo struct {
 	typeInfos map[goinsp.TypeName]typeInfo
+	// err is the reason the package couldn't be loaded, so that loading isn't attempted again.
+	err error
 }
 
 type typeInfo struct {
 	declaration typeDeclaration
+	fields      map[string]*ast.Field
 	methods     map[string]functionDeclaration
+	constants   []Constant
 }
 
 type typeDeclaration struct {
@@ -61,49 +89,85 @@ func NewLoader() Loader {
 	}
 }
 
-func (l loader) Load(t goinsp.Type) string {
-	info := l.typeInfo(t)
+func (l loader) Load(t goinsp.Type) (string, error) {
+	info, err := l.typeInfo(t)
+	if err != nil {
+		return "", err
+	}
 	commentGroup := info.declaration.CommentGroup()
-	return commentGroup.Text()
+	return commentGroup.Text(), nil
+}
+
+func (l loader) LoadField(t goinsp.Type, name string) (string, error) {
+	info, err := l.typeInfo(t)
+	if err != nil {
+		return "", err
+	}
+	field, ok := info.fields[name]
+	if !ok {
+		return "", fmt.Errorf("can't find field %s of %s", name, t)
+	}
+	if field.Doc != nil {
+		return field.Doc.Text(), nil
+	}
+	return field.Comment.Text(), nil
 }
 
-func (l loader) LoadMethod(t goinsp.Type, name string) string {
-	info := l.typeInfo(t)
+func (l loader) LoadDirectives(t goinsp.Type) ([]Directive, error) {
+	info, err := l.typeInfo(t)
+	if err != nil {
+		return nil, err
+	}
+	return directivesIn(info.declaration.CommentGroup()), nil
+}
+
+func (l loader) LoadMethodDirectives(t goinsp.Type, name string) ([]Directive, error) {
+	info, err := l.typeInfo(t)
+	if err != nil {
+		return nil, err
+	}
 	method, ok := info.methods[name]
 	if !ok {
-		panic(fmt.Sprintf("can't find %s for %s in %#v", name, t.Name(), info.methods))
+		return nil, nil
 	}
-	commentGroup := method.funcDecl.Doc
-	return commentGroup.Text()
+	return directivesIn(method.funcDecl.Doc), nil
 }
 
-func (l loader) typeInfo(t goinsp.Type) typeInfo {
+func (l loader) LoadConstants(t goinsp.Type) ([]Constant, error) {
+	info, err := l.typeInfo(t)
+	return info.constants, err
+}
+
+func (l loader) typeInfo(t goinsp.Type) (typeInfo, error) {
 	pkg, ok := l.packages[t.PkgPath()]
 	if !ok {
 		l.loadTypesFromPackage(t.PkgPath())
-		pkg, ok = l.packages[t.PkgPath()]
-		if !ok {
-			panic(t)
-		}
+		pkg = l.packages[t.PkgPath()]
+	}
+	if pkg.err != nil {
+		return typeInfo{}, pkg.err
 	}
 	if td, ok := pkg.typeInfos[t.WithoutTypeArguments().Name()]; ok {
-		return td
+		return td, nil
 	}
-	panic(t)
+	return typeInfo{}, fmt.Errorf("can't find the declaration of %s in the source of package %s", t, t.PkgPath())
 }
 
+// loadTypesFromPackage loads the package with the given path along with its test packages, recording why it can't be
+// loaded if it can't.
 func (l loader) loadTypesFromPackage(path goinsp.ImportPath) {
-	path = goinsp.ImportPath(strings.TrimSuffix(string(path), "_test"))
-	pkgs, err := packages.Load(l.packagesConfig, string(path))
+	loaded := goinsp.ImportPath(strings.TrimSuffix(string(path), "_test"))
+	pkgs, err := packages.Load(l.packagesConfig, string(loaded))
 	if err != nil {
-		panic(err)
-	}
-	if slices.IndexFunc(pkgs, func(p *packages.Package) bool { return p.PkgPath == string(path) }) == -1 {
-		panic(pkgs)
+		l.packages[path] = packageInfo{err: fmt.Errorf("can't load package %s: %w", loaded, err)}
+		return
 	}
 	for _, pkg := range pkgs {
 		typeInfos := l.collectTypeInfo(pkg)
-		l.packages[goinsp.ImportPath(pkg.PkgPath)] = packageInfo{typeInfos}
+		l.packages[goinsp.ImportPath(pkg.PkgPath)] = packageInfo{typeInfos: typeInfos}
+	}
+	if _, ok := l.packages[path]; !ok {
+		l.packages[path] = packageInfo{err: fmt.Errorf("can't find the source of package %s", path)}
 	}
 }
 
@@ -120,9 +184,16 @@ func (l loader) collectTypeInfo(pkg *packages.Package) map[goinsp.TypeName]typeI
 						name := goinsp.TypeName(spec.Name.Name)
 						info := typeInfos[name]
 						info.declaration = typeDeclaration{genDecl, spec, pkg.Fset.Position(spec.Pos())}
+						if structType, ok := spec.Type.(*ast.StructType); ok {
+							info.fields = collectFields(structType)
+						}
 						typeInfos[name] = info
-					case *ast.ImportSpec, *ast.ValueSpec:
-						// not currently interested in imports or values
+					case *ast.ValueSpec:
+						if genDecl.Tok == token.CONST {
+							collectConstants(pkg, genDecl, spec, typeInfos)
+						}
+					case *ast.ImportSpec:
+						// not currently interested in imports
 					default:
 						panic(spec)
 					}
@@ -150,6 +221,45 @@ func (l loader) collectTypeInfo(pkg *packages.Package) map[goinsp.TypeName]typeI
 	return typeInfos
 }
 
+// collectConstants adds the constants of the given spec that are declared with named types of the package to the infos
+// of those types.
+func collectConstants(pkg *packages.Package, genDecl *ast.GenDecl, spec *ast.ValueSpec, typeInfos map[goinsp.TypeName]typeInfo) {
+	commentGroup := spec.Doc
+	if commentGroup == nil && len(genDecl.Specs) == 1 {
+		commentGroup = genDecl.Doc
+	}
+	if commentGroup == nil {
+		commentGroup = spec.Comment
+	}
+	for _, name := range spec.Names {
+		c, ok := pkg.TypesInfo.Defs[name].(*types.Const)
+		if !ok || name.Name == "_" {
+			continue
+		}
+		named, ok := types.Unalias(c.Type()).(*types.Named)
+		if !ok || named.Obj().Pkg() != pkg.Types {
+			continue
+		}
+		typeName := goinsp.TypeName(named.Obj().Name())
+		info := typeInfos[typeName]
+		info.constants = append(info.constants, Constant{name.Name, c.Val(), commentGroup.Text()})
+		typeInfos[typeName] = info
+	}
+}
+
+func collectFields(structType *ast.StructType) map[string]*ast.Field {
+	fields := make(map[string]*ast.Field)
+	for _, field := range structType.Fields.List {
+		if len(field.Names) == 0 {
+			fields[nameOf(field.Type).String()] = field
+		}
+		for _, name := range field.Names {
+			fields[name.Name] = field
+		}
+	}
+	return fields
+}
+
 func nameOf(expr ast.Expr) goinsp.TypeName {
 	for {
 		switch typed := expr.(type) {
@@ -161,6 +271,8 @@ func nameOf(expr ast.Expr) goinsp.TypeName {
 			expr = typed.X
 		case *ast.IndexListExpr:
 			expr = typed.X
+		case *ast.SelectorExpr:
+			expr = typed.Sel
 		default:
 			panic(fmt.Sprintf("%v, i.e. %#v", expr, expr))
 		}
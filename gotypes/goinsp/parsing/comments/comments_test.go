@@ -1,8 +1,10 @@
 package comments
 
 import (
+	"go/constant"
 	"testing"
 
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
@@ -11,17 +13,72 @@ import (
 func TestLoadTypeComment(t *testing.T) {
 	loader := NewLoader()
 
-	assert.Equal(t, ``, loader.Load(reflective.TypeFor[uncommentedType]()))
+	assert.Equal(t, ``, lo.Must(loader.Load(reflective.TypeFor[uncommentedType]())))
 
-	assert.Equal(t, ``, loader.Load(reflective.TypeFor[solitaryUncommentedTypeInGroupWithoutComment]()))
+	assert.Equal(t, ``, lo.Must(loader.Load(reflective.TypeFor[solitaryUncommentedTypeInGroupWithoutComment]())))
 
 	assert.Equal(t, `solitaryUncommentedTypeInGroupWithComment is a test case for this package.
 It's a type that's declared in a declaration group of which it is the sole member.
 And it doesn't have its own comment, whereas its group does.
-`, loader.Load(reflective.TypeFor[solitaryUncommentedTypeInGroupWithComment]()))
+`, lo.Must(loader.Load(reflective.TypeFor[solitaryUncommentedTypeInGroupWithComment]())))
 
 	assert.Equal(t, `solitaryCommentedTypeInGroupWithoutComment is a test case for this package.
 It's a type that's declared in a declaration group of which it is the sole member.
 And it doesn't has its own comment. It's group has none.
-`, loader.Load(reflective.TypeFor[solitaryCommentedTypeInGroupWithoutComment]()))
+`, lo.Must(loader.Load(reflective.TypeFor[solitaryCommentedTypeInGroupWithoutComment]())))
+}
+
+func TestLoadFieldComment(t *testing.T) {
+	loader := NewLoader()
+	structType := reflective.TypeFor[structWithCommentedFields]()
+
+	assert.Equal(t, "Documented has a doc comment.\n", lo.Must(loader.LoadField(structType, "Documented")))
+	assert.Equal(t, "LineCommented has a line comment.\n", lo.Must(loader.LoadField(structType, "LineCommented")))
+	assert.Equal(t, "Both has a doc comment,\n", lo.Must(loader.LoadField(structType, "Both")))
+	assert.Equal(t, "", lo.Must(loader.LoadField(structType, "Uncommented")))
+	assert.Equal(t, "First and Second share a comment.\n", lo.Must(loader.LoadField(structType, "First")))
+	assert.Equal(t, "First and Second share a comment.\n", lo.Must(loader.LoadField(structType, "Second")))
+	assert.Equal(t, "uncommentedType is embedded.\n", lo.Must(loader.LoadField(structType, "uncommentedType")))
+}
+
+func TestLoadDirectives(t *testing.T) {
+	loader := NewLoader()
+	withDirectives := reflective.TypeFor[typeWithDirectives]()
+
+	assert.Equal(t, "typeWithDirectives is a test case for this package.\n\nIts directives are not part of the text of its comment.\n", lo.Must(loader.Load(withDirectives)))
+	assert.Equal(t, []Directive{
+		{"gotypes", "schema", "z.string()"},
+		{"go", "generate", "echo this is a directive for another tool"},
+		{"gotypes", "template", "{}px"},
+	}, lo.Must(loader.LoadDirectives(withDirectives)))
+	assert.Equal(t, []Directive{{"gotypes", "transform", "s => s.length"}}, lo.Must(loader.LoadMethodDirectives(withDirectives, "MarshalText")))
+	assert.Nil(t, lo.Must(loader.LoadMethodDirectives(withDirectives, "String")))
+	assert.Nil(t, lo.Must(loader.LoadMethodDirectives(withDirectives, "MarshalJSON")))
+	assert.Nil(t, lo.Must(loader.LoadDirectives(reflective.TypeFor[typeWithMultilineComment]())))
+}
+
+func TestLoadConstants(t *testing.T) {
+	loader := NewLoader()
+
+	assert.Equal(t, []Constant{
+		{"red", constant.MakeString("red"), "red is documented.\n"},
+		{"green", constant.MakeString("green"), "green has a line comment.\n"},
+		{"blue", constant.MakeString("blue"), ""},
+	}, lo.Must(loader.LoadConstants(reflective.TypeFor[colour]())))
+	assert.Equal(t, []Constant{
+		{"monday", constant.MakeInt64(1), "monday is documented, while its group isn't.\n"},
+		{"tuesday", constant.MakeInt64(2), ""},
+		{"wednesday", constant.MakeInt64(3), ""},
+	}, lo.Must(loader.LoadConstants(reflective.TypeFor[weekday]())))
+	assert.Empty(t, lo.Must(loader.LoadConstants(reflective.TypeFor[uncommentedType]())))
+}
+
+func TestLoadingUnknownTypesFails(t *testing.T) {
+	loader := NewLoader()
+
+	_, err := loader.LoadField(reflective.TypeFor[structWithCommentedFields](), "Missing")
+	assert.EqualError(t, err, "can't find field Missing of comments.structWithCommentedFields")
+	type local struct{}
+	_, err = loader.Load(reflective.TypeFor[local]())
+	assert.EqualError(t, err, "can't find the declaration of comments.local in the source of package github.com/softwaretechnik-berlin/goats/gotypes/goinsp/parsing/comments")
 }
@@ -0,0 +1,76 @@
+package comments
+
+import (
+	"go/ast"
+	"strings"
+)
+
+// Directive is a directive comment of the form `//tool:name args`, as described in https://go.dev/doc/comment#syntax.
+//
+// Directive comments are omitted from the text of doc comments, so they have to be loaded separately.
+type Directive struct {
+	Tool string
+	Name string
+	Args string
+}
+
+func (d Directive) String() string {
+	s := "//" + d.Tool + ":" + d.Name
+	if d.Args != "" {
+		s += " " + d.Args
+	}
+	return s
+}
+
+func directivesIn(commentGroup *ast.CommentGroup) []Directive {
+	if commentGroup == nil {
+		return nil
+	}
+	var texts []string
+	for _, comment := range commentGroup.List {
+		texts = append(texts, comment.Text)
+	}
+	return ParseDirectives(texts)
+}
+
+// ParseDirectives returns the directives among the given comments, e.g. those returned by goinsp.Type.Directives.
+func ParseDirectives(comments []string) []Directive {
+	var directives []Directive
+	for _, comment := range comments {
+		if directive, ok := parseDirective(comment); ok {
+			directives = append(directives, directive)
+		}
+	}
+	return directives
+}
+
+func parseDirective(comment string) (Directive, bool) {
+	text, ok := strings.CutPrefix(comment, "//")
+	if !ok {
+		return Directive{}, false
+	}
+	tool, rest, ok := strings.Cut(text, ":")
+	if !ok || !isDirectiveWord(tool) {
+		return Directive{}, false
+	}
+	name, args := rest, ""
+	if i := strings.IndexAny(rest, " \t"); i != -1 {
+		name, args = rest[:i], rest[i:]
+	}
+	if !isDirectiveWord(name) {
+		return Directive{}, false
+	}
+	return Directive{tool, name, strings.TrimSpace(args)}, true
+}
+
+func isDirectiveWord(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !('a' <= r && r <= 'z' || '0' <= r && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
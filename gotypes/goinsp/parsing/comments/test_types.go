@@ -34,3 +34,56 @@ type (
 	// And it doesn't has its own comment. It's group has a comment that isn't specific to this type.
 	solitaryCommentedTypeInGroupWithComment struct{}
 )
+
+// structWithCommentedFields is a test case for this package.
+type structWithCommentedFields struct {
+	// Documented has a doc comment.
+	Documented    string
+	LineCommented string // LineCommented has a line comment.
+	// Both has a doc comment,
+	Both          string // and a line comment.
+	Uncommented   string
+	First, Second int // First and Second share a comment.
+	// uncommentedType is embedded.
+	uncommentedType
+}
+
+// typeWithDirectives is a test case for this package.
+//
+// Its directives are not part of the text of its comment.
+//
+//gotypes:schema z.string()
+//go:generate echo this is a directive for another tool
+//gotypes:template	{}px
+type typeWithDirectives int
+
+// MarshalText is documented.
+//
+//gotypes:transform s => s.length
+func (typeWithDirectives) MarshalText() ([]byte, error) { return nil, nil }
+
+func (typeWithDirectives) String() string { return "" }
+
+// colour is a test case for this package, whose constants are declared in a group.
+type colour string
+
+const (
+	// red is documented.
+	red   colour = "red"
+	green colour = "green" // green has a line comment.
+	blue  colour = "blue"
+	_     colour = "unnamed"
+	// notAColour is untyped, so it isn't one of the constants of colour.
+	notAColour = "purple"
+)
+
+// weekday is a test case for this package, whose constants are declared with iota.
+type weekday int
+
+// monday is documented, while its group isn't.
+const monday weekday = 1
+
+const (
+	tuesday weekday = iota + 2
+	wednesday
+)
@@ -17,3 +17,7 @@ func (f fieldAdaptor) IsExported() bool {
 func (f fieldAdaptor) Type() goinsp.Type {
 	return typeAdaptor{f.reflected.Type}
 }
+
+func (f fieldAdaptor) Comment() goinsp.PotentiallyUnavailable[goinsp.NoneWhenZero[string]] {
+	return goinsp.PotentiallyUnavailable[goinsp.NoneWhenZero[string]]{}
+}
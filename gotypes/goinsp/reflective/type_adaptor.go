@@ -17,6 +17,10 @@ func (t typeAdaptor) Comment() goinsp.PotentiallyUnavailable[goinsp.NoneWhenZero
 	return goinsp.PotentiallyUnavailable[goinsp.NoneWhenZero[string]]{}
 }
 
+func (t typeAdaptor) Directives(string) goinsp.PotentiallyUnavailable[[]string] {
+	return goinsp.PotentiallyUnavailable[[]string]{}
+}
+
 func (t typeAdaptor) Name() goinsp.TypeName {
 	return goinsp.TypeName(t.reflected.Name())
 }
@@ -29,6 +33,10 @@ func (t typeAdaptor) Implements(u goinsp.Type) bool {
 	return t.reflected.Implements(u.(typeAdaptor).reflected)
 }
 
+func (t typeAdaptor) PointerTo() goinsp.Type {
+	return typeAdaptor{reflect.PointerTo(t.reflected)}
+}
+
 func (t typeAdaptor) Kind() reflect.Kind {
 	return t.reflected.Kind()
 }
@@ -61,6 +69,16 @@ func (t typeAdaptor) WithoutTypeArguments() goinsp.GenType {
 	return t
 }
 
+func (t typeAdaptor) TypeArguments() goinsp.PotentiallyUnavailable[[]goinsp.Type] {
+	// Reflection only exposes the type arguments as part of the name, which we can't turn back into types.
+	return goinsp.PotentiallyUnavailable[[]goinsp.Type]{Available: !strings.ContainsRune(t.reflected.Name(), '[')}
+}
+
+func (t typeAdaptor) IsTypeParameter() bool {
+	// Reflection only deals with instantiated types.
+	return false
+}
+
 func Adapt(t reflect.Type) goinsp.Type {
 	return typeAdaptor{t}
 }
@@ -72,3 +90,9 @@ func TypeOf(i any) goinsp.Type {
 func TypeFor[T any]() goinsp.Type {
 	return Adapt(reflect.TypeFor[T]())
 }
+
+// Reflected returns the reflect.Type the given type adapts, if it was obtained through reflection.
+func Reflected(t goinsp.Type) (reflect.Type, bool) {
+	adaptor, ok := t.(typeAdaptor)
+	return adaptor.reflected, ok
+}
@@ -0,0 +1,379 @@
+package static
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+)
+
+// Loader loads types from Go source.
+//
+// All packages loaded in a single call share one universe of type-checked objects, so types from packages that are
+// loaded together can be compared and tested for interface implementation with full fidelity. Packages that are
+// imported (transitively) by a loaded package are loaded along with it.
+type Loader interface {
+	// Load loads the packages matching the given patterns, so that their types can subsequently be looked up.
+	Load(patterns ...string) error
+	// Lookup returns the named type declared at package scope in the package with the given import path,
+	// loading the package if necessary.
+	Lookup(path goinsp.ImportPath, name goinsp.TypeName) (goinsp.Type, error)
+	// PackageTypes returns the exported, non-generic named types declared in the package with the given import path,
+	// in order of declaration, loading the package if necessary.
+	PackageTypes(path goinsp.ImportPath) ([]goinsp.Type, error)
+	// Adapt returns a goinsp.Type for a go/types type that was obtained from a package loaded by this Loader.
+	Adapt(t types.Type) goinsp.Type
+}
+
+type loader struct {
+	*state
+}
+
+type state struct {
+	packagesConfig   *packages.Config
+	packages         map[goinsp.ImportPath]*packages.Package
+	canonical        typeutil.Map
+	typeComments     map[*types.TypeName]string
+	typeDirectives   map[*types.TypeName][]string
+	methodDirectives map[*types.Func][]string
+	fieldComments    map[*types.Var]string
+}
+
+// NewLoader returns a Loader that loads packages with the given build flags (e.g. `-tags=integration`).
+func NewLoader(buildFlags ...string) Loader {
+	return NewLoaderWithConfig(&packages.Config{BuildFlags: buildFlags, Tests: true})
+}
+
+// NewLoaderWithConfig returns a Loader that loads packages using the given configuration.
+// The configuration's Mode is overridden with what the Loader requires.
+func NewLoaderWithConfig(config *packages.Config) Loader {
+	config.Mode = packages.NeedName | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps
+	return loader{&state{
+		packagesConfig:   config,
+		packages:         make(map[goinsp.ImportPath]*packages.Package),
+		typeComments:     make(map[*types.TypeName]string),
+		typeDirectives:   make(map[*types.TypeName][]string),
+		methodDirectives: make(map[*types.Func][]string),
+		fieldComments:    make(map[*types.Var]string),
+	}}
+}
+
+func (l loader) Load(patterns ...string) error {
+	pkgs, err := packages.Load(l.packagesConfig, patterns...)
+	if err != nil {
+		return err
+	}
+	var errs []string
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		for _, e := range pkg.Errors {
+			errs = append(errs, e.Error())
+		}
+		l.register(pkg)
+	})
+	if len(errs) > 0 {
+		return fmt.Errorf("errors loading %v:\n%s", patterns, strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+func (l loader) Lookup(path goinsp.ImportPath, name goinsp.TypeName) (goinsp.Type, error) {
+	pkg, err := l.pkg(path)
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := pkg.Types.Scope().Lookup(name.String()).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("there is no type %s in package %q", name, path)
+	}
+	return l.Adapt(obj.Type()), nil
+}
+
+func (l loader) PackageTypes(path goinsp.ImportPath) ([]goinsp.Type, error) {
+	pkg, err := l.pkg(path)
+	if err != nil {
+		return nil, err
+	}
+	var objs []*types.TypeName
+	scope := pkg.Types.Scope()
+	for _, name := range scope.Names() {
+		if obj, ok := scope.Lookup(name).(*types.TypeName); ok && obj.Exported() && !obj.IsAlias() {
+			if named, ok := obj.Type().(*types.Named); ok && named.TypeParams().Len() == 0 {
+				objs = append(objs, obj)
+			}
+		}
+	}
+	slices.SortFunc(objs, func(a, b *types.TypeName) int { return int(a.Pos() - b.Pos()) })
+	result := make([]goinsp.Type, len(objs))
+	for i, obj := range objs {
+		result[i] = l.Adapt(obj.Type())
+	}
+	return result, nil
+}
+
+func (l loader) Adapt(t types.Type) goinsp.Type {
+	return typeAdaptor{l, l.canonicalize(t)}
+}
+
+func (l loader) canonicalize(t types.Type) types.Type {
+	t = types.Unalias(t)
+	if basic, ok := t.(*types.Basic); ok {
+		// byte and rune are aliases whose *types.Basic differ from those of uint8 and int32 only in name.
+		return types.Typ[basic.Kind()]
+	}
+	if canonical := l.canonical.At(t); canonical != nil {
+		return canonical.(types.Type)
+	}
+	l.canonical.Set(t, t)
+	return t
+}
+
+func (l loader) pkg(path goinsp.ImportPath) (*packages.Package, error) {
+	if pkg, ok := l.packages[path]; ok {
+		return pkg, nil
+	}
+	// External test packages are loaded along with the package they test.
+	if err := l.Load(strings.TrimSuffix(string(path), "_test")); err != nil {
+		return nil, err
+	}
+	if pkg, ok := l.packages[path]; ok {
+		return pkg, nil
+	}
+	return nil, fmt.Errorf("loading %q didn't produce a package with that import path", path)
+}
+
+func (l loader) register(pkg *packages.Package) {
+	path := goinsp.ImportPath(pkg.PkgPath)
+	if existing, ok := l.packages[path]; ok && len(existing.Syntax) >= len(pkg.Syntax) {
+		return
+	}
+	if pkg.Types == nil {
+		return
+	}
+	l.packages[path] = pkg
+	l.collectComments(pkg)
+}
+
+func (l loader) collectComments(pkg *packages.Package) {
+	if pkg.TypesInfo == nil {
+		return
+	}
+	for _, file := range pkg.Syntax {
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.GenDecl:
+				for _, spec := range node.Specs {
+					if spec, ok := spec.(*ast.TypeSpec); ok {
+						if obj, ok := pkg.TypesInfo.Defs[spec.Name].(*types.TypeName); ok {
+							l.typeComments[obj] = typeCommentGroup(node, spec).Text()
+							l.typeDirectives[obj] = directiveComments(typeCommentGroup(node, spec))
+						}
+					}
+				}
+			case *ast.FuncDecl:
+				if obj, ok := pkg.TypesInfo.Defs[node.Name].(*types.Func); ok && node.Recv != nil {
+					l.methodDirectives[obj] = directiveComments(node.Doc)
+				}
+			case *ast.StructType:
+				for _, field := range node.Fields.List {
+					comment := fieldCommentGroup(field).Text()
+					names := field.Names
+					if len(names) == 0 {
+						// For an embedded field, Defs maps the identifier of the embedded type's name to the field.
+						if ident, ok := embeddedTypeIdent(field.Type); ok {
+							names = []*ast.Ident{ident}
+						}
+					}
+					for _, name := range names {
+						if obj, ok := pkg.TypesInfo.Defs[name].(*types.Var); ok {
+							l.fieldComments[obj] = comment
+						}
+					}
+				}
+			}
+			return true
+		})
+	}
+}
+
+func typeCommentGroup(genDecl *ast.GenDecl, typeSpec *ast.TypeSpec) *ast.CommentGroup {
+	if typeSpec.Doc != nil {
+		return typeSpec.Doc
+	}
+	if len(genDecl.Specs) == 1 {
+		return genDecl.Doc
+	}
+	return nil
+}
+
+// directiveComments returns the directive comments in the given comment group, which its Text omits, e.g.
+// `//go:generate …`.
+func directiveComments(group *ast.CommentGroup) []string {
+	if group == nil {
+		return nil
+	}
+	var directives []string
+	for _, comment := range group.List {
+		if isDirective(comment.Text) {
+			directives = append(directives, comment.Text)
+		}
+	}
+	return directives
+}
+
+// isDirective tells whether the given comment is a directive comment, following the rules of ast.CommentGroup.Text.
+func isDirective(comment string) bool {
+	text, ok := strings.CutPrefix(comment, "//")
+	if !ok {
+		return false
+	}
+	colon := strings.Index(text, ":")
+	if colon <= 0 || colon+1 >= len(text) {
+		return false
+	}
+	for i := 0; i <= colon+1; i++ {
+		if i == colon {
+			continue
+		}
+		if b := text[i]; !('a' <= b && b <= 'z' || '0' <= b && b <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func (l loader) interfaceFor(u goinsp.Type) (*types.Interface, bool) {
+	if u, ok := u.(typeAdaptor); ok && u.loader == l {
+		iface, ok := u.t.Underlying().(*types.Interface)
+		return iface, ok
+	}
+	// u comes from another goinsp implementation (typically it's reflective.TypeFor[encoding.TextMarshaler]()),
+	// so we find the corresponding interface by name, or build it from its methods if its package can't be loaded.
+	if u.PkgPath() != "" {
+		if t, err := l.Lookup(u.PkgPath(), u.Name()); err == nil {
+			iface, ok := t.(typeAdaptor).t.Underlying().(*types.Interface)
+			return iface, ok
+		}
+	}
+	if reflected, ok := reflective.Reflected(u); ok && reflected.Kind() == reflect.Interface {
+		if iface, ok := l.reflectedType(reflected); ok {
+			return iface.Underlying().(*types.Interface), true
+		}
+	}
+	return nil, false
+}
+
+// reflectedType returns the go/types type corresponding to the given reflected type, as far as it can be built without
+// loading packages, e.g. for the signatures of the methods of json.Marshaler.
+func (l loader) reflectedType(t reflect.Type) (types.Type, bool) {
+	if t == reflect.TypeFor[error]() {
+		return types.Universe.Lookup("error").Type(), true
+	}
+	if t.Name() != "" {
+		if kind, ok := reflectedBasicKinds[t.Kind()]; ok && t.PkgPath() == "" {
+			return types.Typ[kind], true
+		}
+		named, err := l.Lookup(goinsp.ImportPath(t.PkgPath()), goinsp.TypeName(t.Name()))
+		if err != nil {
+			return nil, false
+		}
+		return named.(typeAdaptor).t, true
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		elem, ok := l.reflectedType(t.Elem())
+		return types.NewPointer(elem), ok
+	case reflect.Slice:
+		elem, ok := l.reflectedType(t.Elem())
+		return types.NewSlice(elem), ok
+	case reflect.Array:
+		elem, ok := l.reflectedType(t.Elem())
+		return types.NewArray(elem, int64(t.Len())), ok
+	case reflect.Map:
+		key, keyOK := l.reflectedType(t.Key())
+		elem, elemOK := l.reflectedType(t.Elem())
+		return types.NewMap(key, elem), keyOK && elemOK
+	case reflect.Func:
+		signature, ok := l.reflectedSignature(t)
+		return signature, ok
+	case reflect.Interface:
+		methods := make([]*types.Func, t.NumMethod())
+		for i := range methods {
+			method := t.Method(i)
+			signature, ok := l.reflectedSignature(method.Type)
+			if !ok || !method.IsExported() {
+				return nil, false
+			}
+			methods[i] = types.NewFunc(token.NoPos, nil, method.Name, signature)
+		}
+		return types.NewInterfaceType(methods, nil).Complete(), true
+	}
+	return nil, false
+}
+
+func (l loader) reflectedSignature(t reflect.Type) (*types.Signature, bool) {
+	tuple := func(n int, at func(int) reflect.Type) (*types.Tuple, bool) {
+		vars := make([]*types.Var, n)
+		for i := range vars {
+			t, ok := l.reflectedType(at(i))
+			if !ok {
+				return nil, false
+			}
+			vars[i] = types.NewParam(token.NoPos, nil, "", t)
+		}
+		return types.NewTuple(vars...), true
+	}
+	params, paramsOK := tuple(t.NumIn(), t.In)
+	results, resultsOK := tuple(t.NumOut(), t.Out)
+	if !paramsOK || !resultsOK {
+		return nil, false
+	}
+	return types.NewSignatureType(nil, nil, nil, params, results, t.IsVariadic()), true
+}
+
+var reflectedBasicKinds = map[reflect.Kind]types.BasicKind{
+	reflect.Bool: types.Bool, reflect.Int: types.Int, reflect.Int8: types.Int8, reflect.Int16: types.Int16,
+	reflect.Int32: types.Int32, reflect.Int64: types.Int64, reflect.Uint: types.Uint, reflect.Uint8: types.Uint8,
+	reflect.Uint16: types.Uint16, reflect.Uint32: types.Uint32, reflect.Uint64: types.Uint64,
+	reflect.Uintptr: types.Uintptr, reflect.Float32: types.Float32, reflect.Float64: types.Float64,
+	reflect.Complex64: types.Complex64, reflect.Complex128: types.Complex128, reflect.String: types.String,
+	reflect.UnsafePointer: types.UnsafePointer,
+}
+
+func fieldCommentGroup(field *ast.Field) *ast.CommentGroup {
+	if field.Doc != nil {
+		return field.Doc
+	}
+	return field.Comment
+}
+
+// embeddedTypeIdent returns the identifier of the name of the embedded type of a field, e.g. `User` for `*api.User`.
+// Other expressions only occur in packages with syntax errors, which Load reports.
+func embeddedTypeIdent(expr ast.Expr) (*ast.Ident, bool) {
+	for {
+		switch typed := expr.(type) {
+		case *ast.Ident:
+			return typed, true
+		case *ast.StarExpr:
+			expr = typed.X
+		case *ast.SelectorExpr:
+			expr = typed.Sel
+		case *ast.IndexExpr:
+			expr = typed.X
+		case *ast.IndexListExpr:
+			expr = typed.X
+		case *ast.ParenExpr:
+			expr = typed.X
+		default:
+			return nil, false
+		}
+	}
+}
@@ -0,0 +1,6 @@
+// Package static provides an implementation of goinsp.Type in terms of go/types,
+// loading Go source with golang.org/x/tools/go/packages rather than relying on reflection.
+//
+// This makes it possible to inspect types from packages that can't be imported by the program doing the inspecting,
+// e.g. main packages, packages behind build tags, or packages whose initialisation has side effects.
+package static
@@ -0,0 +1,180 @@
+package static
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+)
+
+var testLoader = NewLoader()
+
+const thisPackage = goinsp.ImportPath("github.com/softwaretechnik-berlin/goats/gotypes/goinsp/static")
+
+func TestAgreesWithReflection(t *testing.T) {
+	static, err := testLoader.Lookup(thisPackage, "commentedStruct")
+	require.NoError(t, err)
+	assertAgree(t, reflective.TypeFor[commentedStruct](), static, 2)
+}
+
+func assertAgree(t *testing.T, reflected goinsp.Type, static goinsp.Type, depth int) {
+	assert.Equal(t, reflected.Name(), static.Name())
+	assert.Equal(t, reflected.PkgPath(), static.PkgPath())
+	assert.Equal(t, reflected.String(), static.String())
+	assert.Equal(t, reflected.Kind(), static.Kind())
+	assert.Equal(t, reflected.Implements(reflective.TypeFor[encoding.TextMarshaler]()), static.Implements(reflective.TypeFor[encoding.TextMarshaler]()), "%s implements TextMarshaler", reflected)
+	assert.Equal(t, reflected.Implements(reflective.TypeFor[json.Marshaler]()), static.Implements(reflective.TypeFor[json.Marshaler]()), "%s implements json.Marshaler", reflected)
+	assert.Equal(t, reflected.WithoutTypeArguments() == reflected, static.WithoutTypeArguments() == static, "%s has type arguments", reflected)
+	if depth == 0 {
+		return
+	}
+	switch reflected.Kind() {
+	case reflect.Array:
+		assert.Equal(t, reflected.Len(), static.Len())
+		assertAgree(t, reflected.Elem(), static.Elem(), depth-1)
+	case reflect.Map:
+		assertAgree(t, reflected.Key(), static.Key(), depth-1)
+		assertAgree(t, reflected.Elem(), static.Elem(), depth-1)
+	case reflect.Pointer, reflect.Slice:
+		assertAgree(t, reflected.Elem(), static.Elem(), depth-1)
+	case reflect.Struct:
+		if assert.Equal(t, reflected.NumField(), static.NumField()) {
+			for i := range reflected.NumField() {
+				reflectedField, staticField := reflected.Field(i), static.Field(i)
+				assert.Equal(t, reflectedField.Name, staticField.Name)
+				assert.Equal(t, reflectedField.Tag, staticField.Tag)
+				assert.Equal(t, reflectedField.Anonymous, staticField.Anonymous)
+				assert.Equal(t, reflectedField.IsExported(), staticField.IsExported())
+				assertAgree(t, reflectedField.Type(), staticField.Type(), depth-1)
+			}
+		}
+	}
+}
+
+func TestTypesAreComparable(t *testing.T) {
+	static, err := testLoader.Lookup(thisPackage, "commentedStruct")
+	require.NoError(t, err)
+	again, err := testLoader.Lookup(thisPackage, "commentedStruct")
+	require.NoError(t, err)
+	assert.Equal(t, static, again)
+	assert.True(t, static == again)
+
+	// The pointer field refers back to the struct itself.
+	assert.True(t, static == static.Field(2).Type().Elem())
+	// Instantiated generics share their generic type.
+	instantiated := static.Field(10).Type()
+	assert.Equal(t, goinsp.TypeName("genericStruct"), instantiated.WithoutTypeArguments().Name())
+	generic, err := testLoader.Lookup(thisPackage, "genericStruct")
+	require.NoError(t, err)
+	assert.True(t, generic == instantiated.WithoutTypeArguments())
+}
+
+func TestTypeArguments(t *testing.T) {
+	static, err := testLoader.Lookup(thisPackage, "commentedStruct")
+	require.NoError(t, err)
+	assert.Equal(t, goinsp.PotentiallyUnavailable[[]goinsp.Type]{Available: true}, static.TypeArguments())
+
+	instantiated := static.Field(10).Type()
+	arguments := instantiated.TypeArguments()
+	require.True(t, arguments.Available)
+	assert.Equal(t, []goinsp.Type{static, static.Field(1).Type()}, arguments.Value)
+
+	generic := instantiated.WithoutTypeArguments().(goinsp.Type)
+	parameters := generic.TypeArguments()
+	require.True(t, parameters.Available)
+	require.Len(t, parameters.Value, 2)
+	a, b := parameters.Value[0], parameters.Value[1]
+	assert.True(t, a.IsTypeParameter())
+	assert.Equal(t, goinsp.TypeName("A"), a.Name())
+	assert.Equal(t, goinsp.TypeName("B"), b.Name())
+	assert.False(t, instantiated.IsTypeParameter())
+	// The fields of the generic type refer to its type parameters.
+	assert.True(t, a == generic.Field(0).Type().Elem())
+	assert.True(t, b == generic.Field(1).Type())
+
+	assert.False(t, reflective.TypeFor[genericStruct[int, int]]().TypeArguments().Available)
+	assert.True(t, reflective.TypeFor[commentedStruct]().TypeArguments().Available)
+}
+
+func TestImplementsUsesMethodSets(t *testing.T) {
+	textMarshaler := reflective.TypeFor[encoding.TextMarshaler]()
+	valueMarshaler, err := testLoader.Lookup(thisPackage, "valueTextMarshaler")
+	require.NoError(t, err)
+	pointerMarshaler, err := testLoader.Lookup(thisPackage, "pointerTextMarshaler")
+	require.NoError(t, err)
+
+	assert.True(t, valueMarshaler.Implements(textMarshaler))
+	assert.False(t, pointerMarshaler.Implements(textMarshaler))
+	assert.Equal(t, reflective.TypeFor[pointerTextMarshaler]().Implements(textMarshaler), pointerMarshaler.Implements(textMarshaler))
+}
+
+func TestImplementsBuildsUnnamedInterfacesOfReflectedTypes(t *testing.T) {
+	valueMarshaler, err := testLoader.Lookup(thisPackage, "valueTextMarshaler")
+	require.NoError(t, err)
+
+	assert.True(t, valueMarshaler.Implements(reflective.TypeFor[interface{ MarshalText() ([]byte, error) }]()))
+	assert.False(t, valueMarshaler.Implements(reflective.TypeFor[interface{ MarshalText() (string, error) }]()))
+	assert.False(t, valueMarshaler.Implements(reflective.TypeFor[interface{ MarshalJSON() ([]byte, error) }]()))
+}
+
+func TestAddressableValuesHaveTheMethodsOfPointers(t *testing.T) {
+	textMarshaler := reflective.TypeFor[encoding.TextMarshaler]()
+	pointerMarshaler, err := testLoader.Lookup(thisPackage, "pointerTextMarshaler")
+	require.NoError(t, err)
+
+	assert.True(t, pointerMarshaler.PointerTo().Implements(textMarshaler))
+	assert.Equal(t, "*static.pointerTextMarshaler", pointerMarshaler.PointerTo().String())
+	assert.True(t, goinsp.Addressable.Implements(pointerMarshaler, textMarshaler))
+	assert.False(t, goinsp.MaybeAddressable.Implements(pointerMarshaler, textMarshaler))
+	assert.False(t, goinsp.Unaddressable.Implements(pointerMarshaler, textMarshaler))
+	assert.True(t, goinsp.Addressable.Implements(reflective.TypeFor[pointerTextMarshaler](), textMarshaler))
+
+	slice := reflective.TypeFor[[]pointerTextMarshaler]()
+	assert.Equal(t, goinsp.Addressable, goinsp.Unaddressable.Elem(slice))
+	assert.Equal(t, goinsp.Unaddressable, goinsp.Addressable.Elem(reflective.TypeFor[map[string]pointerTextMarshaler]()))
+	assert.Equal(t, goinsp.MaybeAddressable, goinsp.MaybeAddressable.Elem(reflective.TypeFor[[1]pointerTextMarshaler]()))
+}
+
+func TestComments(t *testing.T) {
+	static, err := testLoader.Lookup(thisPackage, "commentedStruct")
+	require.NoError(t, err)
+	assert.Equal(t, goinsp.PotentiallyUnavailable[goinsp.NoneWhenZero[string]]{Available: true, Value: goinsp.NoneWhenZero[string]{Value: `commentedStruct is a test case for this package.
+It has a multiline comment.
+`}}, static.Comment())
+	assert.Equal(t, "embeddedStruct is documented inside its group.\n", static.Field(11).Type().Comment().Value.Value)
+	assert.Equal(t, goinsp.PotentiallyUnavailable[goinsp.NoneWhenZero[string]]{Available: true}, static.Field(12).Type().Elem().Comment())
+	assert.Equal(t, goinsp.PotentiallyUnavailable[goinsp.NoneWhenZero[string]]{Available: true}, static.Field(1).Type().Comment())
+
+	assert.Equal(t, "Exported is documented.\n", static.Field(0).Comment().Value.Value)
+	assert.Equal(t, "unexported has a line comment.\n", static.Field(1).Comment().Value.Value)
+	assert.Equal(t, goinsp.PotentiallyUnavailable[goinsp.NoneWhenZero[string]]{Available: true}, static.Field(2).Comment())
+	generic := static.Field(10).Type()
+	assert.Equal(t, "As are documented on the generic type.\n", generic.Field(0).Comment().Value.Value)
+}
+
+func TestMainPackage(t *testing.T) {
+	command, err := testLoader.Lookup(thisPackage+"/testdata/mainpkg", "Command")
+	require.NoError(t, err)
+	assert.Equal(t, "main.Command", command.String())
+	assert.Equal(t, "Command is something a main package might want to describe to a client.\n", command.Comment().Value.Value)
+
+	types, err := testLoader.PackageTypes(thisPackage + "/testdata/mainpkg")
+	require.NoError(t, err)
+	assert.Equal(t, []goinsp.Type{command}, types)
+}
+
+func TestDirectivesAreOmittedFromCommentsButAvailableSeparately(t *testing.T) {
+	directed, err := testLoader.Lookup(thisPackage, "directedString")
+	require.NoError(t, err)
+	assert.Equal(t, "directedString is documented along with directives.\n", directed.Comment().Value.Value)
+	assert.Equal(t, goinsp.PotentiallyUnavailable[[]string]{Available: true, Value: []string{"//gotypes:schema z.string()"}}, directed.Directives(""))
+	assert.Equal(t, goinsp.PotentiallyUnavailable[[]string]{Available: true, Value: []string{"//gotypes:transform s => s.length"}}, directed.Directives("MarshalText"))
+	assert.Equal(t, goinsp.PotentiallyUnavailable[[]string]{Available: true}, directed.Directives("String"))
+	assert.Equal(t, goinsp.PotentiallyUnavailable[[]string]{Available: true}, directed.Directives("MarshalJSON"))
+}
@@ -0,0 +1,25 @@
+package static
+
+import (
+	"go/types"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
+)
+
+type fieldAdaptor struct {
+	loader loader
+	field  *types.Var
+}
+
+func (f fieldAdaptor) IsExported() bool {
+	return f.field.Exported()
+}
+
+func (f fieldAdaptor) Type() goinsp.Type {
+	return f.loader.Adapt(f.field.Type())
+}
+
+func (f fieldAdaptor) Comment() goinsp.PotentiallyUnavailable[goinsp.NoneWhenZero[string]] {
+	comment, ok := f.loader.fieldComments[f.field.Origin()]
+	return goinsp.PotentiallyUnavailable[goinsp.NoneWhenZero[string]]{Available: ok, Value: goinsp.NoneWhenZero[string]{Value: comment}}
+}
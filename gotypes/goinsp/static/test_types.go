@@ -0,0 +1,57 @@
+package static
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// commentedStruct is a test case for this package.
+// It has a multiline comment.
+type commentedStruct struct {
+	// Exported is documented.
+	Exported     string `json:"exported,omitempty"`
+	unexported   int    // unexported has a line comment.
+	Pointer      *commentedStruct
+	Slice        []byte
+	Array        [3]rune
+	Map          map[string]float64
+	Interface    any
+	Time         time.Time
+	RawMessage   json.RawMessage
+	Anonymous    struct{ A, B bool }
+	Instantiated genericStruct[commentedStruct, int]
+	embeddedStruct
+	*embeddedPointer
+}
+
+type (
+	// embeddedStruct is documented inside its group.
+	embeddedStruct  struct{ E uint16 }
+	embeddedPointer struct{ P uintptr }
+)
+
+type genericStruct[A, B any] struct {
+	// As are documented on the generic type.
+	As []A
+	B  B
+}
+
+type valueTextMarshaler string
+
+func (valueTextMarshaler) MarshalText() ([]byte, error) { return nil, nil }
+
+type pointerTextMarshaler string
+
+func (*pointerTextMarshaler) MarshalText() ([]byte, error) { return nil, nil }
+
+// directedString is documented along with directives.
+//
+//gotypes:schema z.string()
+type directedString string
+
+// MarshalText has a directive too.
+//
+//gotypes:transform s => s.length
+func (directedString) MarshalText() ([]byte, error) { return nil, nil }
+
+func (directedString) String() string { return "" }
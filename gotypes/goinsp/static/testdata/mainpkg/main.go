@@ -0,0 +1,9 @@
+// This package can't be imported, but its types can still be inspected statically.
+package main
+
+// Command is something a main package might want to describe to a client.
+type Command struct {
+	Name string `json:"name"`
+}
+
+func main() {}
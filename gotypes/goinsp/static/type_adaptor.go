@@ -0,0 +1,220 @@
+package static
+
+import (
+	"fmt"
+	"go/types"
+	"reflect"
+	"strings"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
+)
+
+type typeAdaptor struct {
+	loader loader
+	t      types.Type
+}
+
+func (t typeAdaptor) PkgPath() goinsp.ImportPath {
+	if obj := t.obj(); obj != nil && obj.Pkg() != nil {
+		return goinsp.ImportPath(obj.Pkg().Path())
+	}
+	return ""
+}
+
+func (t typeAdaptor) Comment() goinsp.PotentiallyUnavailable[goinsp.NoneWhenZero[string]] {
+	obj := t.obj()
+	if obj == nil {
+		return goinsp.PotentiallyUnavailable[goinsp.NoneWhenZero[string]]{Available: true}
+	}
+	if named, ok := t.t.(*types.Named); ok {
+		obj = named.Origin().Obj()
+	}
+	comment, ok := t.loader.typeComments[obj]
+	return goinsp.PotentiallyUnavailable[goinsp.NoneWhenZero[string]]{Available: ok, Value: goinsp.NoneWhenZero[string]{Value: comment}}
+}
+
+func (t typeAdaptor) Directives(method string) goinsp.PotentiallyUnavailable[[]string] {
+	named, ok := t.t.(*types.Named)
+	if !ok {
+		return goinsp.PotentiallyUnavailable[[]string]{Available: true}
+	}
+	named = named.Origin()
+	if _, ok := t.loader.typeComments[named.Obj()]; !ok {
+		// The package declaring the type hasn't been parsed.
+		return goinsp.PotentiallyUnavailable[[]string]{}
+	}
+	if method == "" {
+		return goinsp.PotentiallyUnavailable[[]string]{Available: true, Value: t.loader.typeDirectives[named.Obj()]}
+	}
+	for i := range named.NumMethods() {
+		if m := named.Method(i); m.Name() == method {
+			return goinsp.PotentiallyUnavailable[[]string]{Available: true, Value: t.loader.methodDirectives[m]}
+		}
+	}
+	return goinsp.PotentiallyUnavailable[[]string]{Available: true}
+}
+
+func (t typeAdaptor) Name() goinsp.TypeName {
+	switch typed := t.t.(type) {
+	case *types.Basic:
+		return goinsp.TypeName(typed.Name())
+	case *types.Named:
+		// Like reflection, this includes type arguments qualified by import path, e.g. `Page[example.com/api.User]`.
+		if _, typeArgs, ok := strings.Cut(typeString(typed, qualifyByPath), "["); ok {
+			return goinsp.TypeName(typed.Obj().Name() + "[" + typeArgs)
+		}
+		return goinsp.TypeName(typed.Obj().Name())
+	case *types.TypeParam:
+		return goinsp.TypeName(typed.Obj().Name())
+	default:
+		return ""
+	}
+}
+
+func (t typeAdaptor) String() string {
+	return typeString(t.t, qualifyByName)
+}
+
+// Implements panics like reflect.Type's Implements if u isn't an interface type, which includes interfaces of other
+// goinsp implementations whose packages can't be loaded and whose methods can't be built from reflection either.
+func (t typeAdaptor) Implements(u goinsp.Type) bool {
+	iface, ok := t.loader.interfaceFor(u)
+	if !ok {
+		panic(fmt.Sprintf("static: non-interface type %s passed to Type.Implements", u))
+	}
+	return types.Implements(t.t, iface)
+}
+
+func (t typeAdaptor) PointerTo() goinsp.Type {
+	return t.loader.Adapt(types.NewPointer(t.t))
+}
+
+func (t typeAdaptor) Kind() reflect.Kind {
+	switch underlying := t.t.Underlying().(type) {
+	case *types.Basic:
+		return basicKinds[underlying.Kind()]
+	case *types.Array:
+		return reflect.Array
+	case *types.Chan:
+		return reflect.Chan
+	case *types.Signature:
+		return reflect.Func
+	case *types.Interface:
+		return reflect.Interface
+	case *types.Map:
+		return reflect.Map
+	case *types.Pointer:
+		return reflect.Pointer
+	case *types.Slice:
+		return reflect.Slice
+	case *types.Struct:
+		return reflect.Struct
+	default:
+		panic(fmt.Sprintf("unexpected underlying type %#v of %s", underlying, t.t))
+	}
+}
+
+var basicKinds = map[types.BasicKind]reflect.Kind{
+	types.Bool:          reflect.Bool,
+	types.Int:           reflect.Int,
+	types.Int8:          reflect.Int8,
+	types.Int16:         reflect.Int16,
+	types.Int32:         reflect.Int32,
+	types.Int64:         reflect.Int64,
+	types.Uint:          reflect.Uint,
+	types.Uint8:         reflect.Uint8,
+	types.Uint16:        reflect.Uint16,
+	types.Uint32:        reflect.Uint32,
+	types.Uint64:        reflect.Uint64,
+	types.Uintptr:       reflect.Uintptr,
+	types.Float32:       reflect.Float32,
+	types.Float64:       reflect.Float64,
+	types.Complex64:     reflect.Complex64,
+	types.Complex128:    reflect.Complex128,
+	types.String:        reflect.String,
+	types.UnsafePointer: reflect.UnsafePointer,
+}
+
+func (t typeAdaptor) Len() uint {
+	return uint(t.t.Underlying().(*types.Array).Len())
+}
+
+func (t typeAdaptor) NumField() int {
+	return t.t.Underlying().(*types.Struct).NumFields()
+}
+
+func (t typeAdaptor) Key() goinsp.Type {
+	return t.loader.Adapt(t.t.Underlying().(*types.Map).Key())
+}
+
+func (t typeAdaptor) Elem() goinsp.Type {
+	switch underlying := t.t.Underlying().(type) {
+	case *types.Array:
+		return t.loader.Adapt(underlying.Elem())
+	case *types.Chan:
+		return t.loader.Adapt(underlying.Elem())
+	case *types.Map:
+		return t.loader.Adapt(underlying.Elem())
+	case *types.Pointer:
+		return t.loader.Adapt(underlying.Elem())
+	case *types.Slice:
+		return t.loader.Adapt(underlying.Elem())
+	default:
+		panic(fmt.Sprintf("Elem of non-element type %s", t.t))
+	}
+}
+
+func (t typeAdaptor) Field(i int) goinsp.StructField {
+	s := t.t.Underlying().(*types.Struct)
+	field := s.Field(i)
+	return goinsp.NewStructField(field.Name(), reflect.StructTag(s.Tag(i)), field.Embedded(), fieldAdaptor{t.loader, field})
+}
+
+func (t typeAdaptor) WithoutTypeArguments() goinsp.GenType {
+	if named, ok := t.t.(*types.Named); ok && named.TypeArgs().Len() > 0 {
+		return t.loader.Adapt(named.Origin())
+	}
+	switch t.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice:
+		// Like reflection, we treat arrays, maps and slices as generic in their element types.
+		return newTypeConstructor(t)
+	}
+	return t
+}
+
+func (t typeAdaptor) TypeArguments() goinsp.PotentiallyUnavailable[[]goinsp.Type] {
+	var arguments []goinsp.Type
+	if named, ok := t.t.(*types.Named); ok {
+		if args := named.TypeArgs(); args.Len() > 0 {
+			for i := range args.Len() {
+				arguments = append(arguments, t.loader.Adapt(args.At(i)))
+			}
+		} else {
+			params := named.TypeParams()
+			for i := range params.Len() {
+				arguments = append(arguments, t.loader.Adapt(params.At(i)))
+			}
+		}
+	}
+	return goinsp.PotentiallyUnavailable[[]goinsp.Type]{Available: true, Value: arguments}
+}
+
+func (t typeAdaptor) IsTypeParameter() bool {
+	_, ok := t.t.(*types.TypeParam)
+	return ok
+}
+
+func (t typeAdaptor) obj() *types.TypeName {
+	if named, ok := t.t.(*types.Named); ok {
+		return named.Obj()
+	}
+	return nil
+}
+
+func qualifyByPath(pkg *types.Package) string {
+	return pkg.Path()
+}
+
+func qualifyByName(pkg *types.Package) string {
+	return pkg.Name()
+}
@@ -0,0 +1,72 @@
+package static
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
+)
+
+// typeConstructor represents arrays, maps and slices without their element types,
+// mirroring what the reflective implementation returns from WithoutTypeArguments.
+type typeConstructor struct {
+	name    goinsp.TypeName
+	pkgPath goinsp.ImportPath
+	string  string
+	kind    reflect.Kind
+	len     uint
+}
+
+func (t typeConstructor) Name() goinsp.TypeName {
+	return t.name
+}
+
+func (t typeConstructor) PkgPath() goinsp.ImportPath {
+	return t.pkgPath
+}
+
+func (t typeConstructor) String() string {
+	return t.string
+}
+
+func (t typeConstructor) Kind() reflect.Kind {
+	return t.kind
+}
+
+func (t typeConstructor) Len() uint {
+	if t.kind != reflect.Array {
+		panic(t)
+	}
+	return t.len
+}
+
+func (t typeConstructor) NumField() int {
+	panic(t)
+}
+
+func (t typeConstructor) WithoutTypeArguments() goinsp.GenType {
+	return t
+}
+
+func (t typeConstructor) Comment() goinsp.PotentiallyUnavailable[goinsp.NoneWhenZero[string]] {
+	return goinsp.PotentiallyUnavailable[goinsp.NoneWhenZero[string]]{}
+}
+
+func newTypeConstructor(t typeAdaptor) typeConstructor {
+	gen := typeConstructor{
+		upToOpeningBrace(t.Name()),
+		t.PkgPath(),
+		upToOpeningBrace(t.String()),
+		t.Kind(),
+		0,
+	}
+	if gen.kind == reflect.Array {
+		gen.len = t.Len()
+	}
+	return gen
+}
+
+func upToOpeningBrace[S ~string](s S) S {
+	prefix, _, _ := strings.Cut(string(s), "[")
+	return S(prefix)
+}
@@ -0,0 +1,138 @@
+package static
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// typeString formats types the way reflection does, which differs from go/types' formatting in details such as
+// spacing and the names it uses for byte and rune.
+func typeString(t types.Type, qualify func(*types.Package) string) string {
+	var b strings.Builder
+	writeType(&b, t, qualify)
+	return b.String()
+}
+
+func writeType(b *strings.Builder, t types.Type, qualify func(*types.Package) string) {
+	switch t := types.Unalias(t).(type) {
+	case *types.Basic:
+		b.WriteString(types.Typ[t.Kind()].Name())
+	case *types.Named:
+		if pkg := t.Obj().Pkg(); pkg != nil {
+			b.WriteString(qualify(pkg))
+			b.WriteByte('.')
+		}
+		b.WriteString(t.Obj().Name())
+		if args := t.TypeArgs(); args.Len() > 0 {
+			b.WriteByte('[')
+			for i := range args.Len() {
+				if i > 0 {
+					b.WriteByte(',')
+				}
+				writeType(b, args.At(i), qualifyByPath)
+			}
+			b.WriteByte(']')
+		}
+	case *types.TypeParam:
+		b.WriteString(t.Obj().Name())
+	case *types.Pointer:
+		b.WriteByte('*')
+		writeType(b, t.Elem(), qualify)
+	case *types.Slice:
+		b.WriteString("[]")
+		writeType(b, t.Elem(), qualify)
+	case *types.Array:
+		fmt.Fprintf(b, "[%d]", t.Len())
+		writeType(b, t.Elem(), qualify)
+	case *types.Map:
+		b.WriteString("map[")
+		writeType(b, t.Key(), qualify)
+		b.WriteByte(']')
+		writeType(b, t.Elem(), qualify)
+	case *types.Chan:
+		switch t.Dir() {
+		case types.SendRecv:
+			b.WriteString("chan ")
+		case types.SendOnly:
+			b.WriteString("chan<- ")
+		case types.RecvOnly:
+			b.WriteString("<-chan ")
+		}
+		writeType(b, t.Elem(), qualify)
+	case *types.Signature:
+		b.WriteString("func")
+		writeSignature(b, t, qualify)
+	case *types.Struct:
+		if t.NumFields() == 0 {
+			b.WriteString("struct {}")
+			return
+		}
+		b.WriteString("struct {")
+		for i := range t.NumFields() {
+			if i > 0 {
+				b.WriteByte(';')
+			}
+			b.WriteByte(' ')
+			field := t.Field(i)
+			if !field.Embedded() {
+				b.WriteString(field.Name())
+				b.WriteByte(' ')
+			}
+			writeType(b, field.Type(), qualify)
+			if tag := t.Tag(i); tag != "" {
+				fmt.Fprintf(b, " %q", tag)
+			}
+		}
+		b.WriteString(" }")
+	case *types.Interface:
+		if t.NumMethods() == 0 {
+			b.WriteString("interface {}")
+			return
+		}
+		b.WriteString("interface {")
+		for i := range t.NumMethods() {
+			if i > 0 {
+				b.WriteByte(';')
+			}
+			b.WriteByte(' ')
+			method := t.Method(i)
+			b.WriteString(method.Name())
+			writeSignature(b, method.Type().(*types.Signature), qualify)
+		}
+		b.WriteString(" }")
+	default:
+		b.WriteString(types.TypeString(t, qualify))
+	}
+}
+
+func writeSignature(b *strings.Builder, s *types.Signature, qualify func(*types.Package) string) {
+	b.WriteByte('(')
+	for i := range s.Params().Len() {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		param := s.Params().At(i).Type()
+		if s.Variadic() && i == s.Params().Len()-1 {
+			b.WriteString("...")
+			param = param.(*types.Slice).Elem()
+		}
+		writeType(b, param, qualify)
+	}
+	b.WriteByte(')')
+	switch s.Results().Len() {
+	case 0:
+	case 1:
+		b.WriteByte(' ')
+		writeType(b, s.Results().At(0).Type(), qualify)
+	default:
+		b.WriteString(" (")
+		for i := range s.Results().Len() {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeType(b, s.Results().At(i).Type(), qualify)
+		}
+		b.WriteByte(')')
+	}
+}
@@ -26,6 +26,11 @@ func (f StructField) Type() Type {
 	return f.impl.Type()
 }
 
+// Comment returns the doc comment of the field, if the implementation has access to it.
+func (f StructField) Comment() PotentiallyUnavailable[NoneWhenZero[string]] {
+	return f.impl.Comment()
+}
+
 func NewStructField(name string, tag reflect.StructTag, anonymous bool, impl StructFieldImpl) StructField {
 	return StructField{name, tag, anonymous, impl}
 }
@@ -33,4 +38,5 @@ func NewStructField(name string, tag reflect.StructTag, anonymous bool, impl Str
 type StructFieldImpl interface {
 	IsExported() bool
 	Type() Type
+	Comment() PotentiallyUnavailable[NoneWhenZero[string]]
 }
@@ -46,4 +46,21 @@ type Type interface {
 	reflectTypeInterface[TypeName, ImportPath, uint, StructField, Type]
 	//PackageName() PotentiallyUnavailable[NoneWhenZero[PackageName]]
 	Comment() PotentiallyUnavailable[NoneWhenZero[string]]
+	// Directives returns the directive comments, e.g. `//gotypes:schema z.string()`, which Comment omits, in the doc
+	// comment of the type, or in that of the method with the given name declared on it if method isn't empty, if the
+	// implementation has access to them.
+	Directives(method string) PotentiallyUnavailable[[]string]
+
+	// TypeArguments returns the type arguments of an instantiated generic type, e.g. `User` for `Page[User]`.
+	// For a generic type that hasn't been instantiated, it returns its type parameters, e.g. `T` for `Page[T any]`.
+	// Other types have no type arguments.
+	//
+	// Reflection can't provide the type arguments of instantiated generic types.
+	TypeArguments() PotentiallyUnavailable[[]Type]
+	// IsTypeParameter tells whether the type is a type parameter, which occurs in generic types that haven't been
+	// instantiated.
+	IsTypeParameter() bool
+	// PointerTo returns the type of pointers to the type, whose method set also includes the methods with pointer
+	// receivers.
+	PointerTo() Type
 }
@@ -0,0 +1,73 @@
+package gozod
+
+import (
+	"encoding"
+	"encoding/json"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+)
+
+// marshaller is the method encoding/json marshals values with.
+type marshaller uint8
+
+const (
+	noMarshaller marshaller = iota
+	jsonMarshaller
+	textMarshaller
+)
+
+// marshallerOf returns the method encoding/json marshals the values of type t with addressability a with.
+func marshallerOf(t goinsp.Type, a goinsp.Addressability) marshaller {
+	switch {
+	case a.Implements(t, reflective.TypeFor[json.Marshaler]()):
+		return jsonMarshaller
+	case a.Implements(t, reflective.TypeFor[encoding.TextMarshaler]()):
+		return textMarshaller
+	default:
+		return noMarshaller
+	}
+}
+
+// marshalsThroughPointer tells whether encoding/json marshals the addressable values of type t with a method with a
+// pointer receiver, which it doesn't use for unaddressable values, so that their JSON depends on their addressability.
+//
+// The declared schema of t is that of its unaddressable values, i.e. of the values passed to json.Marshal directly.
+func (b nodeBuilder) marshalsThroughPointer(t goinsp.Type) bool {
+	if marshallerOf(t, goinsp.Addressable) == marshallerOf(t, goinsp.Unaddressable) {
+		return false
+	}
+	// Configured schemas apply to all values.
+	if b.configuresSchema(t) {
+		return false
+	}
+	if _, ok := lookupConfig(b.jsonSamples, t); ok {
+		return false
+	}
+	// So do the directives given on the type itself, but those of the methods with pointer receivers only apply to
+	// addressable values.
+	value, addressable := b.directives(t), b.directivesAt(t, goinsp.Addressable)
+	_, templated := b.template(t, value)
+	return value != addressable || !templated && value.schema == ""
+}
+
+// resolveAt resolves the schema of the values of type t with addressability a.
+func (b nodeBuilder) resolveAt(t goinsp.Type, a goinsp.Addressability, nodes Resolver[goinsp.Type, node]) node {
+	if a == goinsp.Unaddressable || !b.marshalsThroughPointer(t) {
+		return nodes.Resolve(t)
+	}
+	addressable := b.addressableSchema(t, nodes)
+	if a == goinsp.Addressable {
+		return addressable
+	}
+	// Depending on whether encoding/json can take the address of the struct or array containing the value, it uses the
+	// method with the pointer receiver or not.
+	return unionNode{members: []node{addressable, nodes.Resolve(t)}}
+}
+
+// addressableSchema returns the schema of the addressable values of type t, which encoding/json marshals with a method
+// with a pointer receiver, built like the declared schema of t but with the directives of that method.
+func (b nodeBuilder) addressableSchema(t goinsp.Type, nodes Resolver[goinsp.Type, node]) node {
+	directives := b.directivesAt(t, goinsp.Addressable)
+	return b.templated(t, directives, b.transformed(t, directives, b.buildRawSchema(t, goinsp.Addressable, directives, nodes)))
+}
@@ -0,0 +1,145 @@
+package gozod
+
+import (
+	"fmt"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
+	"github.com/softwaretechnik-berlin/goats/gotypes/ts"
+)
+
+// backend renders the nodes the nodeBuilder builds as schemas S of a library, e.g. zod.ZodTypes or jsonschema.Schemas,
+// and declares them as Ds.
+type backend[S, D any] interface {
+	library
+	// render renders the given node, resolving the nodes of the Go types its transforms refer to with the given
+	// resolver.
+	render(n node, nodes Resolver[goinsp.Type, node]) S
+	// reference returns the schema referring to the given schema by the name it is declared under.
+	reference(name ts.Identifier, schema S) S
+	declare(d declared[S]) D
+}
+
+// library is the validator library of a backend, in which the schemas of Go types can be hand-written, e.g. with
+// WithSchema for zod.
+type library interface {
+	// name is the name of the library in problems, e.g. "JSON Schema".
+	name() string
+	// option is the option stating the library's hand-written schemas, which problems suggest, e.g. "WithJSONSchema".
+	option() string
+	// configures tells whether a hand-written schema of the library is configured for t.
+	configures(t goinsp.Type) bool
+	// handWritten returns the hand-written schema of the library configured for t, if any, resolving the Go types it
+	// refers to with the given resolver.
+	handWritten(t goinsp.Type, nodes Resolver[goinsp.Type, node]) (node, bool)
+	// zodExpression returns the schema of the given hand-written zod expression, e.g. of a gotypes:schema directive,
+	// if the library has one.
+	zodExpression(expr string) (node, bool)
+}
+
+// declared is a schema to be declared by a backend, along with the node it renders.
+type declared[S any] struct {
+	comment string
+	name    ts.Identifier
+	// typeParameters are those of a factory, or of a generic type where generic types are declared, which is declared
+	// instead of a schema if there are any.
+	typeParameters []ts.Identifier
+	node           node
+	schema         S
+}
+
+// typeBuilder builds the schemas of a backend with the nodeBuilder, so that all backends follow the same options, tags
+// and directives, and declares them with the same names, comments and order.
+type typeBuilder[S, D any] struct {
+	nodeBuilder
+	backend backend[S, D]
+	// nodes holds the nodes of the types that have been built, by which the schemas referring to them are built.
+	nodes map[goinsp.Type]node
+}
+
+func newBackendMapper[S any, D declaration[ts.Identifier, D]](b nodeBuilder, backend backend[S, D]) mapper[goinsp.Type, S, ts.Identifier, D] {
+	b.library = backend
+	return newMapper[goinsp.Type, S, ts.Identifier, D](typeBuilder[S, D]{b, backend, make(map[goinsp.Type]node)})
+}
+
+func (b typeBuilder[S, D]) Lazy(name ts.Identifier) S {
+	return b.backend.render(lazyNode{name}, nil)
+}
+
+func (b typeBuilder[S, D]) Invalid(problem error) S {
+	b.report(problem)
+	return b.backend.render(unknownNode{}, nil)
+}
+
+func (b typeBuilder[S, D]) Build(t goinsp.Type, resolver Resolver[goinsp.Type, S]) (S, D, bool) {
+	if len(*b.path) == 0 {
+		// t is being resolved itself, rather than as part of another type, so the paths of its problems start at it.
+		*b.path = append(*b.path, t.String())
+		defer func() { *b.path = (*b.path)[:0] }()
+	}
+	// A node left over from an earlier build mustn't be taken for that of t while t is being built.
+	delete(b.nodes, t)
+	nodes := nodeResolver[S]{b.nodes, resolver, b.nodeBuilder}
+	n, name, named, typeParameters := b.build(t, nodes)
+	if len(typeParameters) > 0 {
+		// Factories aren't schemas, so other schemas can only invoke them.
+		b.nodes[t] = factoryNode{name}
+	} else {
+		b.nodes[t] = n
+	}
+	schema := b.backend.render(n, nodes)
+	var declaration D
+	if !named {
+		return schema, declaration, false
+	}
+	declaration = b.backend.declare(declared[S]{b.docComment(name, t), name, typeParameters, n, schema})
+	if len(typeParameters) > 0 {
+		return b.backend.render(factoryNode{name}, nodes), declaration, true
+	}
+	return b.backend.reference(name, schema), declaration, true
+}
+
+// nodeResolver resolves the nodes of Go types with the resolver of the backend's schemas, so that the mapper keeps
+// track of the declarations they refer to. The nodes of declared types refer to their declarations.
+type nodeResolver[S any] struct {
+	nodes    map[goinsp.Type]node
+	resolver Resolver[goinsp.Type, S]
+	builder  nodeBuilder
+}
+
+func (r nodeResolver[S]) Resolve(t goinsp.Type) node {
+	schema := r.resolver.Resolve(t)
+	t = r.builder.Canonical(t)
+	name, named := r.builder.name(t)
+	n, ok := r.nodes[t]
+	switch {
+	case ok && !named:
+		return n
+	case !named:
+		// The type refers to itself without being declared, which the mapper reported.
+		return unknownNode{}
+	case !ok:
+		// The declaration is still being built.
+		n = lazyNode{name}
+	}
+	return resolved[S]{schema, n, name}
+}
+
+// resolverFunc is a Resolver implemented by a function.
+type resolverFunc[A, B any] func(A) B
+
+func (f resolverFunc[A, B]) Resolve(a A) B {
+	return f(a)
+}
+
+// sources returns the resolver of the TypeScript expressions of the schemas of Go types, which transforms refer to,
+// given the renderer of the backend's schemas.
+func sources[S any](nodes Resolver[goinsp.Type, node], render func(node) S, typeScript func(S) ts.Source) Resolver[goinsp.Type, ts.Source] {
+	return resolverFunc[goinsp.Type, ts.Source](func(t goinsp.Type) ts.Source {
+		return typeScript(render(nodes.Resolve(t)))
+	})
+}
+
+// unknownNodeError is the panic of renderers given a node they don't know.
+func unknownNodeError(n node) string {
+	return fmt.Sprintf("unknown node %T", n)
+}
@@ -2,6 +2,20 @@ package gozod
 
 // A builder knows how to build a B for a given A given a resolver for other As it depends on.
 // It may also return a Declaration as a byproduct.
-type builder[A, B, Declaration any] interface {
+type builder[A, B, ID, Declaration any] interface {
+	// Canonical returns the A to build in place of the given one, so that different As that would be built alike share
+	// a single Declaration.
+	Canonical(A) A
+	// Name returns the identifier of the Declaration that Build will return for the given A, if it will return one.
+	Name(A) (ID, bool)
 	Build(A, Resolver[A, B]) (B, Declaration, bool)
+	// Lazy returns a B referring to the Declaration with the given identifier before that Declaration has been built.
+	// It is used for the Bs of recursive references to As that are still being built.
+	Lazy(ID) B
+	// Invalid reports a problem preventing the B of an A from being built and returns a placeholder for it, so that
+	// building can carry on to find further problems.
+	Invalid(problem error) B
+	// Err reports the problems found while building, which don't prevent the Bs from being built, but from being
+	// faithful to their As.
+	Err() error
 }
@@ -0,0 +1,70 @@
+package gozod
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
+	"github.com/softwaretechnik-berlin/goats/gotypes/ts"
+	"github.com/softwaretechnik-berlin/goats/gotypes/util"
+)
+
+// Bundle is a module of declarations for a client, e.g. `admin` for an admin console, which are those that its root
+// types depend on, directly or indirectly.
+type Bundle struct {
+	Name  string
+	Roots []goinsp.Type
+}
+
+// commonBundle is the name of the module with the declarations that more than one Bundle depends on.
+const commonBundle = "common"
+
+// GenerateBundles resolves the roots of the given bundles and writes the declarations that each of them depends on to
+// a TypeScript module named after it in the given directory, e.g. `admin.ts`, unless the mapper found problems while
+// resolving types, in which case it returns all of them instead.
+//
+// Declarations that more than one bundle depends on are written to `common.ts` instead, from which the bundles import
+// them, so that all bundles share the same declarations. The declarations of each module are in the order of
+// SupportingDeclarations.
+func GenerateBundles[S any, D typeScriptDeclaration[D]](mapper mapper[goinsp.Type, S, ts.Identifier, D], outDir string, bundles ...Bundle) error {
+	bundlesByDeclaration := make(map[ts.Identifier][]string)
+	for i, b := range bundles {
+		if b.Name == commonBundle || slices.ContainsFunc(bundles[:i], func(other Bundle) bool { return other.Name == b.Name }) {
+			return fmt.Errorf("bundles can't be called %s, since there is already a module of that name", b.Name)
+		}
+		roots := util.Map(b.Roots, mapper.Resolve)
+		for name := range mapper.reachable(roots...) {
+			bundlesByDeclaration[name] = append(bundlesByDeclaration[name], b.Name)
+		}
+	}
+	if err := mapper.Err(); err != nil {
+		return err
+	}
+
+	declarationsByModule := make(map[string][]ts.Source)
+	for _, d := range orderedDeclarations(mapper) {
+		module := commonBundle
+		if names := bundlesByDeclaration[d.Identifier()]; len(names) == 1 {
+			module = names[0]
+		} else if len(names) == 0 {
+			continue
+		}
+		declarationsByModule[module] = append(declarationsByModule[module], d.TypeScript())
+	}
+	common := statements(declarationsByModule[commonBundle])
+	shared := ts.ExportedNames(common)
+	if len(shared) > 0 {
+		if err := writeModule(outDir, commonBundle, common.String()); err != nil {
+			return err
+		}
+	}
+	for _, b := range bundles {
+		source := ts.Module(statements(declarationsByModule[b.Name]), func(name ts.Identifier) (string, bool) {
+			return moduleSpecifier(b.Name, commonBundle), slices.Contains(shared, name)
+		})
+		if err := writeModule(outDir, b.Name, source); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,26 +1,58 @@
 package gozod
 
 import (
+	"fmt"
 	"slices"
 
+	"github.com/softwaretechnik-berlin/goats/gotypes/effect"
 	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
 	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/parsing/comments"
 	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/static"
+	"github.com/softwaretechnik-berlin/goats/gotypes/jsonschema"
+	"github.com/softwaretechnik-berlin/goats/gotypes/pydantic"
 	"github.com/softwaretechnik-berlin/goats/gotypes/ts"
 	"github.com/softwaretechnik-berlin/goats/gotypes/util"
+	"github.com/softwaretechnik-berlin/goats/gotypes/valibot"
 	"github.com/softwaretechnik-berlin/goats/gotypes/zod"
 )
 
 type config struct {
-	names                 map[goinsp.GenType]ts.Identifier
-	unnamedTypes          map[goinsp.GenType]struct{}
-	schemas               map[goinsp.GenType]func(resolver Resolver[goinsp.Type, zod.ZodType]) zod.ZodType
-	templates             map[goinsp.GenType]string
-	undiscriminatedUnions map[goinsp.GenType][]goinsp.Type
-	discriminators        map[goinsp.GenType]JSONDiscriminator
-	discriminatedUnions   map[goinsp.GenType]JSONDiscriminatedUnion
-	transforms            map[goinsp.GenType]func(resolver Resolver[goinsp.Type, zod.ZodType]) ts.Source
+	names                 map[typeKey]ts.Identifier
+	unnamedTypes          map[typeKey]struct{}
+	schemas               map[typeKey]func(resolver Resolver[goinsp.Type, zod.ZodType]) zod.ZodType
+	templates             map[typeKey]string
+	undiscriminatedUnions map[typeKey][]goinsp.Type
+	discriminators        map[typeKey]JSONDiscriminator
+	discriminatedUnions   map[typeKey]JSONDiscriminatedUnion
+	transforms            map[typeKey]func(resolver Resolver[goinsp.Type, ts.Source]) ts.Source
 	commentsLoader        comments.Loader
+	describeFields        bool
+	explicitTypes         bool
+	sharedSubSchemas      bool
+	genericFactories      bool
+	factories             map[typeKey]struct{}
+	staticLoader          static.Loader
+	namingStrategy        NamingStrategy
+	nilPolicy             NilPolicy
+	nilPolicies           map[typeKey]NilPolicy
+	standardLibrary       bool
+	timeAsDate            bool
+	integerPolicy         IntegerPolicy
+	integerPolicies       map[typeKey]IntegerPolicy
+	enums                 bool
+	enumTypes             map[typeKey]bool
+	validateTags          bool
+	ignoredValidateRules  map[string]bool
+	jsonSamples           map[typeKey][]any
+	jsonSchemas           map[typeKey]jsonschema.Schema
+	pydanticTypes         map[typeKey]pydantic.Type
+	valibotSchemas        map[typeKey]valibot.Schema
+	effectSchemas         map[typeKey]effect.Schema
+	// standardSchemas are the nodes of the types configured by WithStandardLibrary.
+	standardSchemas map[typeKey]func(nodes Resolver[goinsp.Type, node]) node
+	// problems are those found in the options, which the mapper's Err reports.
+	problems []error
 }
 
 type JSONDiscriminator struct {
@@ -38,9 +70,23 @@ func newConfig(options ...Option) config {
 	for _, o := range options {
 		o.apply(&c)
 	}
+	if c.standardLibrary {
+		// The given options are applied again so that they take precedence over those of the standard library.
+		options = append(standardLibrary(c), options...)
+		c = config{}
+		for _, o := range options {
+			o.apply(&c)
+		}
+	}
 	if c.commentsLoader == nil {
 		c.commentsLoader = comments.NewLoader()
 	}
+	if c.staticLoader == nil {
+		c.staticLoader = static.NewLoader()
+	}
+	if c.namingStrategy == nil {
+		c.namingStrategy = FlattenedTypeNames
+	}
 	return c
 }
 
@@ -54,27 +100,27 @@ var _ Option = TypeOptions{}
 func WithName(t goinsp.GenType, name string) Option {
 	return funcOption(func(c *config) {
 		if c.names == nil {
-			c.names = make(map[goinsp.GenType]ts.Identifier)
+			c.names = make(map[typeKey]ts.Identifier)
 		}
-		c.names[t] = ts.Identifier(name)
+		c.names[keyFor(t)] = ts.Identifier(name)
 	})
 }
 
 func WithUnnamedType(t goinsp.GenType) Option {
 	return funcOption(func(c *config) {
 		if c.unnamedTypes == nil {
-			c.unnamedTypes = make(map[goinsp.GenType]struct{})
+			c.unnamedTypes = make(map[typeKey]struct{})
 		}
-		c.unnamedTypes[t] = struct{}{}
+		c.unnamedTypes[keyFor(t)] = struct{}{}
 	})
 }
 
 func WithResolvingSchema(t goinsp.GenType, schema func(resolver Resolver[goinsp.Type, zod.ZodType]) zod.ZodType) Option {
 	return funcOption(func(c *config) {
 		if c.schemas == nil {
-			c.schemas = make(map[goinsp.GenType]func(resolver Resolver[goinsp.Type, zod.ZodType]) zod.ZodType)
+			c.schemas = make(map[typeKey]func(resolver Resolver[goinsp.Type, zod.ZodType]) zod.ZodType)
 		}
-		c.schemas[t] = schema
+		c.schemas[keyFor(t)] = schema
 	})
 }
 
@@ -82,53 +128,64 @@ func WithSchema(t goinsp.GenType, schema zod.ZodType) Option {
 	return WithResolvingSchema(t, func(_ Resolver[goinsp.Type, zod.ZodType]) zod.ZodType { return schema })
 }
 
+// WithExternal refers to the schema that the given module exports under the given name, importing it, instead of
+// declaring the given type, e.g. `Money` from `@acme/money`, see zod.External.
+func WithExternal(t goinsp.GenType, module, name string) Option {
+	return funcOption(func(c *config) {
+		WithUnnamedType(t).apply(c)
+		WithSchema(t, zod.External(module, ts.Identifier(name))).apply(c)
+	})
+}
+
 func WithTemplate(t goinsp.GenType, template string) Option {
 	return funcOption(func(c *config) {
 		if c.templates == nil {
-			c.templates = make(map[goinsp.GenType]string)
+			c.templates = make(map[typeKey]string)
 		}
-		c.templates[t] = template
+		c.templates[keyFor(t)] = template
 	})
 }
 
 func WithUndiscriminatedUnion(t goinsp.GenType, types ...goinsp.Type) Option {
 	return funcOption(func(c *config) {
 		if c.undiscriminatedUnions == nil {
-			c.undiscriminatedUnions = make(map[goinsp.GenType][]goinsp.Type)
+			c.undiscriminatedUnions = make(map[typeKey][]goinsp.Type)
 		}
-		c.undiscriminatedUnions[t] = types
+		c.undiscriminatedUnions[keyFor(t)] = types
 	})
 }
 
 func WithDiscriminator(t goinsp.GenType, property, value string) Option {
 	return funcOption(func(c *config) {
 		if c.discriminators == nil {
-			c.discriminators = make(map[goinsp.GenType]JSONDiscriminator)
+			c.discriminators = make(map[typeKey]JSONDiscriminator)
 		}
-		c.discriminators[t] = JSONDiscriminator{property, value}
+		c.discriminators[keyFor(t)] = JSONDiscriminator{property, value}
 	})
 }
 
-func WithDiscriminatedUnion(t goinsp.GenType, discriminatorProperty string, types ...goinsp.Type) func(*config) {
-	return func(c *config) {
+func WithDiscriminatedUnion(t goinsp.GenType, discriminatorProperty string, types ...goinsp.Type) Option {
+	return funcOption(func(c *config) {
 		if c.discriminatedUnions == nil {
-			c.discriminatedUnions = make(map[goinsp.GenType]JSONDiscriminatedUnion)
+			c.discriminatedUnions = make(map[typeKey]JSONDiscriminatedUnion)
 		}
-		c.discriminatedUnions[t] = JSONDiscriminatedUnion{discriminatorProperty, types}
-	}
+		c.discriminatedUnions[keyFor(t)] = JSONDiscriminatedUnion{discriminatorProperty, types}
+	})
 }
 
-func WithResolvingTransform(t goinsp.GenType, expr func(resolver Resolver[goinsp.Type, zod.ZodType]) ts.Source) Option {
+// WithResolvingTransform transforms the values of the given type with the TypeScript function returned by expr, which
+// may refer to the schemas of other types, as resolved in the validator library of the backend.
+func WithResolvingTransform(t goinsp.GenType, expr func(resolver Resolver[goinsp.Type, ts.Source]) ts.Source) Option {
 	return funcOption(func(c *config) {
 		if c.transforms == nil {
-			c.transforms = make(map[goinsp.GenType]func(resolver Resolver[goinsp.Type, zod.ZodType]) ts.Source)
+			c.transforms = make(map[typeKey]func(resolver Resolver[goinsp.Type, ts.Source]) ts.Source)
 		}
-		c.transforms[t] = expr
+		c.transforms[keyFor(t)] = expr
 	})
 }
 
 func WithTransform(t goinsp.GenType, expr ts.Source) Option {
-	return WithResolvingTransform(t, func(_ Resolver[goinsp.Type, zod.ZodType]) ts.Source { return expr })
+	return WithResolvingTransform(t, func(_ Resolver[goinsp.Type, ts.Source]) ts.Source { return expr })
 }
 
 func WithCommentsLoader(loader comments.Loader) Option {
@@ -137,6 +194,61 @@ func WithCommentsLoader(loader comments.Loader) Option {
 	})
 }
 
+// WithFieldDescriptions makes the doc comments of struct fields available at runtime,
+// by adding them to the schemas of the corresponding object properties with `.describe(…)`.
+// Field comments are always emitted as doc comments on the object properties, regardless of this option.
+func WithFieldDescriptions() Option {
+	return funcOption(func(c *config) {
+		c.describeFields = true
+	})
+}
+
+// WithExplicitTypes declares the output types of schemas explicitly, e.g. `export interface User { … }`, instead of
+// inferring them with `z.infer`, and checks the schemas against them with `satisfies z.ZodType<User>`. TypeScript checks
+// explicit types much faster than inferred ones.
+func WithExplicitTypes() Option {
+	return funcOption(func(c *config) {
+		c.explicitTypes = true
+	})
+}
+
+// WithSharedSubSchemas declares the schemas of unnamed types that occur repeatedly, e.g. anonymous structs, maps or
+// templated strings, as constants of their own, e.g. `const shared1 = z.object({ … });`, and refers to them by name,
+// rather than repeating them inline. The constants aren't exported.
+func WithSharedSubSchemas() Option {
+	return funcOption(func(c *config) {
+		c.sharedSubSchemas = true
+	})
+}
+
+// WithGenericFactories declares every generic type as a factory, which instantiations of the type invoke with the
+// schemas for their type arguments, e.g. `Page(User)` for `Page[User]`.
+// Without it, only the generic types configured with WithGenericFactory are declared as factories.
+func WithGenericFactories() Option {
+	return funcOption(func(c *config) {
+		c.genericFactories = true
+	})
+}
+
+// WithGenericFactory declares the given generic type as a factory, see WithGenericFactories.
+// Instantiations of it can still be declared individually by naming them with WithName.
+func WithGenericFactory(t goinsp.GenType) Option {
+	return funcOption(func(c *config) {
+		if c.factories == nil {
+			c.factories = make(map[typeKey]struct{})
+		}
+		c.factories[keyFor(t.WithoutTypeArguments())] = struct{}{}
+	})
+}
+
+// WithStaticLoader sets the loader used to look up the declarations of generic types that are declared as factories,
+// since reflection doesn't provide them. It defaults to a static.NewLoader().
+func WithStaticLoader(loader static.Loader) Option {
+	return funcOption(func(c *config) {
+		c.staticLoader = loader
+	})
+}
+
 type TypeOptions struct {
 	t       goinsp.GenType
 	options []Option
@@ -156,10 +268,20 @@ func (o TypeOptions) Schema(schema zod.ZodType) TypeOptions {
 	return o.add(WithSchema(o.t, schema))
 }
 
+// External refers to an external schema instead of declaring the type, see WithExternal.
+func (o TypeOptions) External(module string, name string) TypeOptions {
+	return o.add(WithExternal(o.t, module, name))
+}
+
 func (o TypeOptions) ResolvingSchema(schema func(resolver Resolver[goinsp.Type, zod.ZodType]) zod.ZodType) TypeOptions {
 	return o.add(WithResolvingSchema(o.t, schema))
 }
 
+// JSONSamples infers the schema of the type from the JSON the given samples of it marshal to, see WithJSONSamples.
+func (o TypeOptions) JSONSamples(samples ...any) TypeOptions {
+	return o.add(WithJSONSamples(o.t, samples...))
+}
+
 func (o TypeOptions) Template(template string) TypeOptions {
 	return o.add(WithTemplate(o.t, template))
 }
@@ -172,7 +294,7 @@ func (o TypeOptions) Named(name string) TypeOptions {
 	return o.add(WithName(o.t, name))
 }
 
-func (o TypeOptions) ResolvingTransform(f func(resolver Resolver[goinsp.Type, zod.ZodType]) ts.Source) TypeOptions {
+func (o TypeOptions) ResolvingTransform(f func(resolver Resolver[goinsp.Type, ts.Source]) ts.Source) TypeOptions {
 	return o.add(WithResolvingTransform(o.t, f))
 }
 
@@ -180,21 +302,51 @@ func (o TypeOptions) Transform(f ts.Source) TypeOptions {
 	return o.add(WithTransform(o.t, f))
 }
 
+// Transformf is like Transform, but formats the transform like ts.Sourcef, substituting the schemas of the goinsp.Types
+// among the arguments, which must otherwise be ts.Sources.
 func (o TypeOptions) Transformf(format string, as ...any) TypeOptions {
-	return o.add(WithResolvingTransform(o.t, func(resolver Resolver[goinsp.Type, zod.ZodType]) ts.Source {
+	for i, value := range as {
+		switch value.(type) {
+		case ts.Source, goinsp.Type:
+		default:
+			return o.add(invalidOption(fmt.Errorf("argument %d of the transform of %v is a %T rather than a ts.Source or goinsp.Type", i, o.t, value)))
+		}
+	}
+	return o.add(WithResolvingTransform(o.t, func(resolver Resolver[goinsp.Type, ts.Source]) ts.Source {
 		return ts.Sourcef(format, util.Map(as, func(value any) ts.Source {
-			switch value := value.(type) {
-			case ts.Source:
-				return value
-			case goinsp.Type:
-				return resolver.Resolve(value).TypeScript()
-			default:
-				panic(value)
+			if t, ok := value.(goinsp.Type); ok {
+				return resolver.Resolve(t)
 			}
+			return value.(ts.Source)
 		})...)
 	}))
 }
 
+// NilPolicy sets the NilPolicy for the slice or map type, see WithTypeNilPolicy.
+func (o TypeOptions) NilPolicy(policy NilPolicy) TypeOptions {
+	return o.add(WithTypeNilPolicy(o.t, policy))
+}
+
+// IntegerPolicy sets the IntegerPolicy for the 64-bit integer type, see WithTypeIntegerPolicy.
+func (o TypeOptions) IntegerPolicy(policy IntegerPolicy) TypeOptions {
+	return o.add(WithTypeIntegerPolicy(o.t, policy))
+}
+
+// AsFactory declares the generic type as a factory, see WithGenericFactory.
+func (o TypeOptions) AsFactory() TypeOptions {
+	return o.add(WithGenericFactory(o.t))
+}
+
+// AsEnum declares the type as an enum of its constants, see WithEnum.
+func (o TypeOptions) AsEnum() TypeOptions {
+	return o.add(WithEnum(o.t, true))
+}
+
+// NotEnum excludes the type from WithEnums.
+func (o TypeOptions) NotEnum() TypeOptions {
+	return o.add(WithEnum(o.t, false))
+}
+
 func (o TypeOptions) UndiscriminatedUnionOf(disjuncts ...goinsp.Type) Option {
 	return o.add(WithUndiscriminatedUnion(o.t, disjuncts...))
 }
@@ -207,12 +359,13 @@ func When[T any]() TypeOptions {
 	return ForType(reflective.TypeFor[T]())
 }
 
+// WhenGeneric configures the generic type of which T is an instantiation, e.g. `Page[T]` for `Page[User]`.
 func WhenGeneric[T any]() TypeOptions {
 	t := reflective.TypeFor[T]()
 	if generic := t.WithoutTypeArguments(); generic != t {
 		return ForType(generic)
 	}
-	panic(t)
+	return TypeOptions{t, []Option{invalidOption(fmt.Errorf("WhenGeneric needs an instantiation of a generic type, not %v", t))}}
 }
 
 type funcOption func(c *config)
@@ -221,10 +374,33 @@ func (f funcOption) apply(c *config) {
 	f(c)
 }
 
-func lookupConfig[T any](m map[goinsp.GenType]T, t goinsp.Type) (value T, ok bool) {
-	value, ok = m[t]
+// invalidOption is an option that can't be applied, whose problem the mapper's Err reports.
+func invalidOption(problem error) Option {
+	return funcOption(func(c *config) { c.problems = append(c.problems, problem) })
+}
+
+func lookupConfig[T any](m map[typeKey]T, t goinsp.Type) (value T, ok bool) {
+	value, ok = m[keyFor(t)]
 	if !ok {
-		value, ok = m[t.WithoutTypeArguments()]
+		value, ok = m[keyFor(t.WithoutTypeArguments())]
 	}
 	return
 }
+
+// typeKey identifies a type in the configuration.
+//
+// Named types are identified by their import path and name, so that configuration given for a type obtained from one
+// goinsp implementation (typically reflective.TypeFor) also applies when the same type is obtained from another
+// (e.g. by loading it statically).
+type typeKey struct {
+	pkgPath goinsp.ImportPath
+	name    goinsp.TypeName
+	unnamed goinsp.GenType
+}
+
+func keyFor(t goinsp.GenType) typeKey {
+	if t.PkgPath() != "" && t.Name() != "" {
+		return typeKey{t.PkgPath(), t.Name(), nil}
+	}
+	return typeKey{"", "", t}
+}
@@ -0,0 +1,143 @@
+package gozod
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/parsing/comments"
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+	"github.com/softwaretechnik-berlin/goats/gotypes/ts"
+)
+
+// directiveTool is the tool name of the directives gozod understands, e.g. `//gotypes:schema z.string().datetime()`.
+const directiveTool = "gotypes"
+
+// directives holds the `//gotypes:…` directives given for a type.
+//
+// They can be given in the doc comment of the type itself, as well as in the doc comments of its MarshalJSON and
+// MarshalText methods, so that the representation of a type that customises its JSON can be declared next to the code
+// doing the customisation. Directives on the type take precedence over those on MarshalJSON, which in turn take
+// precedence over those on MarshalText, mirroring the precedence encoding/json gives to the methods. The directives of
+// methods with pointer receivers only apply to addressable values, which encoding/json marshals with them.
+type directives struct {
+	// schema is a zod schema expression replacing the schema that would otherwise be derived from the type.
+	schema string
+	// transform is a TypeScript function expression transforming the values parsed by the schema.
+	transform string
+	// template is a string template as given with WithTemplate.
+	template string
+}
+
+// directives returns the directives given for the unaddressable values of t, reporting the problems with them.
+func (b nodeBuilder) directives(t goinsp.Type) directives {
+	return b.directivesAt(t, goinsp.Unaddressable)
+}
+
+// directivesAt returns the directives given for the values of t with addressability a, reporting the problems with them.
+func (b nodeBuilder) directivesAt(t goinsp.Type, a goinsp.Addressability) directives {
+	d, err := b.loadDirectives(t, a)
+	if err != nil {
+		b.report(err)
+	}
+	return d
+}
+
+// loadDirectives returns the directives given for the values of t with addressability a, which are incomplete if
+// there's a problem with them.
+func (b nodeBuilder) loadDirectives(t goinsp.Type, a goinsp.Addressability) (directives, error) {
+	var d directives
+	if t.PkgPath() == "" || t.Name() == "" {
+		// There's no declaration we could find directives in.
+		return d, nil
+	}
+	given, err := b.loadDirectivesOf(t, "")
+	if err != nil {
+		return d, fmt.Errorf("can't load the directives of %v: %w", t, err)
+	}
+	if err := d.add(t, "", given); err != nil {
+		return d, err
+	}
+	for _, method := range []struct {
+		name      string
+		marshaler goinsp.Type
+	}{
+		{"MarshalJSON", reflective.TypeFor[json.Marshaler]()},
+		{"MarshalText", reflective.TypeFor[encoding.TextMarshaler]()},
+	} {
+		if !a.Implements(t, method.marshaler) {
+			continue
+		}
+		given, err := b.loadDirectivesOf(t, method.name)
+		if err != nil {
+			return d, fmt.Errorf("can't load the directives of %v: %w", t, err)
+		}
+		if err := d.add(t, method.name, given); err != nil {
+			return d, err
+		}
+	}
+	return d, nil
+}
+
+// loadDirectivesOf returns the directives in the doc comment of t, or of its method with the given name if method isn't
+// empty, only resorting to the comments loader if t doesn't provide them, e.g. if it was obtained by reflection.
+func (b nodeBuilder) loadDirectivesOf(t goinsp.Type, method string) ([]comments.Directive, error) {
+	if given := t.Directives(method); given.Available {
+		return comments.ParseDirectives(given.Value), nil
+	}
+	if method == "" {
+		return b.commentsLoader.LoadDirectives(t)
+	}
+	return b.commentsLoader.LoadMethodDirectives(t, method)
+}
+
+// add adds the given directives, found in the doc comment of the given method of t (or of t itself if method is empty),
+// without overriding directives that have already been given.
+func (d *directives) add(t goinsp.Type, method string, given []comments.Directive) error {
+	var fromThisComment directives
+	for _, directive := range given {
+		if directive.Tool != directiveTool {
+			continue
+		}
+		var value *string
+		switch directive.Name {
+		case "schema":
+			value = &fromThisComment.schema
+		case "transform":
+			value = &fromThisComment.transform
+		case "template":
+			value = &fromThisComment.template
+		default:
+			return fmt.Errorf("unknown directive %s on %s", directive, directiveLocation(t, method))
+		}
+		if directive.Args == "" {
+			return fmt.Errorf("directive %s on %s is missing its argument", directive, directiveLocation(t, method))
+		}
+		if *value != "" {
+			return fmt.Errorf("duplicate directive %s on %s", directive, directiveLocation(t, method))
+		}
+		*value = directive.Args
+	}
+	if d.schema == "" {
+		d.schema = fromThisComment.schema
+	}
+	if d.transform == "" {
+		d.transform = fromThisComment.transform
+	}
+	if d.template == "" {
+		d.template = fromThisComment.template
+	}
+	return nil
+}
+
+func directiveLocation(t goinsp.Type, method string) string {
+	if method == "" {
+		return t.String()
+	}
+	return t.String() + "." + method
+}
+
+func (d directives) transformExpr() ts.Source {
+	return ts.Importing(ts.AsSource(d.transform), "zod", "z")
+}
@@ -0,0 +1,321 @@
+package gozod
+
+import (
+	"github.com/softwaretechnik-berlin/goats/gotypes/effect"
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
+	"github.com/softwaretechnik-berlin/goats/gotypes/ts"
+	"github.com/softwaretechnik-berlin/goats/gotypes/util"
+)
+
+type goToEffectMapper = mapper[goinsp.Type, effect.Schema, ts.Identifier, EffectDeclaration]
+
+// NewEffectMapper returns a mapper like NewMapper, whose declarations are Effect schemas rather than zod schemas,
+// e.g. `export const User = Schema.Struct({ … })`.
+//
+// Hand-written schemas, e.g. those of gotypes:schema directives, are reported by Err unless their Effect equivalents
+// are given with WithEffectSchema, since they would accept any value. Transformations can't be inverted, so the
+// schemas can't encode values.
+func NewEffectMapper(options ...Option) goToEffectMapper {
+	c := newConfig(options...)
+	return newBackendMapper(newNodeBuilder(c), effectBackend{c.effectSchemas})
+}
+
+// WithEffectSchema gives the Effect schema of the given type, which NewEffectMapper uses in place of the one derived
+// from the type, e.g. for types whose zod schemas are given with WithSchema.
+func WithEffectSchema(t goinsp.GenType, schema effect.Schema) Option {
+	return funcOption(func(c *config) {
+		if c.effectSchemas == nil {
+			c.effectSchemas = make(map[typeKey]effect.Schema)
+		}
+		c.effectSchemas[keyFor(t)] = schema
+	})
+}
+
+// EffectDeclaration declares an Effect schema under the name of the declared type, along with the type of its output,
+// e.g. `export const User = Schema.Struct({ … }); export type User = typeof User.Type;`.
+//
+// The value and label maps of enums are declared along with them, as for zod schemas.
+type EffectDeclaration struct {
+	schemaDeclaration
+	schema effect.Schema
+}
+
+// Recursive returns the declaration of a schema that refers to itself lazily.
+//
+// The lazy references to such a schema are typed as any, so its plain type is declared instead of being inferred.
+func (d EffectDeclaration) Recursive() EffectDeclaration {
+	d.recursive = true
+	return d
+}
+
+func (d EffectDeclaration) TypeScript() ts.Source {
+	schema := d.schema.TypeScript()
+	if len(d.typeParameters) > 0 {
+		return d.factoryTypeScript(ts.TypeName(ts.Sourcef("%s.Schema.Any", effect.S)), schema, func(instance ts.TypeExpression) ts.TypeExpression {
+			return ts.TypeName(ts.Sourcef("%s.Schema.Type", effect.S), instance)
+		}, func(t ts.TypeExpression) ts.TypeExpression {
+			return ts.TypeName(ts.Sourcef("%s.Schema", effect.S), t, ts.AnyType)
+		})
+	}
+	var declaration ts.Source
+	if d.recursive {
+		declaration = ts.Statements(
+			ts.DocComment(d.comment),
+			d.plainTypeDeclaration(),
+			ts.Sourcef(`export const %s = %s;`, d.name, schema),
+		)
+	} else {
+		declaration = ts.Statements(
+			ts.DocComment(d.comment),
+			ts.Sourcef(`export const %s = %s;`, d.name, schema),
+			ts.Sourcef(`export type %s = typeof %s.Type;`, d.name, d.name),
+		)
+	}
+	return d.withEnumMaps(declaration)
+}
+
+// effectBackend renders nodes as Effect schemas.
+type effectBackend struct {
+	schemas map[typeKey]effect.Schema
+}
+
+var _ backend[effect.Schema, EffectDeclaration] = effectBackend{}
+
+func (b effectBackend) name() string   { return "Effect" }
+func (b effectBackend) option() string { return "WithEffectSchema" }
+
+func (b effectBackend) configures(t goinsp.Type) bool {
+	_, ok := lookupConfig(b.schemas, t)
+	return ok
+}
+
+func (b effectBackend) handWritten(t goinsp.Type, _ Resolver[goinsp.Type, node]) (node, bool) {
+	schema, ok := lookupConfig(b.schemas, t)
+	if !ok {
+		return nil, false
+	}
+	return resolved[effect.Schema]{schema, unknownNode{}, ""}, true
+}
+
+func (b effectBackend) zodExpression(string) (node, bool) {
+	return nil, false
+}
+
+func (b effectBackend) render(n node, nodes Resolver[goinsp.Type, node]) effect.Schema {
+	return renderEffect(n, nodes)
+}
+
+func (b effectBackend) reference(name ts.Identifier, _ effect.Schema) effect.Schema {
+	return effect.Expr(name)
+}
+
+func (b effectBackend) declare(d declared[effect.Schema]) EffectDeclaration {
+	return EffectDeclaration{schemaDeclarationOf(d), d.schema}
+}
+
+// renderEffect renders the given node as an Effect schema.
+func renderEffect(n node, nodes Resolver[goinsp.Type, node]) effect.Schema {
+	render := func(n node) effect.Schema { return renderEffect(n, nodes) }
+	switch n := n.(type) {
+	case anyNode:
+		return effect.Any()
+	case unknownNode:
+		return effect.Unknown()
+	case booleanNode:
+		return effect.Boolean()
+	case bigIntNode:
+		return effect.BigInt()
+	case nullNode:
+		return effect.Null()
+	case stringNode:
+		s := effect.String()
+		if n.base != nil {
+			s = render(n.base)
+		}
+		for _, c := range n.checks {
+			s = effectStringCheck(s, c)
+		}
+		return s
+	case numberNode:
+		number := effect.Number()
+		if n.base != nil {
+			number = render(n.base)
+		}
+		for _, c := range n.checks {
+			number = effectNumberCheck(number, c)
+		}
+		return number
+	case arrayNode:
+		var array effect.Schema
+		if n.base != nil {
+			array = render(n.base)
+		} else {
+			array = effect.Array(render(n.elem))
+		}
+		for _, c := range n.checks {
+			switch c.kind {
+			case arrayMinLength:
+				array = array.MinItems(int(c.length))
+			case arrayMaxLength:
+				array = array.MaxItems(int(c.length))
+			case arrayExactLength:
+				array = array.ItemsCount(int(c.length))
+			}
+		}
+		return array
+	case recordNode:
+		return effect.Record(render(n.key), render(n.value))
+	case literalNode:
+		return effect.Literal(literal(n))
+	case stringEnumNode:
+		return effect.Literal(util.Map(n.values, ts.StringLiteral)...)
+	case enumNode:
+		return effect.Literal(util.Map(n.values(), enumLiteral)...)
+	case objectNode:
+		object := effect.Struct(util.Map(n.properties, func(p property) effect.Field {
+			return effect.Field{Name: p.name, Schema: render(p.node), Comment: p.comment}
+		})...)
+		if renamed := renamedProperties(n); len(renamed) > 0 {
+			object = object.Rename(renamed...)
+		}
+		return object
+	case unionNode:
+		// Effect tells apart structs by their literal fields on its own, so discriminated unions are plain unions.
+		return effect.Union(util.Map(n.members, render)...)
+	case nullableNode:
+		return render(n.inner).NullOr()
+	case optionalNode:
+		return render(n.inner).Optional()
+	case readonlyNode:
+		// The values Effect produces aren't frozen.
+		return render(n.inner)
+	case describedNode:
+		return render(n.inner).Description(n.description)
+	case brandedNode:
+		return render(n.inner).Brand(n.brand)
+	case defaultNode:
+		return render(n.inner).Default(n.value)
+	case hoistableNode:
+		return render(n.inner)
+	case orNode:
+		return render(n.inner).Or(render(n.other))
+	case refinedNode:
+		return render(n.inner).Filter(n.check.typeScript)
+	case transformNode:
+		fn := n.fn(sources(nodes, render, effect.Schema.TypeScript))
+		switch {
+		case n.outputType != nil:
+			return render(n.inner).TransformTo(effectTypeSchema(*n.outputType), fn)
+		case n.outputOf != nil:
+			return render(n.inner).TransformTo(render(n.outputOf).TypeSchema(), fn)
+		default:
+			return render(n.inner).TransformTo(effect.Unknown(), fn)
+		}
+	case quotedNode:
+		if n.parse {
+			return effect.String().TransformTo(effect.Unknown(), ts.AsSource("s => JSON.parse(s)")).Compose(render(n.inner))
+		}
+		return render(n.inner)
+	case templateNode:
+		// The strings are checked against the template's pattern first, so the transformation can rely on them
+		// matching.
+		return effect.String().Pattern(n.pattern).TransformTo(render(n.inner).TypeSchema(), ts.Sourcef(`(s) => {
+    const match = %s.exec(s)!;
+    return %s;
+}`, ts.RegexLiteral(n.pattern), n.match(func(n node, value ts.Source) ts.Source { return effect.Parse(render(n), value) })))
+	case largeIntegerNode:
+		return render(n.transformed())
+	case typeParameterNode:
+		return effect.Expr(parameterName(n.name))
+	case factoryNode:
+		return effect.Expr(n.name)
+	case lazyNode:
+		return effect.Suspend(n.name)
+	case invocationNode:
+		arguments := util.Map(n.arguments, func(n node) ts.Source { return render(n).TypeScript() })
+		if lazy, ok := referredFactory(n.factory).(lazyNode); ok {
+			// The factory is still being declared, so the invocation has to be deferred.
+			return effect.Suspend(ts.InvokeFunction(lazy.name, arguments...))
+		}
+		return effect.Expr(ts.InvokeFunction(render(n.factory).TypeScript(), arguments...))
+	case resolved[effect.Schema]:
+		return n.schema
+	default:
+		panic(unknownNodeError(n))
+	}
+}
+
+func effectStringCheck(s effect.Schema, c stringCheck) effect.Schema {
+	switch c.kind {
+	case stringMin:
+		return s.MinLength(c.length)
+	case stringMax:
+		return s.MaxLength(c.length)
+	case stringLength:
+		return s.Length(c.length)
+	case stringEmail:
+		return s.Email()
+	case stringURL:
+		return s.URL()
+	case stringUUID:
+		return s.UUID()
+	case stringDatetime:
+		return s.DatetimeWithOffset()
+	case stringIP:
+		return s.IP()
+	case stringIPv4:
+		return s.IPv4()
+	case stringIPv6:
+		return s.IPv6()
+	case stringIncludes:
+		return s.Includes(c.text)
+	case stringStartsWith:
+		return s.StartsWith(c.text)
+	case stringEndsWith:
+		return s.EndsWith(c.text)
+	case stringRegex:
+		return s.Pattern(c.pattern)
+	default:
+		panic(c.kind)
+	}
+}
+
+func effectNumberCheck(n effect.Schema, c numberCheck) effect.Schema {
+	switch c.kind {
+	case numberInt:
+		return n.Int()
+	case numberNonNegative:
+		return n.NonNegative()
+	case numberMin:
+		return n.GreaterThanOrEqualTo(ts.NumberLiteral(c.bound))
+	case numberMax:
+		return n.LessThanOrEqualTo(ts.NumberLiteral(c.bound))
+	case numberGt:
+		return n.GreaterThan(ts.NumberLiteral(c.bound))
+	case numberLt:
+		return n.LessThan(ts.NumberLiteral(c.bound))
+	case numberSafe:
+		return n.Between(ts.AsSource("Number.MIN_SAFE_INTEGER"), ts.AsSource("Number.MAX_SAFE_INTEGER"))
+	default:
+		panic(c.kind)
+	}
+}
+
+// effectTypeSchema is the Effect schema of values of the given type, as far as it is known, which is the target of
+// transformations to the type.
+func effectTypeSchema(t ts.TypeExpression) effect.Schema {
+	switch t.String() {
+	case ts.StringType.String():
+		return effect.String()
+	case ts.NumberType.String():
+		return effect.Number()
+	case ts.BooleanType.String():
+		return effect.Boolean()
+	case ts.BigIntType.String():
+		return effect.BigInt()
+	case "Date":
+		return effect.Date()
+	default:
+		return effect.Unknown()
+	}
+}
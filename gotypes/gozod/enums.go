@@ -0,0 +1,135 @@
+package gozod
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"go/constant"
+	"reflect"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/parsing/comments"
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+)
+
+// WithEnums declares named string and integer types as enums of the constants declared with them in their packages,
+// unless they are configured otherwise with WithEnum.
+//
+// Types whose constants aren't their only legal values, like time.Duration, should be excluded with WithEnum.
+func WithEnums() Option {
+	return funcOption(func(c *config) {
+		c.enums = true
+	})
+}
+
+// WithEnum sets whether the given named string or integer type is declared as an enum of the constants declared with
+// it in its package, regardless of WithEnums.
+//
+// The schema of an enum is `z.enum([…])` for string types and a union of `z.literal(…)`s for integer types, which is
+// declared along with an object mapping the names of the constants to their values. Types implementing
+// encoding.TextMarshaler are enums of the text their constants marshal to, if they're obtained through reflection.
+// Types implementing fmt.Stringer, but not encoding.TextMarshaler, additionally declare an object mapping their values
+// to the String() of their constants.
+func WithEnum(t goinsp.GenType, enum bool) Option {
+	return funcOption(func(c *config) {
+		if c.enumTypes == nil {
+			c.enumTypes = make(map[typeKey]bool)
+		}
+		c.enumTypes[keyFor(t)] = enum
+	})
+}
+
+// enumSchema returns the schema of the given type if it is to be declared as an enum.
+func (b nodeBuilder) enumSchema(t goinsp.Type) (node, bool) {
+	enum, configured := lookupConfig(b.enumTypes, t)
+	if !configured {
+		enum = b.enums
+	}
+	if !enum || t.PkgPath() == "" || t.Name() == "" || !isEnumKind(t.Kind()) || t.Implements(reflective.TypeFor[json.Marshaler]()) {
+		if configured && enum {
+			b.report(fmt.Errorf("%v is configured as an enum, but isn't a named string or integer type with the default JSON representation", t))
+		}
+		return nil, false
+	}
+	if _, ok := reflective.Reflected(t); !ok && t.Implements(reflective.TypeFor[encoding.TextMarshaler]()) {
+		// Without reflection, we can't marshal the constants to find the enum's values.
+		if configured {
+			b.report(fmt.Errorf("%v is configured as an enum, but implements encoding.TextMarshaler and wasn't obtained through reflection", t))
+		}
+		return nil, false
+	}
+	constants, err := b.commentsLoader.LoadConstants(t)
+	if err != nil {
+		b.report(fmt.Errorf("can't load the constants of %v: %w", t, err))
+		return nil, false
+	}
+	var members []enumMember
+	for _, c := range constants {
+		if member, ok := constantMember(t, c); ok {
+			members = append(members, member)
+		}
+	}
+	if len(members) == 0 {
+		if configured {
+			b.report(fmt.Errorf("%v is configured as an enum, but has no constants that can be represented in JSON", t))
+		}
+		return nil, false
+	}
+	return enumNode{members}, true
+}
+
+func isEnumKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	default:
+		return false
+	}
+}
+
+// constantMember returns the member of the enum of type t corresponding to the given constant, unless the constant
+// can't be marshalled.
+func constantMember(t goinsp.Type, c comments.Constant) (enumMember, bool) {
+	member := enumMember{name: c.Name, comment: c.Comment}
+	switch t.Kind() {
+	case reflect.String:
+		member.value = constant.StringVal(c.Value)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		member.value, _ = constant.Uint64Val(constant.ToInt(c.Value))
+	default:
+		member.value, _ = constant.Int64Val(constant.ToInt(c.Value))
+	}
+
+	textMarshaler := t.Implements(reflective.TypeFor[encoding.TextMarshaler]())
+	stringer := t.Implements(reflective.TypeFor[fmt.Stringer]())
+	if !textMarshaler && !stringer {
+		return member, true
+	}
+	reflected, ok := reflective.Reflected(t)
+	if !ok {
+		// Without reflection, we can't invoke String on the constants.
+		return member, true
+	}
+	value := reflect.New(reflected)
+	switch v := member.value.(type) {
+	case string:
+		value.Elem().SetString(v)
+	case uint64:
+		value.Elem().SetUint(v)
+	case int64:
+		value.Elem().SetInt(v)
+	}
+	if textMarshaler {
+		text, err := value.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			// encoding/json can't marshal the constant either, so it's not a legal value.
+			return member, false
+		}
+		member.value = string(text)
+		return member, true
+	}
+	member.label = value.Interface().(fmt.Stringer).String()
+	return member, true
+}
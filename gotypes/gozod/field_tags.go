@@ -0,0 +1,267 @@
+package gozod
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
+	"github.com/softwaretechnik-berlin/goats/gotypes/ts"
+)
+
+// fieldTag is the parsed gotypes tag of a struct field, e.g. `gotypes:",name=userId,min=1,max=64"`.
+//
+// The tag has no name, so it starts with a comma, which is followed by comma-separated options. The options are:
+//
+//   - value: the struct is represented by this field alone.
+//   - nullable: the field accepts null.
+//   - nonnull: the field rejects null, even though its pointer, slice or map type allows nil.
+//   - required: the field is required, even though its json tag has the omitempty option or it's promoted through an
+//     embedded pointer.
+//   - readonly: the field's value is frozen.
+//   - name=<identifier>: the field is renamed in the schema's output.
+//   - nil=<policy>: the NilPolicy of a field of an unnamed slice or map type.
+//   - int=<policy>: the IntegerPolicy of a field of an unnamed 64-bit integer type.
+//   - min=<n>, max=<n>, len=<n>: the field's string length, array length or number range.
+//   - format=<email|url|uuid|datetime>: the format of the field's string.
+//   - brand=<brand>: the field's schema is branded.
+//   - default=<value>: the field's value when it is undefined, given as JSON, or as a string if it isn't valid JSON.
+//     The value can't contain commas.
+//   - pattern=<regexp>: the regular expression the field's string must match.
+//   - schema=<expression>: the field's schema is the given TypeScript expression, which may refer to `z`.
+//
+// Since pattern= and schema= extend to the end of the tag, they may contain commas, but must be the last option.
+type fieldTag struct {
+	value, nullable, nonnull, required, readonly bool
+
+	name ts.Identifier
+
+	nilPolicy        NilPolicy
+	hasNilPolicy     bool
+	integerPolicy    IntegerPolicy
+	hasIntegerPolicy bool
+
+	min, max, len *float64
+	format        string
+	pattern       *regexp.Regexp
+
+	brand        string
+	defaultValue ts.Source
+	schema       string
+}
+
+var identifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// parseFieldTag parses the given gotypes tag, failing if it contains an option it doesn't know.
+func parseFieldTag(tag string) (fieldTag, error) {
+	var parsed fieldTag
+	if tag == "" {
+		return parsed, nil
+	}
+	name, options, _ := strings.Cut(tag, ",")
+	if name != "" {
+		return parsed, fmt.Errorf("gotypes tag %q must start with a comma, since it has no name", tag)
+	}
+	for options != "" {
+		var option string
+		option, options, _ = strings.Cut(options, ",")
+		key, value, hasValue := strings.Cut(option, "=")
+		if key == "pattern" || key == "schema" {
+			// These extend to the end of the tag, since regular expressions and TypeScript may contain commas.
+			if options != "" {
+				value += "," + options
+				options = ""
+			}
+		}
+		var err error
+		switch {
+		case option == "":
+		case option == "value":
+			parsed.value = true
+		case option == "nullable":
+			parsed.nullable = true
+		case option == "nonnull":
+			parsed.nonnull = true
+		case option == "required":
+			parsed.required = true
+		case option == "readonly":
+			parsed.readonly = true
+		case hasValue && key == "name":
+			if !identifier.MatchString(value) {
+				return parsed, fmt.Errorf("name %q in gotypes tag %q isn't a valid identifier", value, tag)
+			}
+			parsed.name = ts.Identifier(value)
+		case hasValue && key == "nil":
+			parsed.nilPolicy, err = parseNilPolicy(value, tag)
+			parsed.hasNilPolicy = true
+		case hasValue && key == "int":
+			parsed.integerPolicy, err = parseIntegerPolicy(value, tag)
+			parsed.hasIntegerPolicy = true
+		case hasValue && key == "min":
+			parsed.min, err = parseTagNumber(key, value, tag)
+		case hasValue && key == "max":
+			parsed.max, err = parseTagNumber(key, value, tag)
+		case hasValue && key == "len":
+			parsed.len, err = parseTagNumber(key, value, tag)
+		case hasValue && key == "format":
+			if _, ok := stringFormats[value]; !ok {
+				return parsed, fmt.Errorf("unknown format %q in gotypes tag %q; the known formats are email, url, uuid and datetime", value, tag)
+			}
+			parsed.format = value
+		case hasValue && key == "pattern":
+			parsed.pattern, err = regexp.Compile(value)
+			if err != nil {
+				err = fmt.Errorf("invalid pattern in gotypes tag %q: %w", tag, err)
+			}
+		case hasValue && key == "brand" && value != "":
+			parsed.brand = value
+		case hasValue && key == "default":
+			parsed.defaultValue = defaultLiteral(value)
+		case hasValue && key == "schema" && value != "":
+			parsed.schema = value
+		default:
+			return parsed, fmt.Errorf("unknown option %q in gotypes tag %q", option, tag)
+		}
+		if err != nil {
+			return parsed, err
+		}
+	}
+	if parsed.nullable && parsed.nonnull {
+		return parsed, fmt.Errorf("gotypes tag %q can't have both the nullable and the nonnull option", tag)
+	}
+	if parsed.hasNilPolicy && parsed.nonnull {
+		return parsed, fmt.Errorf("gotypes tag %q can't have both a nil policy and the nonnull option", tag)
+	}
+	if parsed.schema != "" && (parsed.hasNilPolicy || parsed.hasIntegerPolicy || parsed.refines()) {
+		return parsed, fmt.Errorf("the schema given in gotypes tag %q can't be combined with policies, constraints or formats", tag)
+	}
+	return parsed, nil
+}
+
+func parseTagNumber(key, value, tag string) (*float64, error) {
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsInf(n, 0) || math.IsNaN(n) {
+		return nil, fmt.Errorf("%s=%s in gotypes tag %q isn't a number", key, value, tag)
+	}
+	return &n, nil
+}
+
+// defaultLiteral returns the TypeScript literal of the given default value, which is JSON, unless it isn't valid JSON,
+// in which case it is a string.
+func defaultLiteral(value string) ts.Source {
+	if json.Valid([]byte(value)) {
+		return ts.AsSource(value)
+	}
+	return ts.StringLiteral(value)
+}
+
+var stringFormats = map[string]func(stringNode) stringNode{
+	"email":    stringNode.email,
+	"url":      stringNode.url,
+	"uuid":     stringNode.uuid,
+	"datetime": stringNode.datetime,
+}
+
+// refines tells whether the tag constrains the values of the field's schema.
+func (tag fieldTag) refines() bool {
+	return tag.min != nil || tag.max != nil || tag.len != nil || tag.format != "" || tag.pattern != nil
+}
+
+// refine applies the constraints, brand and readonly option of the tag to the given node of a field of type t,
+// which mustn't be nullable. If the constraints can't be applied, the node is returned without them.
+func (tag fieldTag) refine(t goinsp.Type, n node) (node, error) {
+	var err error
+	if tag.refines() {
+		switch structureOf(n).(type) {
+		case stringNode:
+			s, _ := asString(n)
+			n, err = tag.refineString(t, s)
+		case numberNode:
+			number, _ := asNumber(n)
+			n, err = tag.refineNumber(t, number)
+		case arrayNode:
+			a, _ := asArray(n)
+			n, err = tag.refineArray(t, a)
+		default:
+			err = fmt.Errorf("constraints can only be applied to fields whose schemas are strings, numbers or arrays, not that of %v; the schemas of named types can be refined with WithSchema", t)
+		}
+	}
+	if tag.brand != "" {
+		n = brandedNode{n, tag.brand}
+	}
+	if tag.readonly {
+		n = readonlyNode{n}
+	}
+	return n, err
+}
+
+func (tag fieldTag) refineString(t goinsp.Type, s stringNode) (node, error) {
+	if err := tag.checkLengths(t); err != nil {
+		return s, err
+	}
+	if tag.min != nil {
+		s = s.min(int(*tag.min))
+	}
+	if tag.max != nil {
+		s = s.max(int(*tag.max))
+	}
+	if tag.len != nil {
+		s = s.length(int(*tag.len))
+	}
+	if tag.format != "" {
+		s = stringFormats[tag.format](s)
+	}
+	if tag.pattern != nil {
+		s = s.regex(tag.pattern)
+	}
+	return s, nil
+}
+
+func (tag fieldTag) refineNumber(t goinsp.Type, n numberNode) (node, error) {
+	if tag.len != nil || tag.format != "" || tag.pattern != nil {
+		return n, fmt.Errorf("only min= and max= can be applied to fields of number type %v", t)
+	}
+	if tag.min != nil {
+		n = n.min(*tag.min)
+	}
+	if tag.max != nil {
+		n = n.max(*tag.max)
+	}
+	return n, nil
+}
+
+func (tag fieldTag) refineArray(t goinsp.Type, a arrayNode) (node, error) {
+	if tag.format != "" || tag.pattern != nil {
+		return a, fmt.Errorf("formats and patterns can't be applied to fields of array type %v", t)
+	}
+	if err := tag.checkLengths(t); err != nil {
+		return a, err
+	}
+	if tag.min != nil {
+		a = a.min(uint(*tag.min))
+	}
+	if tag.max != nil {
+		a = a.max(uint(*tag.max))
+	}
+	if tag.len != nil {
+		a = a.length(uint(*tag.len))
+	}
+	return a, nil
+}
+
+// checkLengths checks that the min=, max= and len= options of the tag, which constrain the length of the values of t,
+// are non-negative integers.
+func (tag fieldTag) checkLengths(t goinsp.Type) error {
+	for _, option := range []struct {
+		key   string
+		value *float64
+	}{{"min", tag.min}, {"max", tag.max}, {"len", tag.len}} {
+		if n := option.value; n != nil && (*n < 0 || *n != math.Trunc(*n)) {
+			return fmt.Errorf("%s=%v constrains the length of %v, so it must be a non-negative integer", option.key, *n, t)
+		}
+	}
+	return nil
+}
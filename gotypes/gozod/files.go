@@ -0,0 +1,142 @@
+package gozod
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
+	"github.com/softwaretechnik-berlin/goats/gotypes/ts"
+	"github.com/softwaretechnik-berlin/goats/gotypes/util"
+)
+
+// FilesOption configures GenerateFiles.
+type FilesOption func(*filesConfig)
+
+type filesConfig struct {
+	index bool
+}
+
+// WithIndexFile makes GenerateFiles write an `index.ts` barrel, which re-exports the declarations of all modules.
+func WithIndexFile() FilesOption {
+	return func(c *filesConfig) { c.index = true }
+}
+
+// GenerateFiles writes the declarations of the given mapper to one TypeScript module per Go package in the given
+// directory, e.g. `billing.ts` and `catalog/v2.ts`, see modulePaths, unless the mapper found problems while resolving
+// types, in which case it returns all of them instead.
+//
+// The declarations of each module are in the order of SupportingDeclarations, and modules import the declarations of
+// other modules they refer to, e.g. `import { Product } from "./catalog/v2";`.
+func GenerateFiles[S any, D typeScriptDeclaration[D]](mapper mapper[goinsp.Type, S, ts.Identifier, D], outDir string, options ...FilesOption) error {
+	if err := mapper.Err(); err != nil {
+		return err
+	}
+	var config filesConfig
+	for _, o := range options {
+		o(&config)
+	}
+
+	packages := packagedDeclarations(mapper)
+	paths, err := modulePaths(util.Map(packages, func(p packageDeclarations[D]) goinsp.ImportPath { return p.path }))
+	if err != nil {
+		return err
+	}
+	if config.index {
+		for _, p := range packages {
+			if paths[p.path] == "index" {
+				return fmt.Errorf("can't write index file, since the module of package %s is called index", p.path)
+			}
+		}
+	}
+	sources := make([]ts.Source, len(packages))
+	exporters := make(map[ts.Identifier]string)
+	for i, p := range packages {
+		sources[i] = statements(util.Map(p.declarations, D.TypeScript))
+		for _, name := range ts.ExportedNames(sources[i]) {
+			exporters[name] = paths[p.path]
+		}
+	}
+
+	var exports []ts.Source
+	for i, p := range packages {
+		module := paths[p.path]
+		source := ts.Module(sources[i], func(name ts.Identifier) (string, bool) {
+			exporter, ok := exporters[name]
+			if !ok || exporter == module {
+				return "", false
+			}
+			return moduleSpecifier(module, exporter), true
+		})
+		if err := writeModule(outDir, module, source); err != nil {
+			return err
+		}
+		exports = append(exports, ts.Sourcef("export * from %s;", ts.StringLiteral(moduleSpecifier("index", module))))
+	}
+	if config.index {
+		return writeModule(outDir, "index", ts.Statements(exports...).String())
+	}
+	return nil
+}
+
+// modulePaths returns the paths of the modules of the given Go packages, without the `.ts` extension: their import
+// paths relative to the closest directory containing all of them, e.g. `billing` and `catalog/v2` for
+// `example.com/shop/billing` and `example.com/shop/catalog/v2`. The import paths of the standard library's packages are
+// kept as they are, e.g. `time` and `encoding/json`.
+func modulePaths(packages []goinsp.ImportPath) (map[goinsp.ImportPath]string, error) {
+	dir := ""
+	for _, p := range packages {
+		if isStandardLibrary(p) {
+			continue
+		}
+		if dir == "" {
+			dir = path.Dir(string(p))
+		}
+		for dir != "." && !strings.HasPrefix(string(p), dir+"/") {
+			dir = path.Dir(dir)
+		}
+	}
+	paths := make(map[goinsp.ImportPath]string, len(packages))
+	packagesByPath := make(map[string]goinsp.ImportPath, len(packages))
+	for _, p := range packages {
+		modulePath := string(p)
+		if !isStandardLibrary(p) && dir != "." && dir != "" {
+			modulePath = strings.TrimPrefix(modulePath, dir+"/")
+		}
+		if other, ok := packagesByPath[modulePath]; ok {
+			return nil, fmt.Errorf("packages %s and %s would both be written to module %s", other, p, modulePath)
+		}
+		paths[p], packagesByPath[modulePath] = modulePath, p
+	}
+	return paths, nil
+}
+
+// isStandardLibrary tells whether the package of the given import path is part of the standard library, whose import
+// paths have no dots, unlike those of modules, e.g. `example.com/shop`.
+func isStandardLibrary(p goinsp.ImportPath) bool {
+	return !strings.ContainsRune(string(p), '.')
+}
+
+// moduleSpecifier returns the relative module specifier under which the module of the given path imports the module of
+// the other given path, e.g. `../billing` from `catalog/v2` for `billing`.
+func moduleSpecifier(from, to string) string {
+	relative, err := filepath.Rel(filepath.Dir(filepath.FromSlash(from)), filepath.FromSlash(to))
+	if err != nil {
+		panic(err) // both paths are relative, so one is always relative to the other
+	}
+	relative = filepath.ToSlash(relative)
+	if !strings.HasPrefix(relative, "../") {
+		relative = "./" + relative
+	}
+	return relative
+}
+
+func writeModule(outDir, module, source string) error {
+	fileName := filepath.Join(outDir, filepath.FromSlash(module)+".ts")
+	if err := os.MkdirAll(filepath.Dir(fileName), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(fileName, []byte(source), 0o644)
+}
@@ -0,0 +1,132 @@
+package gozod
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+)
+
+// instantiatesFactory tells whether the given type is an instantiation of a generic type that is declared as a factory,
+// in which case the type's schema invokes that factory.
+//
+// Instantiations with type arguments that refer to type parameters, which occur in the declarations of other factories,
+// always invoke factories, since there's no other way to refer to their type parameters.
+func (b nodeBuilder) instantiatesFactory(t goinsp.Type) bool {
+	if !strings.ContainsRune(t.Name().String(), '[') {
+		return false
+	}
+	if _, ok := lookupConfig(b.factories, t); ok || b.genericFactories {
+		return true
+	}
+	return containsTypeParameters(t)
+}
+
+// invokeFactory returns the schema of the given instantiation of a generic type, invoking the factory declared for the
+// generic type with the schemas of the type arguments.
+func (b nodeBuilder) invokeFactory(t goinsp.Type, nodes Resolver[goinsp.Type, node]) node {
+	generic, err := b.genericType(t)
+	if err != nil {
+		return b.invalid(err)
+	}
+	return invocationNode{nodes.Resolve(generic), mapSlice(typeArguments(generic, t), nodes.Resolve)}
+}
+
+// genericType returns the declaration of the generic type the given type instantiates,
+// whose type arguments are its type parameters.
+func (b nodeBuilder) genericType(t goinsp.Type) (goinsp.Type, error) {
+	if generic, ok := t.WithoutTypeArguments().(goinsp.Type); ok {
+		return generic, nil
+	}
+	// Reflection doesn't provide generic types, so we load them from source.
+	generic, err := b.staticLoader.Lookup(t.PkgPath(), t.WithoutTypeArguments().Name())
+	if err != nil {
+		return nil, fmt.Errorf("can't find the declaration of the generic type of %v: %w", t, err)
+	}
+	return generic, nil
+}
+
+// typeParameters returns the type parameters of the given type, if it is a generic type that hasn't been instantiated.
+func typeParameters(t goinsp.Type) []goinsp.Type {
+	if strings.ContainsRune(t.Name().String(), '[') {
+		return nil
+	}
+	return t.TypeArguments().Value
+}
+
+// typeArguments returns the type arguments with which the given instance instantiates the given generic type.
+//
+// Where they aren't available, as with reflection, they're inferred from the types of the instance's fields.
+// Type parameters that no field refers to don't affect the JSON representation of the instance, so their type
+// arguments are taken to be `any`.
+func typeArguments(generic goinsp.Type, instance goinsp.Type) []goinsp.Type {
+	if arguments := instance.TypeArguments(); arguments.Available {
+		return arguments.Value
+	}
+	inferred := make(map[goinsp.Type]goinsp.Type)
+	inferTypeArguments(generic, instance, inferred, make(map[[2]goinsp.Type]bool))
+	return mapSlice(typeParameters(generic), func(parameter goinsp.Type) goinsp.Type {
+		if argument, ok := inferred[parameter]; ok {
+			return argument
+		}
+		return reflective.TypeFor[any]()
+	})
+}
+
+// inferTypeArguments matches the structure of the given generic type against that of the given instance,
+// recording which types take the place of the type parameters referred to by the generic type.
+func inferTypeArguments(generic goinsp.Type, instance goinsp.Type, inferred map[goinsp.Type]goinsp.Type, visited map[[2]goinsp.Type]bool) {
+	if generic.IsTypeParameter() {
+		if _, ok := inferred[generic]; !ok {
+			inferred[generic] = instance
+		}
+		return
+	}
+	pair := [2]goinsp.Type{generic, instance}
+	if visited[pair] || !containsTypeParameters(generic) || generic.Kind() != instance.Kind() {
+		return
+	}
+	visited[pair] = true
+	switch generic.Kind() {
+	case reflect.Array, reflect.Pointer, reflect.Slice:
+		inferTypeArguments(generic.Elem(), instance.Elem(), inferred, visited)
+	case reflect.Map:
+		inferTypeArguments(generic.Key(), instance.Key(), inferred, visited)
+		inferTypeArguments(generic.Elem(), instance.Elem(), inferred, visited)
+	case reflect.Struct:
+		if generic.NumField() != instance.NumField() {
+			return
+		}
+		for i := range generic.NumField() {
+			inferTypeArguments(generic.Field(i).Type(), instance.Field(i).Type(), inferred, visited)
+		}
+	}
+}
+
+// containsTypeParameters tells whether the given type refers to type parameters,
+// i.e. whether it only occurs in the declaration of a generic type.
+func containsTypeParameters(t goinsp.Type) bool {
+	if t.IsTypeParameter() {
+		return true
+	}
+	if t.Name() != "" {
+		arguments := t.TypeArguments()
+		return arguments.Available && slices.ContainsFunc(arguments.Value, containsTypeParameters)
+	}
+	switch t.Kind() {
+	case reflect.Array, reflect.Pointer, reflect.Slice:
+		return containsTypeParameters(t.Elem())
+	case reflect.Map:
+		return containsTypeParameters(t.Key()) || containsTypeParameters(t.Elem())
+	case reflect.Struct:
+		for i := range t.NumField() {
+			if containsTypeParameters(t.Field(i).Type()) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -1,89 +1,212 @@
 package gozod
 
 import (
+	"cmp"
+	"encoding/json"
+	"reflect"
+	"slices"
 	"strings"
+	"unicode"
 
 	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
 )
 
-func forEachTopLevelJSONFieldAndEmbeddedType(
-	t goinsp.Type,
-	visitPropertyField func(name string, field goinsp.StructField, tag string),
-	visitEmbeddedJSONType func(t goinsp.Type),
-) {
-	for i := range t.NumField() {
-		field := t.Field(i)
-		tag := field.Tag.Get("json")
-		if tag == `-` {
+// jsonField is a struct field that encoding/json marshals as an object property,
+// either declared by the struct itself or promoted from a struct embedded in it.
+type jsonField struct {
+	name  string
+	field goinsp.StructField
+	tag   string
+	// declaringType is the struct type declaring the field.
+	declaringType goinsp.Type
+	// index is the sequence of field indices leading to the field, like reflect.StructField.Index.
+	index []int
+	// tagged tells whether the name was given in the json tag.
+	tagged bool
+	// viaPointer tells whether the field is promoted through an embedded pointer,
+	// in which case it is omitted when the pointer is nil.
+	viaPointer bool
+	// embedded tells whether the field is an embedded struct standing in for the property of the given name that it
+	// promotes from elsewhere than its fields, see jsonFields.
+	embedded bool
+}
+
+// embeddedType returns the type of the struct embedded as the field, which may be embedded through a pointer to it.
+func (f jsonField) embeddedType() goinsp.Type {
+	if t := f.field.Type(); t.Kind() == reflect.Pointer {
+		return t.Elem()
+	}
+	return f.field.Type()
+}
+
+// jsonFields returns the fields of the given struct type that encoding/json marshals, in the order it marshals them.
+//
+// It follows the rules encoding/json uses for fields promoted from embedded structs and pointers to structs:
+// of the fields with the same property name, the shallowest one wins, tagged fields win ties at the same depth,
+// and the name is dropped altogether if that doesn't leave a single winner.
+//
+// The fields of embedded structs that promotes returns false for aren't looked at. The embedded structs stand in for the
+// property names that properties returns for them instead, e.g. those of their configured schemas, which are subject
+// to the same rules as the fields they would otherwise promote.
+func jsonFields(t goinsp.Type, promotes func(goinsp.Type) bool, properties func(embedded jsonField) []string) []jsonField {
+	type embedding struct {
+		t          goinsp.Type
+		index      []int
+		viaPointer bool
+	}
+
+	// This mirrors typeFields in encoding/json: a breadth-first search over the embedded structs.
+	var current []embedding
+	next := []embedding{{t, nil, false}}
+	var count, nextCount map[goinsp.Type]int
+	visited := make(map[goinsp.Type]bool)
+	var fields []jsonField
+	for len(next) > 0 {
+		current, next = next, nil
+		count, nextCount = nextCount, make(map[goinsp.Type]int)
+		for _, e := range current {
+			if visited[e.t] {
+				continue
+			}
+			visited[e.t] = true
+			for i := range e.t.NumField() {
+				field := e.t.Field(i)
+				if field.Anonymous {
+					embedded := field.Type()
+					if embedded.Kind() == reflect.Pointer {
+						embedded = embedded.Elem()
+					}
+					if !field.IsExported() && embedded.Kind() != reflect.Struct {
+						// Ignore embedded fields of unexported non-struct types.
+						continue
+					}
+					// Embedded structs of unexported types may still contribute exported fields.
+				} else if !field.IsExported() {
+					continue
+				}
+				tag := field.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, _, _ := strings.Cut(tag, ",")
+				if !isValidJSONTag(name) {
+					name = ""
+				}
+				index := append(slices.Clip(e.index), i)
+
+				fieldType := field.Type()
+				viaPointer := e.viaPointer
+				if fieldType.Name() == "" && fieldType.Kind() == reflect.Pointer {
+					fieldType = fieldType.Elem()
+					viaPointer = true
+				}
+				if name != "" || !field.Anonymous || fieldType.Kind() != reflect.Struct {
+					tagged := name != ""
+					if name == "" {
+						name = field.Name
+					}
+					f := jsonField{name, field, tag, e.t, index, tagged, e.viaPointer, false}
+					fields = append(fields, f)
+					if count[e.t] > 1 {
+						// The struct was embedded more than once at this depth, so its fields are ambiguous.
+						// Adding a duplicate makes sure they get dropped.
+						fields = append(fields, f)
+					}
+					continue
+				}
+
+				if !promotes(fieldType) {
+					for j, name := range properties(jsonField{"", field, tag, e.t, index, false, viaPointer, true}) {
+						// The properties stand in for the fields of the embedded struct, one level deeper.
+						f := jsonField{name, field, tag, e.t, append(slices.Clip(index), j), hasTaggedJSONField(fieldType, name), viaPointer, true}
+						fields = append(fields, f)
+						if count[e.t] > 1 {
+							fields = append(fields, f)
+						}
+					}
+					continue
+				}
+
+				nextCount[fieldType]++
+				if nextCount[fieldType] == 1 {
+					next = append(next, embedding{fieldType, index, viaPointer})
+				}
+			}
+		}
+	}
+
+	var dominant []jsonField
+	for _, f := range fields {
+		if slices.ContainsFunc(dominant, func(d jsonField) bool { return d.name == f.name }) {
 			continue
 		}
-		if field.Anonymous && tag == "" {
-			visitEmbeddedJSONType(field.Type())
-		} else if field.IsExported() {
-			visitPropertyField(jsonPropertyName(field, tag), field, tag)
+		if d, ok := dominantJSONField(fields, f.name); ok {
+			dominant = append(dominant, d)
 		}
 	}
-	return
+	slices.SortFunc(dominant, func(a, b jsonField) int { return slices.Compare(a.index, b.index) })
+	return dominant
 }
 
-func jsonPropertyName(field goinsp.StructField, tag string) string {
-	name, _, _ := strings.Cut(tag, ",")
-	if name != "" {
-		return name
+// hasTaggedJSONField tells whether the given struct type has a field that encoding/json marshals as the property of the
+// given name because of its json tag, which the property given for the struct elsewhere is assumed to correspond to.
+func hasTaggedJSONField(t goinsp.Type, name string) bool {
+	return slices.ContainsFunc(jsonFields(t, func(goinsp.Type) bool { return true }, nil), func(f jsonField) bool {
+		return f.name == name && f.tagged
+	})
+}
+
+// dominantJSONField returns the field with the given name that encoding/json marshals, if there is one.
+func dominantJSONField(fields []jsonField, name string) (jsonField, bool) {
+	var candidates []jsonField
+	for _, f := range fields {
+		if f.name == name {
+			candidates = append(candidates, f)
+		}
+	}
+	depth := slices.MinFunc(candidates, func(a, b jsonField) int { return cmp.Compare(len(a.index), len(b.index)) })
+	candidates = slices.DeleteFunc(candidates, func(f jsonField) bool { return len(f.index) > len(depth.index) })
+	if len(candidates) == 1 {
+		return candidates[0], true
 	}
-	return field.Name
+	candidates = slices.DeleteFunc(candidates, func(f jsonField) bool { return !f.tagged })
+	if len(candidates) == 1 {
+		return candidates[0], true
+	}
+	return jsonField{}, false
 }
 
-//func forEachJsonProperty(t goinsp.Type, visit func(name string, tag string, field goinsp.StructField)) goinsp.Type {
-//	type visibility struct {
-//		level      int
-//		jsonTagged bool
-//		count      int
-//	}
-//	visibilityByPropertyName := make(map[string]visibility)
-//	forEachPotentialJSONProperty(0, t, func(nestingLevel int, field goinsp.StructField, jsonTag string, propertyName string) {
-//		vis, ok := visibilityByPropertyName[propertyName]
-//		jsonTagged := jsonTag == ""
-//		if !ok || nestingLevel < vis.level || nestingLevel == vis.level && jsonTagged && !vis.jsonTagged {
-//			visibilityByPropertyName[propertyName] = visibility{nestingLevel, jsonTagged, 1}
-//		} else if vis.level == nestingLevel && vis.jsonTagged == jsonTagged {
-//			visibilityByPropertyName[propertyName] = visibility{nestingLevel, jsonTagged, vis.count + 1}
-//		}
-//	})
-//	propertyCount := 0
-//	representation := forEachPotentialJSONProperty(0, t, func(nestingLevel int, field goinsp.StructField, tag string, name string) {
-//		vis := visibilityByPropertyName[name]
-//		if vis.level == nestingLevel && vis.jsonTagged == (tag == "") && vis.count == 1 {
-//			propertyCount++
-//			visit(name, tag, field)
-//		}
-//	})
-//	if propertyCount == 0 {
-//		return representation
-//	}
-//	return nil
-//}
+// isValidJSONTag mirrors isValidTag in encoding/json, which ignores names in json tags that don't satisfy it.
+func isValidJSONTag(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", c):
+			// Backslash and quote chars are reserved, but
+			// otherwise any punctuation chars are allowed
+			// in a tag name.
+		case !unicode.IsLetter(c) && !unicode.IsDigit(c):
+			return false
+		}
+	}
+	return true
+}
 
-//func forEachPotentialJSONProperty(nestingLevel int, t goinsp.Type, visit func(nestingLevel int, field goinsp.StructField, tag string, name string)) (representationType goinsp.Type) {
-//	for i := range t.NumField() {
-//		field := t.Field(i)
-//		tag := field.Tag.Get("json")
-//		if tag == "-" {
-//			continue
-//		}
-//		if field.Anonymous && tag == "" {
-//			t := field.Type()
-//			switch t.Kind() {
-//			case reflect.Array:
-//				representationType = t
-//			case reflect.Struct:
-//				representationType = forEachPotentialJSONProperty(nestingLevel+1, t, visit)
-//			default:
-//				panic(t)
-//			}
-//		} else if field.IsExported() {
-//			visit(nestingLevel, field, tag, jsonPropertyName(field, tag))
-//		}
-//	}
-//	return
-//}
+// promotedJSONMarshaller returns the type of the field embedded in the given struct type whose MarshalJSON method is
+// promoted to the struct's values with addressability a, if there is one, since encoding/json then marshals them using
+// that method.
+func promotedJSONMarshaller(t goinsp.Type, a goinsp.Addressability) (goinsp.Type, bool) {
+	if !a.Implements(t, reflective.TypeFor[json.Marshaler]()) {
+		return nil, false
+	}
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if field.Anonymous && a.Implements(field.Type(), reflective.TypeFor[json.Marshaler]()) {
+			return field.Type(), true
+		}
+	}
+	return nil, false
+}
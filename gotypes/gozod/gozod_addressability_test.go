@@ -0,0 +1,120 @@
+package gozod_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+	"github.com/softwaretechnik-berlin/goats/gotypes/gozod"
+)
+
+type (
+	// pointerText marshals itself to text only when encoding/json can take its address.
+	pointerText struct {
+		Value string
+	}
+	pointerByte  uint8
+	pointerJSON  struct{}
+	addressables struct {
+		Field   pointerText
+		Pointer *pointerText
+		Slice   []pointerText
+		Map     map[string]pointerText
+		Array   [1]pointerText
+		Bytes   []pointerByte
+	}
+)
+
+// pointerLabels marshals itself to comma-separated labels only when encoding/json can take its address.
+type pointerLabels struct {
+	Labels []string
+}
+
+// MarshalText joins the labels.
+//
+//gotypes:transform s => s.split(",")
+func (p *pointerLabels) MarshalText() ([]byte, error) {
+	return []byte(strings.Join(p.Labels, ",")), nil
+}
+
+func (p *pointerText) MarshalText() ([]byte, error) {
+	return []byte(p.Value), nil
+}
+
+func (b *pointerByte) MarshalText() ([]byte, error) {
+	return []byte{'0' + byte(*b)}, nil
+}
+
+func (p *pointerJSON) MarshalJSON() ([]byte, error) {
+	return []byte(`"json"`), nil
+}
+
+func TestMarshallersWithPointerReceiversDependOnAddressability(t *testing.T) {
+	value := addressables{
+		Field:   pointerText{"a"},
+		Pointer: &pointerText{"b"},
+		Slice:   []pointerText{{"c"}},
+		Map:     map[string]pointerText{"d": {"e"}},
+		Array:   [1]pointerText{{"f"}},
+		Bytes:   []pointerByte{1},
+	}
+	unaddressable, err := json.Marshal(value)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"Field":{"Value":"a"},"Pointer":"b","Slice":["c"],"Map":{"d":{"Value":"e"}},"Array":[{"Value":"f"}],"Bytes":["1"]}`, string(unaddressable))
+	addressable, err := json.Marshal(&value)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"Field":"a","Pointer":"b","Slice":["c"],"Map":{"d":{"Value":"e"}},"Array":["f"],"Bytes":["1"]}`, string(addressable))
+
+	m := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader))
+	assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[addressables]()).Value, "", `addressables`)
+	assert.NoError(t, m.Err())
+	assertTypeScript(t, gozod.SupportingDeclarations(m), z, `/**
+ * pointerText corresponds to Go type gozod_test.pointerText (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ * The comment on the original Go type follows.
+ *
+ * pointerText marshals itself to text only when encoding/json can take its address.
+ */
+export const pointerText = z.object({ Value: z.string() });
+export type pointerText = z.infer<typeof pointerText>;
+
+/**
+ * addressables corresponds to Go type gozod_test.addressables (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const addressables = z.object({
+    Field: z.union([
+        z.string(),
+        pointerText,
+    ]),
+    Pointer: z.string().nullable(),
+    Slice: z.array(z.string()).nullable().transform(a => a ?? []),
+    Map: z.record(z.string(), pointerText).nullable().transform(r => r ?? {}),
+    Array: z.array(z.union([
+        z.string(),
+        pointerText,
+    ])).length(1),
+    Bytes: z.array(z.string()).nullable().transform(a => a ?? []),
+});
+export type addressables = z.infer<typeof addressables>;
+`)
+}
+
+func TestJSONMarshallersWithPointerReceiversAreReported(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader))
+	m.Resolve(reflective.TypeFor[[]pointerJSON]())
+	assert.EqualError(t, m.Err(), "[]gozod_test.pointerJSON[]: pointers to gozod_test.pointerJSON implement json.Marshaler, so the JSON of its addressable values can't be derived from its Go type; give its schema, e.g. with WithSchema, or samples of pointers to its values with WithJSONSamples")
+
+	m = gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader), gozod.When[pointerJSON]().JSONSamples(&pointerJSON{}))
+	assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[[]pointerJSON]()).Value, z, `z.array(pointerJSON).nullable().transform(a => a ?? [])`)
+	assert.NoError(t, m.Err())
+}
+
+func TestAddressableValuesHaveTheSchemasGivenByTheDirectivesOfMethodsWithPointerReceivers(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader))
+	assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[[]pointerLabels]()).Value, z, `z.array(z.string().transform(s => s.split(","))).nullable().transform(a => a ?? [])`)
+	assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[pointerLabels]()).Value, "", `pointerLabels`)
+	assert.NoError(t, m.Err())
+}
@@ -0,0 +1,91 @@
+package gozod_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+	"github.com/softwaretechnik-berlin/goats/gotypes/gozod"
+)
+
+type bundleMoney struct {
+	Cents    int32
+	Currency string
+}
+
+type bundleProduct struct {
+	Name  string
+	Price bundleMoney
+}
+
+type bundleRefund struct {
+	Amount bundleMoney
+	Reason string
+}
+
+type bundleFolder struct {
+	Files []bundleFile
+}
+
+type bundleFile struct {
+	Folder *bundleFolder
+}
+
+func TestGenerateBundles(t *testing.T) {
+	m := gozod.NewTypesMapper(gozod.WithCommentsLoader(withoutComments{}))
+	// The folder is resolved first, so that the file only refers to it lazily.
+	require.NoError(t, m.ResolveAll(reflective.TypeFor[bundleFolder]()))
+	dir := t.TempDir()
+	require.NoError(t, gozod.GenerateBundles(m, dir,
+		gozod.Bundle{Name: "public", Roots: []goinsp.Type{reflective.TypeFor[bundleProduct]()}},
+		gozod.Bundle{Name: "admin", Roots: []goinsp.Type{reflective.TypeFor[bundleRefund](), reflective.TypeFor[bundleFile]()}},
+	))
+
+	assertModule(t, dir, "common", `/**
+ * bundleMoney corresponds to Go type gozod_test.bundleMoney (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export interface bundleMoney {
+    Cents: number;
+    Currency: string;
+}
+`)
+	assertModule(t, dir, "public", `import { bundleMoney } from "./common";
+
+/**
+ * bundleProduct corresponds to Go type gozod_test.bundleProduct (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export interface bundleProduct {
+    Name: string;
+    Price: bundleMoney;
+}
+`)
+	assertModule(t, dir, "admin", `import { bundleMoney } from "./common";
+
+/**
+ * bundleFile corresponds to Go type gozod_test.bundleFile (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export interface bundleFile { Folder: bundleFolder | null }
+
+/**
+ * bundleFolder corresponds to Go type gozod_test.bundleFolder (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export interface bundleFolder { Files: bundleFile[] | null }
+
+/**
+ * bundleRefund corresponds to Go type gozod_test.bundleRefund (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export interface bundleRefund {
+    Amount: bundleMoney;
+    Reason: string;
+}
+`)
+}
+
+func TestGenerateBundlesRejectsBundlesCalledCommon(t *testing.T) {
+	m := gozod.NewTypesMapper()
+	err := gozod.GenerateBundles(m, t.TempDir(), gozod.Bundle{Name: "common", Roots: []goinsp.Type{reflective.TypeFor[bundleMoney]()}})
+	assert.EqualError(t, err, "bundles can't be called common, since there is already a module of that name")
+}
@@ -0,0 +1,77 @@
+package gozod_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+	"github.com/softwaretechnik-berlin/goats/gotypes/gozod"
+)
+
+// documentedStruct has documented fields.
+type documentedStruct struct {
+	// Name is what the thing is called.
+	Name  string
+	Count int // Count of things
+	Plain bool
+}
+
+const documentedStructDeclaration = `/**
+ * documentedStruct corresponds to Go type gozod_test.documentedStruct (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ * The comment on the original Go type follows.
+ *
+ * documentedStruct has documented fields.
+ */
+export const documentedStruct = z.object({
+    /**
+     * Name is what the thing is called.
+     */
+    Name: z.string(),
+    /**
+     * Count of things
+     */
+    Count: z.number().int(),
+    Plain: z.boolean(),
+});
+export type documentedStruct = z.infer<typeof documentedStruct>;
+`
+
+func TestFieldCommentsAreEmittedAsJSDoc(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader))
+	assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[documentedStruct]()).Value, "", `documentedStruct`)
+	assertTypeScript(t, gozod.SupportingDeclarations(m), z, documentedStructDeclaration)
+}
+
+func TestFieldCommentsOfStaticallyLoadedTypesAreEmittedAsJSDoc(t *testing.T) {
+	statically, err := staticLoader.Lookup("github.com/softwaretechnik-berlin/goats/gotypes/gozod_test", "documentedStruct")
+	require.NoError(t, err)
+
+	m := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader))
+	m.Resolve(statically)
+	assertTypeScript(t, gozod.SupportingDeclarations(m), z, documentedStructDeclaration)
+}
+
+func TestFieldDescriptions(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader), gozod.WithFieldDescriptions())
+	m.Resolve(reflective.TypeFor[documentedStruct]())
+	assertTypeScript(t, gozod.SupportingDeclarations(m), z, `/**
+ * documentedStruct corresponds to Go type gozod_test.documentedStruct (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ * The comment on the original Go type follows.
+ *
+ * documentedStruct has documented fields.
+ */
+export const documentedStruct = z.object({
+    /**
+     * Name is what the thing is called.
+     */
+    Name: z.string().describe("Name is what the thing is called."),
+    /**
+     * Count of things
+     */
+    Count: z.number().int().describe("Count of things"),
+    Plain: z.boolean(),
+});
+export type documentedStruct = z.infer<typeof documentedStruct>;
+`)
+}
@@ -0,0 +1,180 @@
+package gozod_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+	"github.com/softwaretechnik-berlin/goats/gotypes/gozod"
+	"github.com/softwaretechnik-berlin/goats/gotypes/zod"
+)
+
+// instant is a point in time.
+type instant struct{ t time.Time }
+
+// MarshalText marshals the instant in RFC 3339 format.
+//
+//gotypes:schema z.string().datetime({ offset: true })
+func (i instant) MarshalText() ([]byte, error) {
+	return i.t.MarshalText()
+}
+
+func (i *instant) UnmarshalText(text []byte) error {
+	return i.t.UnmarshalText(text)
+}
+
+// pixels is a length on the screen.
+//
+//gotypes:template {}px
+type pixels uint
+
+func (p pixels) MarshalText() ([]byte, error) {
+	return fmt.Appendf(nil, "%dpx", p), nil
+}
+
+func (p *pixels) UnmarshalText(text []byte) error {
+	n, err := strconv.ParseUint(strings.TrimSuffix(string(text), "px"), 10, 0)
+	*p = pixels(n)
+	return err
+}
+
+type tags []string
+
+// MarshalJSON marshals the tags as a comma-separated string.
+//
+//gotypes:schema z.string()
+//gotypes:transform s => s.split(",")
+func (t tags) MarshalJSON() ([]byte, error) {
+	return fmt.Appendf(nil, "%q", strings.Join(t, ",")), nil
+}
+
+func (t *tags) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*t = strings.Split(s, ",")
+	return nil
+}
+
+// MarshalText is never used for JSON, since MarshalJSON takes precedence.
+//
+//gotypes:schema z.never()
+func (t tags) MarshalText() ([]byte, error) {
+	return []byte(strings.Join(t, ",")), nil
+}
+
+// sessionID identifies a session.
+type sessionID struct{ id string }
+
+// MarshalText marshals the session ID as a UUID, but only for addressable values, whose pointers have the method.
+//
+//gotypes:schema z.string().uuid()
+func (s *sessionID) MarshalText() ([]byte, error) {
+	return []byte(s.id), nil
+}
+
+type session struct {
+	IDs    []sessionID
+	Parent *sessionID
+}
+
+// unknownDirective has a directive gozod doesn't understand.
+//
+//gotypes:frobnicate
+type unknownDirective string
+
+func TestDirectives(t *testing.T) {
+	assertSchemaWithSupportFor[instant](t,
+		``, `instant`,
+		z, `/**
+ * instant corresponds to Go type gozod_test.instant (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ * The comment on the original Go type follows.
+ *
+ * instant is a point in time.
+ */
+export const instant = z.string().datetime({ offset: true });
+export type instant = z.infer<typeof instant>;
+`,
+		examples[instant]{
+			simpleExample(instant{time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC)}, `"2024-02-29T12:00:00Z"`),
+		},
+		nil,
+	)
+	assertSchemaWithSupportFor[pixels](t,
+		``, `pixels`,
+		z, `/**
+ * pixels corresponds to Go type gozod_test.pixels (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ * The comment on the original Go type follows.
+ *
+ * pixels is a length on the screen.
+ */
+export const pixels = z.string().transform((s, ctx) => {
+    const re = /^(\d+)px$/;
+    const match = re.exec(s);
+    if (!match) {
+        ctx.addIssue({ code: z.ZodIssueCode.custom, message: "expected string of the form \"{}px\" matching " + re });
+        return z.NEVER;
+    }
+    return z.number().nonnegative().int().parse(Number(match[1]));
+}).brand("pixels");
+export type pixels = z.infer<typeof pixels>;
+`,
+		examples[pixels]{
+			simpleExample[pixels](42, `"42px"`),
+		},
+		nil,
+	)
+	assertSchemaWithSupportFor[tags](t,
+		``, `tags`,
+		z, `/**
+ * tags corresponds to Go type gozod_test.tags (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const tags = z.string().transform(s => s.split(","));
+export type tags = z.infer<typeof tags>;
+`,
+		examples[tags]{
+			example[tags]{tags{"a", "b"}, nil, `"a,b"`},
+		},
+		nil,
+	)
+}
+
+func TestDirectivesOfMethodsWithPointerReceiversApplyToAddressableValues(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader))
+	assert.NoError(t, m.ResolveAll(reflective.TypeFor[session]()))
+	assertTypeScript(t, gozod.SupportingDeclarations(m), z, `/**
+ * session corresponds to Go type gozod_test.session (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const session = z.object({
+    IDs: z.array(z.string().uuid()).nullable().transform(a => a ?? []),
+    Parent: z.string().uuid().nullable(),
+});
+export type session = z.infer<typeof session>;
+`)
+}
+
+func TestConfigurationTakesPrecedenceOverDirectives(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader), gozod.When[instant]().Schema(zod.String()))
+	m.Resolve(reflective.TypeFor[instant]())
+	assertTypeScript(t, gozod.SupportingDeclarations(m), z, `/**
+ * instant corresponds to Go type gozod_test.instant (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ * The comment on the original Go type follows.
+ *
+ * instant is a point in time.
+ */
+export const instant = z.string();
+export type instant = z.infer<typeof instant>;
+`)
+}
+
+func TestUnknownDirectivesAreRejected(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader))
+	assert.EqualError(t, m.ResolveAll(reflective.TypeFor[unknownDirective]()), "gozod_test.unknownDirective: unknown directive //gotypes:frobnicate on gozod_test.unknownDirective")
+}
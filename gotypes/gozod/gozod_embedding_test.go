@@ -0,0 +1,246 @@
+package gozod_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+	"github.com/softwaretechnik-berlin/goats/gotypes/gozod"
+	"github.com/softwaretechnik-berlin/goats/gotypes/ts"
+)
+
+type (
+	embeddableA struct {
+		A string
+		B bool
+	}
+	embeddableTaggedA struct {
+		A int `json:"A"`
+		C bool
+	}
+	embeddableUntaggedA struct {
+		A float64
+		D bool
+	}
+	EmbeddableLabel string
+	EmbeddableBase  struct {
+		ID   string
+		Kind string `json:"kind,omitempty"`
+	}
+)
+
+type (
+	embeddingUnexportedNonStruct = struct{ string }
+	embeddingExportedNonStruct   = struct{ EmbeddableLabel }
+	shadowingEmbeddedField       = struct {
+		A bool
+		embeddableA
+	}
+	tieBrokenByTag = struct {
+		embeddableA
+		embeddableTaggedA
+	}
+	ambiguousEmbeddedFields = struct {
+		embeddableA
+		embeddableUntaggedA
+	}
+	embeddingPointer = struct {
+		Name string
+		*EmbeddableBase
+	}
+	embeddingWithName = struct {
+		embeddableA `json:"inner"`
+	}
+	embeddedTwiceA         struct{ embeddableA }
+	embeddedTwiceB         struct{ embeddableA }
+	embeddingSameTypeTwice = struct {
+		embeddedTwiceA
+		embeddedTwiceB
+	}
+)
+
+type (
+	embeddableDiscriminated struct{ ID string }
+	embeddingDiscriminated  struct {
+		Name string
+		ID   int
+		*embeddableDiscriminated
+	}
+	embeddableAudited         struct{ At string }
+	embeddingTwoDiscriminated struct {
+		*embeddableDiscriminated
+		embeddableAudited
+	}
+	embeddableTransformed struct{ N int }
+	embeddingTransformed  struct{ embeddableTransformed }
+)
+
+func TestEmbeddedTypes(t *testing.T) {
+	// embedded fields of unexported non-struct types are ignored
+	assertSimpleSchemaFor[embeddingUnexportedNonStruct](t,
+		z, `z.object({})`,
+		examples[embeddingUnexportedNonStruct]{
+			example[embeddingUnexportedNonStruct]{embeddingUnexportedNonStruct{}, []embeddingUnexportedNonStruct{{"foo"}}, `{}`},
+		},
+		rejects{`null`, `undefined`, `"foo"`},
+	)
+
+	// embedded fields of exported non-struct types are properties
+	assertSchemaWithSupportFor[embeddingExportedNonStruct](t,
+		z, `z.object({ EmbeddableLabel: EmbeddableLabel })`,
+		z, `/**
+ * EmbeddableLabel corresponds to Go type gozod_test.EmbeddableLabel (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const EmbeddableLabel = z.string().brand("EmbeddableLabel");
+export type EmbeddableLabel = z.infer<typeof EmbeddableLabel>;
+`,
+		examples[embeddingExportedNonStruct]{
+			simpleExample(embeddingExportedNonStruct{"foo"}, `{"EmbeddableLabel":"foo"}`),
+		},
+		rejects{`null`, `undefined`, `"foo"`},
+	)
+
+	// shallower fields shadow deeper ones
+	assertSimpleSchemaFor[shadowingEmbeddedField](t,
+		z, `z.object({
+    A: z.boolean(),
+    B: z.boolean(),
+})`,
+		examples[shadowingEmbeddedField]{
+			simpleExample(shadowingEmbeddedField{A: true, embeddableA: embeddableA{B: true}}, `{"A":true,"B":true}`),
+		},
+		rejects{`null`, `undefined`, `{}`},
+	)
+
+	// tagged fields win ties between fields at the same depth
+	assertSimpleSchemaFor[tieBrokenByTag](t,
+		z, `z.object({
+    B: z.boolean(),
+    A: z.number().int(),
+    C: z.boolean(),
+})`,
+		examples[tieBrokenByTag]{
+			simpleExample(tieBrokenByTag{embeddableA{B: true}, embeddableTaggedA{A: 1, C: true}}, `{"B":true,"A":1,"C":true}`),
+		},
+		rejects{`null`, `undefined`, `{}`},
+	)
+
+	// otherwise, fields with the same name at the same depth are dropped
+	assertSimpleSchemaFor[ambiguousEmbeddedFields](t,
+		z, `z.object({
+    B: z.boolean(),
+    D: z.boolean(),
+})`,
+		examples[ambiguousEmbeddedFields]{
+			simpleExample(ambiguousEmbeddedFields{embeddableA{B: true}, embeddableUntaggedA{D: true}}, `{"B":true,"D":true}`),
+		},
+		rejects{`null`, `undefined`, `{}`},
+	)
+
+	// fields promoted through pointers are omitted when the pointer is nil
+	assertSimpleSchemaFor[embeddingPointer](t,
+		z, `z.object({
+    Name: z.string(),
+    ID: z.string().optional(),
+    kind: z.string().optional(),
+})`,
+		examples[embeddingPointer]{
+			simpleExample(embeddingPointer{Name: "foo"}, `{"Name":"foo"}`),
+			simpleExample(embeddingPointer{Name: "foo", EmbeddableBase: &EmbeddableBase{ID: "bar"}}, `{"Name":"foo","ID":"bar"}`),
+		},
+		rejects{`null`, `undefined`, `{}`},
+	)
+
+	// embedded structs with a name in their json tag are properties
+	assertSchemaWithSupportFor[embeddingWithName](t,
+		z, `z.object({ inner: embeddableA })`,
+		z, `/**
+ * embeddableA corresponds to Go type gozod_test.embeddableA (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const embeddableA = z.object({
+    A: z.string(),
+    B: z.boolean(),
+});
+export type embeddableA = z.infer<typeof embeddableA>;
+`,
+		examples[embeddingWithName]{
+			simpleExample(embeddingWithName{embeddableA{A: "foo", B: true}}, `{"inner":{"A":"foo","B":true}}`),
+		},
+		rejects{`null`, `undefined`, `{}`},
+	)
+
+	// the fields of a struct embedded more than once at the same depth are ambiguous
+	assertSimpleSchemaFor[embeddingSameTypeTwice](t,
+		z, `z.object({})`,
+		examples[embeddingSameTypeTwice]{
+			example[embeddingSameTypeTwice]{embeddingSameTypeTwice{}, []embeddingSameTypeTwice{{embeddedTwiceA{embeddableA{A: "foo"}}, embeddedTwiceB{}}}, `{}`},
+		},
+		rejects{`null`, `undefined`},
+	)
+}
+
+func TestEmbeddedStructsContributeThePropertiesOfTheirGivenSchemas(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(withoutComments{}), gozod.WithDiscriminator(reflective.TypeFor[embeddableDiscriminated](), "kind", "base"))
+	require.NoError(t, m.ResolveAll(reflective.TypeFor[embeddingDiscriminated]()))
+	assertTypeScript(t, gozod.SupportingDeclarations(m), z, `/**
+ * embeddableDiscriminated corresponds to Go type gozod_test.embeddableDiscriminated (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const embeddableDiscriminated = z.object({
+    kind: z.literal("base"),
+    ID: z.string(),
+});
+export type embeddableDiscriminated = z.infer<typeof embeddableDiscriminated>;
+
+/**
+ * embeddingDiscriminated corresponds to Go type gozod_test.embeddingDiscriminated (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const embeddingDiscriminated = z.object({
+    Name: z.string(),
+    ID: z.number().int(),
+    kind: z.literal("base").optional(),
+});
+export type embeddingDiscriminated = z.infer<typeof embeddingDiscriminated>;
+`)
+}
+
+func TestPropertiesOfGivenSchemasOfStructsEmbeddedAtTheSameDepthAreAmbiguous(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(withoutComments{}),
+		gozod.WithDiscriminator(reflective.TypeFor[embeddableDiscriminated](), "kind", "base"),
+		gozod.WithDiscriminator(reflective.TypeFor[embeddableAudited](), "kind", "audited"),
+	)
+	require.NoError(t, m.ResolveAll(reflective.TypeFor[embeddingTwoDiscriminated]()))
+	assertTypeScript(t, gozod.SupportingDeclarations(m), z, `/**
+ * embeddableAudited corresponds to Go type gozod_test.embeddableAudited (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const embeddableAudited = z.object({
+    kind: z.literal("audited"),
+    At: z.string(),
+});
+export type embeddableAudited = z.infer<typeof embeddableAudited>;
+
+/**
+ * embeddableDiscriminated corresponds to Go type gozod_test.embeddableDiscriminated (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const embeddableDiscriminated = z.object({
+    kind: z.literal("base"),
+    ID: z.string(),
+});
+export type embeddableDiscriminated = z.infer<typeof embeddableDiscriminated>;
+
+/**
+ * embeddingTwoDiscriminated corresponds to Go type gozod_test.embeddingTwoDiscriminated (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const embeddingTwoDiscriminated = z.object({
+    ID: z.string().optional(),
+    At: z.string(),
+});
+export type embeddingTwoDiscriminated = z.infer<typeof embeddingTwoDiscriminated>;
+`)
+}
+
+func TestEmbeddedStructsWhoseGivenSchemasArentObjectsAreReported(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(withoutComments{}), gozod.WithTransform(reflective.TypeFor[embeddableTransformed](), ts.Sourcef("o => o.N")))
+	assert.EqualError(t, m.ResolveAll(reflective.TypeFor[embeddingTransformed]()), "gozod_test.embeddingTransformed.embeddableTransformed: can't promote the fields of embedded gozod_test.embeddableTransformed, since the schema given for it isn't an object")
+}
@@ -0,0 +1,191 @@
+package gozod_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+	"github.com/softwaretechnik-berlin/goats/gotypes/gozod"
+)
+
+// orderState is the state of an order.
+type orderState string
+
+const (
+	// orderPending orders await payment.
+	orderPending orderState = "pending"
+	orderShipped orderState = "shipped" // orderShipped orders are on their way.
+	orderDone    orderState = "done"
+)
+
+type role int
+
+const (
+	roleGuest role = iota
+	roleUser
+	roleAdmin
+	// roleDefault is the role of new users.
+	roleDefault = roleUser
+)
+
+func (r role) String() string {
+	return [...]string{"Guest", "User", "Admin"}[r]
+}
+
+type level uint8
+
+const (
+	levelLow level = iota + 1
+	levelHigh
+	levelInvalid
+)
+
+func (l level) MarshalText() ([]byte, error) {
+	switch l {
+	case levelLow:
+		return []byte("LOW"), nil
+	case levelHigh:
+		return []byte("HIGH"), nil
+	default:
+		return nil, assert.AnError
+	}
+}
+
+func (l *level) UnmarshalText(text []byte) error {
+	*l = map[string]level{"LOW": levelLow, "HIGH": levelHigh}[strings.ToUpper(string(text))]
+	return nil
+}
+
+type unenumerated string
+
+type enumsStruct struct {
+	State orderState
+	Role  role
+	Level level
+	Wait  time.Duration
+}
+
+func TestEnums(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader), gozod.WithEnums(), gozod.When[time.Duration]().NotEnum())
+	assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[enumsStruct]()).Value, "", `enumsStruct`)
+	assertTypeScript(t, gozod.SupportingDeclarations(m), z, `/**
+ * Duration corresponds to Go type time.Duration (in package "time").
+ * The comment on the original Go type follows.
+ *
+ * A Duration represents the elapsed time between two instants
+ * as an int64 nanosecond count. The representation limits the
+ * largest representable duration to approximately 290 years.
+ */
+export const Duration = z.number().int().brand("Duration");
+export type Duration = z.infer<typeof Duration>;
+
+/**
+ * level corresponds to Go type gozod_test.level (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const level = z.enum([
+    "LOW",
+    "HIGH",
+]);
+export type level = z.infer<typeof level>;
+
+/**
+ * levelValues maps the names of the members of level to their values.
+ */
+export const levelValues = {
+    levelLow: "LOW",
+    levelHigh: "HIGH",
+} as const;
+
+/**
+ * orderState corresponds to Go type gozod_test.orderState (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ * The comment on the original Go type follows.
+ *
+ * orderState is the state of an order.
+ */
+export const orderState = z.enum([
+    "pending",
+    "shipped",
+    "done",
+]);
+export type orderState = z.infer<typeof orderState>;
+
+/**
+ * orderStateValues maps the names of the members of orderState to their values.
+ */
+export const orderStateValues = {
+    /**
+     * orderPending orders await payment.
+     */
+    orderPending: "pending",
+    /**
+     * orderShipped orders are on their way.
+     */
+    orderShipped: "shipped",
+    orderDone: "done",
+} as const;
+
+/**
+ * role corresponds to Go type gozod_test.role (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const role = z.union([
+    z.literal(0),
+    z.literal(1),
+    z.literal(2),
+]);
+export type role = z.infer<typeof role>;
+
+/**
+ * roleValues maps the names of the members of role to their values.
+ */
+export const roleValues = {
+    roleGuest: 0,
+    roleUser: 1,
+    roleAdmin: 2,
+    /**
+     * roleDefault is the role of new users.
+     */
+    roleDefault: 1,
+} as const;
+
+/**
+ * roleLabels maps the values of role to their labels.
+ */
+export const roleLabels = {
+    "0": "Guest",
+    "1": "User",
+    "2": "Admin",
+} as const;
+
+/**
+ * enumsStruct corresponds to Go type gozod_test.enumsStruct (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const enumsStruct = z.object({
+    State: orderState,
+    Role: role,
+    Level: level,
+    Wait: Duration,
+});
+export type enumsStruct = z.infer<typeof enumsStruct>;
+`)
+
+	assertExamplesAndRejects(t, examples[enumsStruct]{
+		simpleExample(enumsStruct{orderShipped, roleAdmin, levelHigh, time.Second}, `{"State":"shipped","Role":2,"Level":"HIGH","Wait":1000000000}`),
+	}, rejects{})
+}
+
+func TestEnumsAreOptIn(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader), gozod.When[role]().AsEnum())
+	assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[orderState]()).Value, "", `orderState`)
+	assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[role]()).Value, "", `role`)
+	assert.Contains(t, gozod.SupportingDeclarations(m).String(), `export const orderState = z.string().brand("orderState");`)
+	assert.Contains(t, gozod.SupportingDeclarations(m).String(), `export const role = z.union([`)
+}
+
+func TestEnumsMustHaveConstants(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader), gozod.When[unenumerated]().AsEnum(), gozod.When[pagedUser]().AsEnum())
+	assert.EqualError(t, m.ResolveAll(reflective.TypeFor[unenumerated](), reflective.TypeFor[pagedUser]()), `gozod_test.unenumerated: gozod_test.unenumerated is configured as an enum, but has no constants that can be represented in JSON
+gozod_test.pagedUser: gozod_test.pagedUser is configured as an enum, but isn't a named string or integer type with the default JSON representation`)
+}
@@ -0,0 +1,68 @@
+package gozod_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+	"github.com/softwaretechnik-berlin/goats/gotypes/gozod"
+	"github.com/softwaretechnik-berlin/goats/gotypes/zod"
+)
+
+type (
+	faultyOrder struct {
+		Lines   []faultyLine
+		Notes   map[string]chan string
+		Created string `gotypes:",nonnull"`
+	}
+	faultyLine struct {
+		Product faultyProduct
+	}
+	faultyProduct struct {
+		Price complex128
+	}
+)
+
+func TestProblemsAreReportedWithTheirGoTypePaths(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader))
+	assert.EqualError(t, m.ResolveAll(reflective.TypeFor[faultyOrder]()), `gozod_test.faultyOrder.Lines[].Product.Price: unsupported kind complex128
+gozod_test.faultyOrder.Notes[]: unsupported kind chan
+gozod_test.faultyOrder.Created: the nonnull option of a field can only be applied to pointers and unnamed slice and map types, not string`)
+
+	var resolutionError *gozod.ResolutionError
+	require.ErrorAs(t, m.Err(), &resolutionError)
+	assert.Equal(t, "gozod_test.faultyOrder.Lines[].Product.Price", resolutionError.Path)
+}
+
+func TestMisusedOptionsAreReported(t *testing.T) {
+	m := gozod.NewMapper(
+		gozod.WithCommentsLoader(sharedCommentsLoader),
+		gozod.When[faultyProduct]().Transformf("p => %s.parse(p)", 42),
+		gozod.WhenGeneric[faultyLine]().Named("Line"),
+	)
+	assert.EqualError(t, m.ResolveAll(reflective.TypeFor[faultyLine]()), `argument 0 of the transform of gozod_test.faultyProduct is a int rather than a ts.Source or goinsp.Type
+WhenGeneric needs an instantiation of a generic type, not gozod_test.faultyLine
+gozod_test.faultyLine.Product.Price: unsupported kind complex128`)
+}
+
+func TestFilesAreOnlyGeneratedWithoutProblems(t *testing.T) {
+	dir := t.TempDir()
+
+	m := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader))
+	m.Resolve(reflective.TypeFor[faultyProduct]())
+	assert.EqualError(t, gozod.GenerateFile(m, filepath.Join(dir, "faulty.ts")), "gozod_test.faultyProduct.Price: unsupported kind complex128")
+	assert.NoFileExists(t, filepath.Join(dir, "faulty.ts"))
+
+	m = gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader), gozod.When[complex128]().Schema(zod.Number()))
+	m.Resolve(reflective.TypeFor[faultyProduct]())
+	require.NoError(t, gozod.GenerateFile(m, filepath.Join(dir, "product.ts")))
+	generated, err := os.ReadFile(filepath.Join(dir, "product.ts"))
+	require.NoError(t, err)
+	assert.Contains(t, string(generated), "export const faultyProduct = z.object({ Price: z.number() });")
+
+	assert.Error(t, gozod.GenerateFile(m, filepath.Join(dir, "missing", "product.ts")))
+}
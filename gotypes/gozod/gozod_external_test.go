@@ -0,0 +1,97 @@
+package gozod_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+	"github.com/softwaretechnik-berlin/goats/gotypes/gozod"
+)
+
+const acmeMoney = `import { Money } from "@acme/money";`
+
+type externalAmount int64
+
+type externalInvoice struct {
+	Total    externalAmount
+	Discount *externalAmount
+	Lines    []externalAmount `json:",omitempty"`
+}
+
+func TestExternalSchemasAreImportedRatherThanDeclared(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(withoutComments{}), gozod.When[externalAmount]().External("@acme/money", "Money"))
+	require.NoError(t, m.ResolveAll(reflective.TypeFor[externalInvoice]()))
+	assertTypeScript(t, gozod.SupportingDeclarations(m), acmeMoney+"\n"+z, `/**
+ * externalInvoice corresponds to Go type gozod_test.externalInvoice (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const externalInvoice = z.object({
+    Total: Money,
+    Discount: Money.nullable(),
+    Lines: z.array(Money).nullable().transform(a => a ?? []).optional(),
+});
+export type externalInvoice = z.infer<typeof externalInvoice>;
+`)
+}
+
+func TestExternalSchemasHaveInferredTypes(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(withoutComments{}), gozod.WithExplicitTypes(), gozod.WithExternal(reflective.TypeFor[externalAmount](), "@acme/money", "Money"))
+	require.NoError(t, m.ResolveAll(reflective.TypeFor[externalInvoice]()))
+	assertTypeScript(t, gozod.SupportingDeclarations(m), acmeMoney+"\n"+z, `/**
+ * externalInvoice corresponds to Go type gozod_test.externalInvoice (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export interface externalInvoice {
+    Total: z.infer<typeof Money>;
+    Discount: z.infer<typeof Money> | null;
+    Lines?: z.infer<typeof Money>[] | undefined;
+}
+export type externalInvoiceInput = {
+    Total: z.input<typeof Money>;
+    Discount: z.input<typeof Money> | null;
+    Lines?: z.input<typeof Money>[] | null | undefined;
+};
+export const externalInvoice = z.object({
+    Total: Money,
+    Discount: Money.nullable(),
+    Lines: z.array(Money).nullable().transform(a => a ?? []).optional(),
+}) satisfies z.ZodType<externalInvoice, z.ZodTypeDef, externalInvoiceInput>;
+`)
+}
+
+func TestExternalSchemasAreOnlyImportedForTypesInTheTypesBackend(t *testing.T) {
+	m := gozod.NewTypesMapper(gozod.WithCommentsLoader(withoutComments{}), gozod.When[externalAmount]().External("@acme/money", "Money"))
+	require.NoError(t, m.ResolveAll(reflective.TypeFor[externalInvoice]()))
+	source, err := gozod.GenerateSource(m)
+	require.NoError(t, err)
+	assert.Equal(t, `import type { Money } from "@acme/money";
+import type { z } from "zod";
+
+/**
+ * externalInvoice corresponds to Go type gozod_test.externalInvoice (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export interface externalInvoice {
+    Total: z.input<typeof Money>;
+    Discount: z.input<typeof Money> | null;
+    Lines?: z.input<typeof Money>[] | null | undefined;
+}
+`, source)
+}
+
+func TestExternalSchemasAreReportedByBackendsWithoutEquivalents(t *testing.T) {
+	external := gozod.When[externalAmount]().External("@acme/money", "Money")
+	for backend, resolve := range map[string]func() error{
+		"JSON Schema": func() error {
+			return gozod.NewJSONSchemaMapper(external).ResolveAll(reflective.TypeFor[externalInvoice]())
+		},
+		"Valibot": func() error {
+			return gozod.NewValibotMapper(external).ResolveAll(reflective.TypeFor[externalInvoice]())
+		},
+		"Effect": func() error { return gozod.NewEffectMapper(external).ResolveAll(reflective.TypeFor[externalInvoice]()) },
+		"Pydantic": func() error {
+			return gozod.NewPydanticMapper(external).ResolveAll(reflective.TypeFor[externalInvoice]())
+		},
+	} {
+		assert.ErrorContains(t, resolve(), "gozod_test.externalInvoice.Total: the hand-written schema has no "+backend+" equivalent", backend)
+	}
+}
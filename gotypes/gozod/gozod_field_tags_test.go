@@ -0,0 +1,90 @@
+package gozod_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+	"github.com/softwaretechnik-berlin/goats/gotypes/gozod"
+)
+
+type (
+	// TaggedAudit is exported, so that encoding/json can unmarshal it through the embedded pointer.
+	TaggedAudit struct {
+		Editor string `json:"editor,omitempty" gotypes:",required"`
+	}
+	taggedAccount struct {
+		*TaggedAudit
+		UserID   string   `json:"user_id" gotypes:",name=userId,format=uuid"`
+		Email    string   `gotypes:",format=email,max=254"`
+		Handle   string   `gotypes:",min=3,max=16,pattern=^[a-z0-9_]{3,16}$"`
+		Age      *int32   `gotypes:",min=0,max=150"`
+		Website  *string  `gotypes:",nonnull,format=url"`
+		Tags     []string `gotypes:",nonnull,readonly,max=8"`
+		Scores   []int8   `gotypes:",nil=empty,len=3"`
+		Locale   string   `json:",omitempty" gotypes:",default=en-GB"`
+		Limit    int      `json:",omitempty" gotypes:",default=20"`
+		Nickname string   `json:",omitempty" gotypes:",required"`
+		Team     string   `gotypes:",brand=TeamID"`
+		Payload  any      `gotypes:",schema=z.record(z.string(), z.number())"`
+	}
+)
+
+func TestFieldTags(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader))
+	assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[taggedAccount]()).Value, "", `taggedAccount`)
+	assertTypeScript(t, gozod.SupportingDeclarations(m), z, `/**
+ * taggedAccount corresponds to Go type gozod_test.taggedAccount (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const taggedAccount = z.object({
+    editor: z.string(),
+    user_id: z.string().uuid(),
+    Email: z.string().max(254).email(),
+    Handle: z.string().min(3).max(16).regex(/^[a-z0-9_]{3,16}$/),
+    Age: z.number().int().min(-2147483648).max(2147483647).min(0).max(150).nullable(),
+    Website: z.string().url(),
+    Tags: z.array(z.string()).max(8).readonly(),
+    Scores: z.array(z.number().int().min(-128).max(127)).length(3).nullable().transform(a => a ?? []),
+    Locale: z.string().default("en-GB"),
+    Limit: z.number().int().default(20),
+    Nickname: z.string(),
+    Team: z.string().brand("TeamID"),
+    Payload: z.record(z.string(), z.number()),
+}).transform(({ user_id: userId, ...rest }) => ({ ...rest, userId }));
+export type taggedAccount = z.infer<typeof taggedAccount>;
+`)
+
+	assertExamplesAndRejects(t, examples[taggedAccount]{
+		simpleExample(taggedAccount{
+			TaggedAudit: &TaggedAudit{Editor: "ada"},
+			UserID:      "8c1c1d6a-3b4c-4a4e-9d3a-0b8e4a0f3f21",
+			Email:       "ada@example.com",
+			Handle:      "ada_l",
+			Website:     ptr("https://example.com"),
+			Tags:        []string{"admin"},
+			Scores:      []int8{1, 2, 3},
+			Nickname:    "Ada",
+			Team:        "core",
+			Payload:     map[string]any{"a": 1.0},
+		}, `{"editor":"ada","user_id":"8c1c1d6a-3b4c-4a4e-9d3a-0b8e4a0f3f21","Email":"ada@example.com","Handle":"ada_l","Age":null,"Website":"https://example.com","Tags":["admin"],"Scores":[1,2,3],"Nickname":"Ada","Team":"core","Payload":{"a":1}}`),
+	}, rejects{})
+}
+
+func TestFieldTagsMustBeValid(t *testing.T) {
+	assert.EqualError(t, gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader)).ResolveAll(reflective.TypeFor[struct {
+		Value string `gotypes:",frobnicate"`
+	}]()), `struct { Value string "gotypes:\",frobnicate\"" }.Value: unknown option "frobnicate" in gotypes tag ",frobnicate"`)
+	assert.EqualError(t, gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader)).ResolveAll(reflective.TypeFor[struct {
+		Value string `gotypes:",format=phone"`
+	}]()), `struct { Value string "gotypes:\",format=phone\"" }.Value: unknown format "phone" in gotypes tag ",format=phone"; the known formats are email, url, uuid and datetime`)
+	assert.EqualError(t, gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader)).ResolveAll(reflective.TypeFor[struct {
+		Value string `gotypes:",name=user-id"`
+	}]()), `struct { Value string "gotypes:\",name=user-id\"" }.Value: name "user-id" in gotypes tag ",name=user-id" isn't a valid identifier`)
+	assert.EqualError(t, gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader)).ResolveAll(reflective.TypeFor[struct {
+		Value float64 `gotypes:",len=3"`
+	}]()), `struct { Value float64 "gotypes:\",len=3\"" }.Value: only min= and max= can be applied to fields of number type float64`)
+	assert.EqualError(t, gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader)).ResolveAll(reflective.TypeFor[struct {
+		Value string `gotypes:",nonnull"`
+	}]()), `struct { Value string "gotypes:\",nonnull\"" }.Value: the nonnull option of a field can only be applied to pointers and unnamed slice and map types, not string`)
+}
@@ -0,0 +1,98 @@
+package gozod_test
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+	"github.com/softwaretechnik-berlin/goats/gotypes/gozod"
+)
+
+type filesEvent struct {
+	At      time.Time
+	Timeout time.Duration
+	Deleted sql.NullTime
+}
+
+func TestGenerateFiles(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(withoutComments{}), gozod.WithStandardLibrary())
+	require.NoError(t, m.ResolveAll(reflective.TypeFor[filesEvent]()))
+	dir := t.TempDir()
+	require.NoError(t, gozod.GenerateFiles(m, dir, gozod.WithIndexFile()))
+
+	assertModule(t, dir, "database/sql", `import { Time } from "../time";
+import { z } from "zod";
+
+/**
+ * NullTime corresponds to Go type sql.NullTime (in package "database/sql").
+ */
+export const NullTime = z.object({
+    Time: Time,
+    Valid: z.boolean(),
+}).transform(n => n.Valid ? n.Time : null);
+export type NullTime = z.infer<typeof NullTime>;
+`)
+	assertModule(t, dir, "gozod_test", `import { NullTime } from "./database/sql";
+import { Duration, Time } from "./time";
+import { z } from "zod";
+
+/**
+ * filesEvent corresponds to Go type gozod_test.filesEvent (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const filesEvent = z.object({
+    At: Time,
+    Timeout: Duration,
+    Deleted: NullTime,
+});
+export type filesEvent = z.infer<typeof filesEvent>;
+`)
+	assertModule(t, dir, "index", `export * from "./time";
+export * from "./database/sql";
+export * from "./gozod_test";
+`)
+}
+
+func TestGenerateFilesWithoutIndexFile(t *testing.T) {
+	m := gozod.NewTypesMapper(gozod.WithCommentsLoader(withoutComments{}), gozod.WithStandardLibrary())
+	require.NoError(t, m.ResolveAll(reflective.TypeFor[filesEvent]()))
+	dir := t.TempDir()
+	require.NoError(t, gozod.GenerateFiles(m, dir))
+
+	assertModule(t, dir, "gozod_test", `import { NullTime } from "./database/sql";
+import { Duration, Time } from "./time";
+
+/**
+ * filesEvent corresponds to Go type gozod_test.filesEvent (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export interface filesEvent {
+    At: Time;
+    Timeout: Duration;
+    Deleted: NullTime;
+}
+`)
+	assert.NoFileExists(t, filepath.Join(dir, "index.ts"))
+}
+
+func TestGenerateFilesWritesNothingIfAModuleIsCalledIndex(t *testing.T) {
+	entry, err := staticLoader.Lookup("github.com/softwaretechnik-berlin/goats/gotypes/gozod/testdata/index", "Entry")
+	require.NoError(t, err)
+	m := gozod.NewMapper(gozod.WithCommentsLoader(withoutComments{}))
+	require.NoError(t, m.ResolveAll(entry))
+	dir := t.TempDir()
+	assert.EqualError(t, gozod.GenerateFiles(m, dir, gozod.WithIndexFile()), "can't write index file, since the module of package github.com/softwaretechnik-berlin/goats/gotypes/gozod/testdata/index is called index")
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func assertModule(t *testing.T, dir, module, expected string) {
+	source, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(module)+".ts"))
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(source), "module %s", module)
+}
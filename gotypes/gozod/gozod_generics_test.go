@@ -0,0 +1,168 @@
+package gozod_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+	"github.com/softwaretechnik-berlin/goats/gotypes/gozod"
+)
+
+type (
+	// page is a page of items.
+	page[T any] struct {
+		// Items are the items on the page.
+		Items []T `json:"items"`
+		Next  *string
+	}
+	envelope[T any] struct {
+		Data page[T]
+		Meta map[string]T
+	}
+	result[T, E any] struct {
+		Value *T `json:",omitempty"`
+		Error E  `json:",omitempty"`
+	}
+	phantom[T any] struct {
+		ID string
+	}
+	genericTree[T any] struct {
+		Value    T
+		Children []genericTree[T]
+	}
+	pagedUser struct {
+		Name string
+	}
+	userListing struct {
+		Users   page[pagedUser]
+		Lookups result[pagedUser, string]
+	}
+)
+
+func genericsOptions(options ...gozod.Option) []gozod.Option {
+	return append([]gozod.Option{gozod.WithCommentsLoader(sharedCommentsLoader), gozod.WithStaticLoader(staticLoader)}, options...)
+}
+
+func TestGenericFactories(t *testing.T) {
+	m := gozod.NewMapper(genericsOptions(gozod.WithGenericFactories())...)
+	assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[userListing]()).Value, "", `userListing`)
+	assertTypeScript(t, gozod.SupportingDeclarations(m), z, `/**
+ * page returns the schema corresponding to Go type gozod_test.page[T] (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test"), given the schemas for its type arguments.
+ * The comment on the original Go type follows.
+ *
+ * page is a page of items.
+ */
+export const page = <T extends z.ZodTypeAny>(t: T) => z.object({
+    /**
+     * Items are the items on the page.
+     */
+    items: z.array(t).nullable().transform(a => a ?? []),
+    Next: z.string().nullable(),
+});
+export type page<T extends z.ZodTypeAny> = z.infer<ReturnType<typeof page<T>>>;
+
+/**
+ * pagedUser corresponds to Go type gozod_test.pagedUser (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const pagedUser = z.object({ Name: z.string() });
+export type pagedUser = z.infer<typeof pagedUser>;
+
+/**
+ * result returns the schema corresponding to Go type gozod_test.result[T,E] (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test"), given the schemas for its type arguments.
+ */
+export const result = <T extends z.ZodTypeAny, E extends z.ZodTypeAny>(t: T, e: E) => z.object({
+    Value: t.nullable().optional(),
+    Error: e.optional(),
+});
+export type result<T extends z.ZodTypeAny, E extends z.ZodTypeAny> = z.infer<ReturnType<typeof result<T, E>>>;
+
+/**
+ * userListing corresponds to Go type gozod_test.userListing (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const userListing = z.object({
+    Users: page(pagedUser),
+    Lookups: result(pagedUser, z.string()),
+});
+export type userListing = z.infer<typeof userListing>;
+`)
+}
+
+func TestGenericFactoriesForStaticallyLoadedTypes(t *testing.T) {
+	statically, err := staticLoader.Lookup("github.com/softwaretechnik-berlin/goats/gotypes/gozod_test", "userListing")
+	require.NoError(t, err)
+
+	staticMapper := gozod.NewMapper(genericsOptions(gozod.WithGenericFactories())...)
+	reflectiveMapper := gozod.NewMapper(genericsOptions(gozod.WithGenericFactories())...)
+	staticMapper.Resolve(statically)
+	reflectiveMapper.Resolve(reflective.TypeFor[userListing]())
+	assert.Equal(t, gozod.SupportingDeclarations(reflectiveMapper).String(), gozod.SupportingDeclarations(staticMapper).String())
+
+	// The same types obtained reflectively share the declarations.
+	staticMapper.Resolve(reflective.TypeFor[userListing]())
+	assert.Equal(t, gozod.SupportingDeclarations(reflectiveMapper).String(), gozod.SupportingDeclarations(staticMapper).String())
+}
+
+func TestGenericFactoriesSelectedWithWhenGeneric(t *testing.T) {
+	// Factories that instantiate other generic types with their type parameters make those factories too.
+	m := gozod.NewMapper(genericsOptions(gozod.WhenGeneric[envelope[any]]().AsFactory())...)
+	assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[envelope[int]]()).Value, z, `envelope(z.number().int())`)
+	assertTypeScript(t, gozod.SupportingDeclarations(m), z, `/**
+ * page returns the schema corresponding to Go type gozod_test.page[T] (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test"), given the schemas for its type arguments.
+ * The comment on the original Go type follows.
+ *
+ * page is a page of items.
+ */
+export const page = <T extends z.ZodTypeAny>(t: T) => z.object({
+    /**
+     * Items are the items on the page.
+     */
+    items: z.array(t).nullable().transform(a => a ?? []),
+    Next: z.string().nullable(),
+});
+export type page<T extends z.ZodTypeAny> = z.infer<ReturnType<typeof page<T>>>;
+
+/**
+ * envelope returns the schema corresponding to Go type gozod_test.envelope[T] (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test"), given the schemas for its type arguments.
+ */
+export const envelope = <T extends z.ZodTypeAny>(t: T) => z.object({
+    Data: page(t),
+    Meta: z.record(z.string(), t).nullable().transform(r => r ?? {}),
+});
+export type envelope<T extends z.ZodTypeAny> = z.infer<ReturnType<typeof envelope<T>>>;
+`)
+}
+
+func TestNamedInstantiationsOfGenericFactories(t *testing.T) {
+	m := gozod.NewMapper(genericsOptions(gozod.WithGenericFactories(), gozod.When[page[pagedUser]]().Named("UserPage"))...)
+	assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[page[pagedUser]]()).Value, "", `UserPage`)
+	assert.Contains(t, gozod.SupportingDeclarations(m).String(), `/**
+ * UserPage corresponds to Go type gozod_test.page[github.com/softwaretechnik-berlin/goats/gotypes/gozod_test.pagedUser] (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ * The comment on the original Go type follows.
+ *
+ * page is a page of items.
+ */
+export const UserPage = page(pagedUser);
+export type UserPage = z.infer<typeof UserPage>;
+`)
+}
+
+func TestRecursiveGenericFactories(t *testing.T) {
+	m := gozod.NewMapper(genericsOptions(gozod.WithGenericFactories())...)
+	assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[genericTree[string]]()).Value, z, `genericTree(z.string())`)
+	assertTypeScript(t, gozod.SupportingDeclarations(m), z, `/**
+ * genericTree returns the schema corresponding to Go type gozod_test.genericTree[T] (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test"), given the schemas for its type arguments.
+ */
+export const genericTree = <T extends z.ZodTypeAny>(t: T): z.ZodTypeAny => z.object({
+    Value: t,
+    Children: z.array(z.lazy(() => genericTree(t))).nullable().transform(a => a ?? []),
+});
+export type genericTree<T extends z.ZodTypeAny> = z.infer<ReturnType<typeof genericTree<T>>>;
+`)
+}
+
+func TestTypeArgumentsOfUnusedTypeParametersAreAny(t *testing.T) {
+	m := gozod.NewMapper(genericsOptions(gozod.WithGenericFactories())...)
+	assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[phantom[pagedUser]]()).Value, z, `phantom(z.any())`)
+}
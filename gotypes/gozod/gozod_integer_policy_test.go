@@ -0,0 +1,121 @@
+package gozod_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+	"github.com/softwaretechnik-berlin/goats/gotypes/gozod"
+)
+
+type (
+	snowflake           int64
+	integerPolicyStruct struct {
+		ID       snowflake
+		Count    uint64
+		Quoted   int64  `json:",string"`
+		Parent   *int64 `json:",string" gotypes:",int=string"`
+		Revision int64  `gotypes:",int=safe"`
+		Small    int32  `json:",string"`
+	}
+)
+
+func TestIntegerPolicies(t *testing.T) {
+	for _, c := range []struct {
+		option           gozod.Option
+		signed, unsigned string
+	}{
+		{gozod.WithIntegerPolicy(gozod.IntegersAsNumbers), `z.number().int()`, `z.number().nonnegative().int()`},
+		{gozod.WithIntegerPolicy(gozod.SafeIntegers), `z.number().int().safe()`, `z.number().nonnegative().int().safe()`},
+		{gozod.WithIntegerPolicy(gozod.IntegersAsBigInts), `z.union([
+    z.number().int().safe(),
+    z.string().regex(/^-?\d+$/),
+    z.bigint(),
+]).transform(n => BigInt(n))`, `z.union([
+    z.number().nonnegative().int().safe(),
+    z.string().regex(/^\d+$/),
+    z.bigint(),
+]).transform(n => BigInt(n))`},
+		{gozod.WithIntegerPolicy(gozod.IntegersAsDecimalStrings), `z.union([
+    z.number().int().safe(),
+    z.string().regex(/^-?\d+$/),
+    z.bigint(),
+]).transform(n => String(n))`, `z.union([
+    z.number().nonnegative().int().safe(),
+    z.string().regex(/^\d+$/),
+    z.bigint(),
+]).transform(n => String(n))`},
+	} {
+		m := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader), c.option)
+		assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[int64]()).Value, z, c.signed)
+		assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[uint]()).Value, z, c.unsigned)
+		// Integers of sized kinds are always safe.
+		assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[int32]()).Value, z, `z.number().int().min(-2147483648).max(2147483647)`)
+	}
+}
+
+func TestIntegerPoliciesForTypesAndFields(t *testing.T) {
+	m := gozod.NewMapper(
+		gozod.WithCommentsLoader(sharedCommentsLoader),
+		gozod.When[snowflake]().IntegerPolicy(gozod.IntegersAsDecimalStrings),
+		gozod.WithIntegerPolicy(gozod.IntegersAsBigInts),
+	)
+	assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[integerPolicyStruct]()).Value, "", `integerPolicyStruct`)
+	assertTypeScript(t, gozod.SupportingDeclarations(m), z, `/**
+ * snowflake corresponds to Go type gozod_test.snowflake (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const snowflake = z.union([
+    z.number().int().safe(),
+    z.string().regex(/^-?\d+$/),
+    z.bigint(),
+]).transform(n => String(n)).brand("snowflake");
+export type snowflake = z.infer<typeof snowflake>;
+
+/**
+ * integerPolicyStruct corresponds to Go type gozod_test.integerPolicyStruct (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const integerPolicyStruct = z.object({
+    ID: snowflake,
+    Count: z.union([
+        z.number().nonnegative().int().safe(),
+        z.string().regex(/^\d+$/),
+        z.bigint(),
+    ]).transform(n => BigInt(n)),
+    Quoted: z.union([
+        z.number().int().safe(),
+        z.string().regex(/^-?\d+$/),
+        z.bigint(),
+    ]).transform(n => BigInt(n)),
+    Parent: z.union([
+        z.number().int().safe(),
+        z.string().regex(/^-?\d+$/),
+        z.bigint(),
+    ]).transform(n => String(n)).nullable(),
+    Revision: z.number().int().safe(),
+    Small: z.string().transform(s => JSON.parse(s)).pipe(z.number().int().min(-2147483648).max(2147483647)),
+});
+export type integerPolicyStruct = z.infer<typeof integerPolicyStruct>;
+`)
+
+	assertExamplesAndRejects(t, examples[integerPolicyStruct]{
+		simpleExample(integerPolicyStruct{ID: 1 << 62, Count: 1 << 63, Quoted: -1 << 62, Parent: ptr[int64](1 << 61), Revision: 3, Small: 4},
+			`{"ID":4611686018427387904,"Count":9223372036854775808,"Quoted":"-4611686018427387904","Parent":"2305843009213693952","Revision":3,"Small":"4"}`),
+	}, rejects{})
+}
+
+func TestFieldIntegerPoliciesMustBeKnown(t *testing.T) {
+	assert.EqualError(t, gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader)).ResolveAll(reflective.TypeFor[struct {
+		Value int64 `gotypes:",int=float"`
+	}]()), `struct { Value int64 "gotypes:\",int=float\"" }.Value: unknown integer policy "float" in gotypes tag ",int=float"; the known policies are number, safe, bigint and string`)
+	assert.EqualError(t, gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader)).ResolveAll(reflective.TypeFor[struct {
+		Value int32 `gotypes:",int=bigint"`
+	}]()), `struct { Value int32 "gotypes:\",int=bigint\"" }.Value: the integer policy bigint of a field can only be applied to unnamed 64-bit integer types and pointers to them, not int32`)
+}
+
+func TestIntegerPoliciesOfOptionsMustBeKnown(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader), gozod.WithIntegerPolicy(gozod.IntegerPolicy(9)), gozod.When[uint]().IntegerPolicy(gozod.IntegerPolicy(-1)))
+	assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[int]()).Value, z, `z.number().int()`)
+	assert.EqualError(t, m.Err(), `unknown integer policy IntegerPolicy(9); the known policies are IntegersAsNumbers, SafeIntegers, IntegersAsBigInts and IntegersAsDecimalStrings
+unknown integer policy IntegerPolicy(-1); the known policies are IntegersAsNumbers, SafeIntegers, IntegersAsBigInts and IntegersAsDecimalStrings`)
+}
@@ -0,0 +1,108 @@
+package gozod_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+	"github.com/softwaretechnik-berlin/goats/gotypes/gozod"
+)
+
+type (
+	// sampledMoney marshals itself to an object whose amount is a decimal string and whose note is omitted if empty.
+	sampledMoney struct {
+		cents    int64
+		currency string
+		note     string
+	}
+	// sampledVersion marshals itself to an array of integers, or null if it's unknown.
+	sampledVersion struct {
+		parts []int
+	}
+	unsampledMarshaler struct {
+		Value string
+	}
+	sampledOrder struct {
+		Total   sampledMoney
+		Version sampledVersion
+	}
+)
+
+func (m sampledMoney) MarshalJSON() ([]byte, error) {
+	object := map[string]any{"amount": fmt.Sprintf("%d.%02d", m.cents/100, m.cents%100), "currency": m.currency}
+	if m.note != "" {
+		object["note"] = m.note
+	}
+	return json.Marshal(object)
+}
+
+func (v sampledVersion) MarshalJSON() ([]byte, error) {
+	if v.parts == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(v.parts)
+}
+
+func (u unsampledMarshaler) MarshalJSON() ([]byte, error) {
+	return json.Marshal(u.Value)
+}
+
+func TestJSONMarshalersAreInferredFromSamples(t *testing.T) {
+	m := gozod.NewMapper(
+		gozod.WithCommentsLoader(sharedCommentsLoader),
+		gozod.WithJSONSamples(reflective.TypeFor[sampledMoney](),
+			sampledMoney{cents: 1230, currency: "EUR"},
+			sampledMoney{cents: 5, currency: "USD", note: "tip"},
+		),
+		gozod.When[sampledVersion]().JSONSamples(sampledVersion{parts: []int{1, 2}}, sampledVersion{}),
+	)
+	assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[sampledOrder]()).Value, "", `sampledOrder`)
+	assert.NoError(t, m.Err())
+	assertTypeScript(t, gozod.SupportingDeclarations(m), z, `/**
+ * sampledMoney corresponds to Go type gozod_test.sampledMoney (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ * The comment on the original Go type follows.
+ *
+ * sampledMoney marshals itself to an object whose amount is a decimal string and whose note is omitted if empty.
+ */
+export const sampledMoney = z.object({
+    amount: z.string(),
+    currency: z.string(),
+    note: z.string().optional(),
+});
+export type sampledMoney = z.infer<typeof sampledMoney>;
+
+/**
+ * sampledVersion corresponds to Go type gozod_test.sampledVersion (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ * The comment on the original Go type follows.
+ *
+ * sampledVersion marshals itself to an array of integers, or null if it's unknown.
+ */
+export const sampledVersion = z.array(z.number().int()).nullable().brand("sampledVersion");
+export type sampledVersion = z.infer<typeof sampledVersion>;
+
+/**
+ * sampledOrder corresponds to Go type gozod_test.sampledOrder (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const sampledOrder = z.object({
+    Total: sampledMoney,
+    Version: sampledVersion,
+});
+export type sampledOrder = z.infer<typeof sampledOrder>;
+`)
+}
+
+func TestJSONMarshalersWithoutSchemasAreReported(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader))
+	m.Resolve(reflective.TypeFor[unsampledMarshaler]())
+	assert.EqualError(t, m.Err(), "gozod_test.unsampledMarshaler: gozod_test.unsampledMarshaler implements json.Marshaler, so its JSON can't be derived from its Go type; give its schema, e.g. with WithSchema, or samples of its values with WithJSONSamples")
+}
+
+func TestJSONSamplesMustHaveTheirType(t *testing.T) {
+	assert.EqualError(t, gozod.NewMapper(
+		gozod.WithCommentsLoader(sharedCommentsLoader),
+		gozod.When[sampledMoney]().JSONSamples("EUR"),
+	).ResolveAll(reflective.TypeFor[sampledMoney]()), `gozod_test.sampledMoney: the JSON sample "EUR" of gozod_test.sampledMoney has type string`)
+}
@@ -0,0 +1,241 @@
+package gozod_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+	"github.com/softwaretechnik-berlin/goats/gotypes/gozod"
+)
+
+type (
+	// schemaOrder is placed by a customer.
+	schemaOrder struct {
+		// ID identifies the order.
+		ID       schemaOrderID
+		Quantity int32 `json:",string"`
+		Lines    []schemaLine
+		Note     string `json:",omitempty"`
+		Currency schemaCurrency
+		Payment  schemaPayment
+		Parent   *schemaOrder `json:"parent,omitempty"`
+	}
+	schemaOrderID uint
+	schemaLine    struct {
+		Product string `gotypes:",min=1"`
+		Count   uint8
+	}
+	schemaPayment interface{}
+	schemaCard    struct{ Number string }
+	schemaInvoice struct{ Days uint16 }
+	// schemaCurrency is marshalled as its ISO 4217 code.
+	schemaCurrency struct{ code [3]byte }
+)
+
+func (c schemaCurrency) MarshalText() ([]byte, error) {
+	return c.code[:], nil
+}
+
+func TestJSONSchemasFollowTheSameRulesAsZod(t *testing.T) {
+	m := gozod.NewJSONSchemaMapper(
+		gozod.WithCommentsLoader(sharedCommentsLoader),
+		gozod.When[schemaOrderID]().Template("order-{}"),
+		gozod.WithDiscriminatedUnion(reflective.TypeFor[schemaPayment](), "kind", reflective.TypeFor[schemaCard](), reflective.TypeFor[schemaInvoice]()),
+		gozod.WithDiscriminator(reflective.TypeFor[schemaCard](), "kind", "card"),
+		gozod.WithDiscriminator(reflective.TypeFor[schemaInvoice](), "kind", "invoice"),
+	)
+	require.NoError(t, m.ResolveAll(reflective.TypeFor[schemaOrder]()))
+	document, err := gozod.JSONSchema(m)
+	require.NoError(t, err)
+	json, err := document.Marshal()
+	require.NoError(t, err)
+	assert.Equal(t, `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "$defs": {
+    "schemaCard": {
+      "description": "schemaCard corresponds to Go type gozod_test.schemaCard (in package \"github.com/softwaretechnik-berlin/goats/gotypes/gozod_test\").",
+      "type": "object",
+      "properties": {
+        "kind": {
+          "const": "card"
+        },
+        "Number": {
+          "type": "string"
+        }
+      },
+      "required": [
+        "kind",
+        "Number"
+      ]
+    },
+    "schemaCurrency": {
+      "description": "schemaCurrency corresponds to Go type gozod_test.schemaCurrency (in package \"github.com/softwaretechnik-berlin/goats/gotypes/gozod_test\").\nThe comment on the original Go type follows.\n\nschemaCurrency is marshalled as its ISO 4217 code.",
+      "type": "string"
+    },
+    "schemaInvoice": {
+      "description": "schemaInvoice corresponds to Go type gozod_test.schemaInvoice (in package \"github.com/softwaretechnik-berlin/goats/gotypes/gozod_test\").",
+      "type": "object",
+      "properties": {
+        "kind": {
+          "const": "invoice"
+        },
+        "Days": {
+          "type": "integer",
+          "minimum": 0,
+          "maximum": 65535
+        }
+      },
+      "required": [
+        "kind",
+        "Days"
+      ]
+    },
+    "schemaLine": {
+      "description": "schemaLine corresponds to Go type gozod_test.schemaLine (in package \"github.com/softwaretechnik-berlin/goats/gotypes/gozod_test\").",
+      "type": "object",
+      "properties": {
+        "Product": {
+          "type": "string",
+          "minLength": 1
+        },
+        "Count": {
+          "type": "integer",
+          "minimum": 0,
+          "maximum": 255
+        }
+      },
+      "required": [
+        "Product",
+        "Count"
+      ]
+    },
+    "schemaOrder": {
+      "description": "schemaOrder corresponds to Go type gozod_test.schemaOrder (in package \"github.com/softwaretechnik-berlin/goats/gotypes/gozod_test\").\nThe comment on the original Go type follows.\n\nschemaOrder is placed by a customer.",
+      "type": "object",
+      "properties": {
+        "ID": {
+          "$ref": "#/$defs/schemaOrderID",
+          "description": "ID identifies the order."
+        },
+        "Quantity": {
+          "type": "string",
+          "pattern": "^-?\\d+$"
+        },
+        "Lines": {
+          "type": [
+            "array",
+            "null"
+          ],
+          "items": {
+            "$ref": "#/$defs/schemaLine"
+          }
+        },
+        "Note": {
+          "type": "string"
+        },
+        "Currency": {
+          "$ref": "#/$defs/schemaCurrency"
+        },
+        "Payment": {
+          "$ref": "#/$defs/schemaPayment"
+        },
+        "parent": {
+          "anyOf": [
+            {
+              "$ref": "#/$defs/schemaOrder"
+            },
+            {
+              "type": "null"
+            }
+          ]
+        }
+      },
+      "required": [
+        "ID",
+        "Quantity",
+        "Lines",
+        "Currency",
+        "Payment"
+      ]
+    },
+    "schemaOrderID": {
+      "description": "schemaOrderID corresponds to Go type gozod_test.schemaOrderID (in package \"github.com/softwaretechnik-berlin/goats/gotypes/gozod_test\").",
+      "type": "string",
+      "pattern": "^order-(\\d+)$"
+    },
+    "schemaPayment": {
+      "description": "schemaPayment corresponds to Go type gozod_test.schemaPayment (in package \"github.com/softwaretechnik-berlin/goats/gotypes/gozod_test\").",
+      "oneOf": [
+        {
+          "$ref": "#/$defs/schemaCard"
+        },
+        {
+          "$ref": "#/$defs/schemaInvoice"
+        }
+      ],
+      "discriminator": {
+        "propertyName": "kind"
+      }
+    }
+  }
+}`, string(json))
+}
+
+type schemaCounters struct {
+	Total   int64
+	Quoted  uint64 `json:",string"`
+	Parent  *int64 `json:",string"`
+	Number  int64  `json:",string" gotypes:",int=number"`
+	Enabled bool   `json:",string"`
+}
+
+func TestJSONSchemasDescribeTheIntegersOnTheWire(t *testing.T) {
+	m := gozod.NewJSONSchemaMapper(gozod.WithCommentsLoader(withoutComments{}), gozod.WithIntegerPolicy(gozod.IntegersAsBigInts))
+	require.NoError(t, m.ResolveAll(reflective.TypeFor[schemaCounters]()))
+	document, err := gozod.JSONSchema(m)
+	require.NoError(t, err)
+	json, err := document.Marshal()
+	require.NoError(t, err)
+	assert.Equal(t, `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "$defs": {
+    "schemaCounters": {
+      "description": "schemaCounters corresponds to Go type gozod_test.schemaCounters (in package \"github.com/softwaretechnik-berlin/goats/gotypes/gozod_test\").",
+      "type": "object",
+      "properties": {
+        "Total": {
+          "type": "integer"
+        },
+        "Quoted": {
+          "type": "string",
+          "pattern": "^\\d+$"
+        },
+        "Parent": {
+          "type": [
+            "string",
+            "null"
+          ],
+          "pattern": "^-?\\d+$"
+        },
+        "Number": {
+          "type": "string",
+          "pattern": "^-?\\d+$"
+        },
+        "Enabled": {
+          "type": "string",
+          "pattern": "^(?:true|false)$"
+        }
+      },
+      "required": [
+        "Total",
+        "Quoted",
+        "Parent",
+        "Number",
+        "Enabled"
+      ]
+    }
+  }
+}`, string(json))
+}
@@ -0,0 +1,75 @@
+package gozod_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+	"github.com/softwaretechnik-berlin/goats/gotypes/gozod"
+	"github.com/softwaretechnik-berlin/goats/gotypes/ts"
+)
+
+type (
+	// Month collides with time.Month.
+	Month    string
+	calendar struct {
+		Local    Month
+		Standard time.Month
+	}
+)
+
+// Number would shadow the global Number.
+type Number float64
+
+func TestTypesWhoseNamesWouldShadowGlobalsArePrefixedWithTheirPackage(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader))
+	assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[Number]()).Value, "", `gozod_testNumber`)
+	assert.NoError(t, m.Err())
+
+	valibotMapper := gozod.NewValibotMapper(gozod.WithCommentsLoader(withoutComments{}), gozod.WithStandardLibrary(), gozod.WithIntegerPolicy(gozod.SafeIntegers))
+	valibotMapper.Resolve(reflective.TypeFor[standardLibraryStruct]())
+	assert.NoError(t, valibotMapper.Err())
+	assert.NotContains(t, gozod.SupportingDeclarations(valibotMapper).String(), "export const Number")
+}
+
+func TestNamesShadowingGlobalsAreReported(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader), gozod.When[Month]().Named("Date"))
+	m.Resolve(reflective.TypeFor[Month]())
+	assert.EqualError(t, m.Err(), `gozod_test.Month: gozod_test.Month can't be declared as Date, since that would shadow the JavaScript global the generated code refers to; give it another name, e.g. with WithName`)
+}
+
+func TestInstantiatedGenericTypesHaveFlattenedNames(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader))
+	assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[page[pagedUser]]()).Value, "", `page_pagedUser`)
+	assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[result[[]*pagedUser, map[string]int]]()).Value, "", `result_ListOf_PtrTo_pagedUser_map_string_int`)
+	assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[result[pagedUser, map[string][2]int]]()).Value, "", `result_pagedUser_map_string_ArrayOf2_int`)
+	assert.NoError(t, m.Err())
+}
+
+func TestNameCollisionsAreReported(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader))
+	m.Resolve(reflective.TypeFor[calendar]())
+	assert.EqualError(t, m.Err(), `gozod_test.Month, time.Month would all be declared as Month; give them distinct names, e.g. with WithNamingStrategy or WithName`)
+	_, err := gozod.GenerateSource(m)
+	assert.EqualError(t, err, m.Err().Error())
+}
+
+func TestPackagePrefixedTypeNames(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader), gozod.WithNamingStrategy(gozod.PackagePrefixedTypeNames))
+	assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[calendar]()).Value, "", `gozod_testcalendar`)
+	assert.NoError(t, m.Err())
+	assert.Contains(t, gozod.SupportingDeclarations(m).String(), `export const timeMonth = z.number().int().brand("timeMonth");`)
+	assert.Contains(t, gozod.SupportingDeclarations(m).String(), `export const gozod_testMonth = z.string().brand("gozod_testMonth");`)
+}
+
+func TestCustomNamingStrategy(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader), gozod.WithNamingStrategy(func(t goinsp.Type) ts.Identifier {
+		return "Api" + gozod.FlattenedTypeNames(t)
+	}), gozod.When[time.Month]().Named("StandardMonth"))
+	assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[calendar]()).Value, "", `Apicalendar`)
+	assert.NoError(t, m.Err())
+	assert.Contains(t, gozod.SupportingDeclarations(m).String(), `export const StandardMonth = z.number().int().brand("StandardMonth");`)
+}
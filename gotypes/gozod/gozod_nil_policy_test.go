@@ -0,0 +1,77 @@
+package gozod_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+	"github.com/softwaretechnik-berlin/goats/gotypes/gozod"
+)
+
+type (
+	guaranteedTags  []string
+	nilPolicyStruct struct {
+		Default  []string
+		Distinct map[string]int `gotypes:",nil=null"`
+		Rejected []byte         `gotypes:",nil=reject"`
+		Empty    []int          `gotypes:",nil=empty"`
+		Tags     guaranteedTags
+	}
+)
+
+func TestNilPolicies(t *testing.T) {
+	for _, c := range []struct {
+		option                            gozod.Option
+		stringSlice, stringMap, byteSlice string
+	}{
+		{gozod.WithNilPolicy(gozod.NilAsEmpty), `z.array(z.string()).nullable().transform(a => a ?? [])`, `z.record(z.string(), z.string()).nullable().transform(r => r ?? {})`, `z.string().nullable().transform(a => a ?? "")`},
+		{gozod.WithNullableSlices(), `z.array(z.string()).nullable()`, `z.record(z.string(), z.string()).nullable()`, `z.string().nullable()`},
+		{gozod.WithNonNullSlices(), `z.array(z.string())`, `z.record(z.string(), z.string())`, `z.string()`},
+	} {
+		m := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader), c.option)
+		assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[[]string]()).Value, z, c.stringSlice)
+		assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[map[string]string]()).Value, z, c.stringMap)
+		assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[[]byte]()).Value, z, c.byteSlice)
+	}
+}
+
+func TestNilPoliciesForTypesAndFields(t *testing.T) {
+	m := gozod.NewMapper(
+		gozod.WithCommentsLoader(sharedCommentsLoader),
+		gozod.When[guaranteedTags]().NilPolicy(gozod.NilRejected),
+		gozod.WithNullableSlices(),
+	)
+	assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[nilPolicyStruct]()).Value, "", `nilPolicyStruct`)
+	assertTypeScript(t, gozod.SupportingDeclarations(m), z, `/**
+ * guaranteedTags corresponds to Go type gozod_test.guaranteedTags (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const guaranteedTags = z.array(z.string()).brand("guaranteedTags");
+export type guaranteedTags = z.infer<typeof guaranteedTags>;
+
+/**
+ * nilPolicyStruct corresponds to Go type gozod_test.nilPolicyStruct (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const nilPolicyStruct = z.object({
+    Default: z.array(z.string()).nullable(),
+    Distinct: z.record(z.string(), z.number().int()).nullable(),
+    Rejected: z.string(),
+    Empty: z.array(z.number().int()).nullable().transform(a => a ?? []),
+    Tags: guaranteedTags,
+});
+export type nilPolicyStruct = z.infer<typeof nilPolicyStruct>;
+`)
+}
+
+func TestFieldNilPoliciesMustBeKnown(t *testing.T) {
+	assert.EqualError(t, gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader)).ResolveAll(reflective.TypeFor[struct {
+		Values []string `gotypes:",nil=never"`
+	}]()), `struct { Values []string "gotypes:\",nil=never\"" }.Values: unknown nil policy "never" in gotypes tag ",nil=never"; the known policies are empty, null and reject`)
+}
+
+func TestNilPoliciesOfOptionsMustBeKnown(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader), gozod.WithNilPolicy(gozod.NilPolicy(9)), gozod.When[[]string]().NilPolicy(gozod.NilPolicy(-1)))
+	assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[[]string]()).Value, z, `z.array(z.string()).nullable().transform(a => a ?? [])`)
+	assert.EqualError(t, m.Err(), `unknown nil policy NilPolicy(9); the known policies are NilAsEmpty, NilAsNull and NilRejected
+unknown nil policy NilPolicy(-1); the known policies are NilAsEmpty, NilAsNull and NilRejected`)
+}
@@ -0,0 +1,262 @@
+package gozod_test
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+	"github.com/softwaretechnik-berlin/goats/gotypes/gozod"
+	"github.com/softwaretechnik-berlin/goats/gotypes/openapi"
+)
+
+type (
+	// apiOrder is an order of the API.
+	apiOrder struct {
+		ID    apiOrderID
+		Lines []apiLine
+	}
+	apiOrderID string
+	apiLine    struct {
+		Product string
+		Count   uint8
+	}
+	apiOrderPath struct {
+		// ID identifies the order.
+		ID apiOrderID `json:"id"`
+	}
+	apiOrdersQuery struct {
+		Limit  uint8  `json:"limit"`
+		Cursor string `json:"cursor,omitempty"`
+	}
+	apiProblem struct {
+		Detail string `json:"detail"`
+	}
+)
+
+func TestOpenAPIDocumentsDescribeOperations(t *testing.T) {
+	m := gozod.NewJSONSchemaMapper(gozod.WithCommentsLoader(sharedCommentsLoader))
+	document, err := gozod.OpenAPI(m, openapi.Info{Title: "Orders", Version: "1.0"},
+		gozod.Operation{
+			Method:          http.MethodGet,
+			Path:            "/orders",
+			ID:              "listOrders",
+			QueryParameters: reflective.TypeFor[apiOrdersQuery](),
+			Responses:       []gozod.Response{{Status: http.StatusOK, Type: reflective.TypeFor[[]apiOrder]()}},
+		},
+		gozod.Operation{
+			Method:         http.MethodPut,
+			Path:           "/orders/{id}",
+			ID:             "putOrder",
+			PathParameters: reflective.TypeFor[apiOrderPath](),
+			Request:        reflective.TypeFor[apiOrder](),
+			Responses: []gozod.Response{
+				{Status: http.StatusNoContent},
+				{Status: http.StatusBadRequest, Description: "The order is invalid.", Type: reflective.TypeFor[apiProblem]()},
+			},
+		},
+	)
+	require.NoError(t, err)
+	json, err := document.Marshal()
+	require.NoError(t, err)
+	assert.Equal(t, `{
+  "openapi": "3.1.0",
+  "info": {
+    "title": "Orders",
+    "version": "1.0"
+  },
+  "paths": {
+    "/orders": {
+      "get": {
+        "operationId": "listOrders",
+        "parameters": [
+          {
+            "name": "limit",
+            "in": "query",
+            "required": true,
+            "schema": {
+              "type": "integer",
+              "minimum": 0,
+              "maximum": 255
+            }
+          },
+          {
+            "name": "cursor",
+            "in": "query",
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "type": [
+                    "array",
+                    "null"
+                  ],
+                  "items": {
+                    "$ref": "#/components/schemas/apiOrder"
+                  }
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/orders/{id}": {
+      "put": {
+        "operationId": "putOrder",
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "description": "ID identifies the order.",
+            "required": true,
+            "schema": {
+              "$ref": "#/components/schemas/apiOrderID"
+            }
+          }
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/apiOrder"
+              }
+            }
+          }
+        },
+        "responses": {
+          "204": {
+            "description": "No Content"
+          },
+          "400": {
+            "description": "The order is invalid.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/apiProblem"
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "apiLine": {
+        "description": "apiLine corresponds to Go type gozod_test.apiLine (in package \"github.com/softwaretechnik-berlin/goats/gotypes/gozod_test\").",
+        "type": "object",
+        "properties": {
+          "Product": {
+            "type": "string"
+          },
+          "Count": {
+            "type": "integer",
+            "minimum": 0,
+            "maximum": 255
+          }
+        },
+        "required": [
+          "Product",
+          "Count"
+        ]
+      },
+      "apiOrder": {
+        "description": "apiOrder corresponds to Go type gozod_test.apiOrder (in package \"github.com/softwaretechnik-berlin/goats/gotypes/gozod_test\").\nThe comment on the original Go type follows.\n\napiOrder is an order of the API.",
+        "type": "object",
+        "properties": {
+          "ID": {
+            "$ref": "#/components/schemas/apiOrderID"
+          },
+          "Lines": {
+            "type": [
+              "array",
+              "null"
+            ],
+            "items": {
+              "$ref": "#/components/schemas/apiLine"
+            }
+          }
+        },
+        "required": [
+          "ID",
+          "Lines"
+        ]
+      },
+      "apiOrderID": {
+        "description": "apiOrderID corresponds to Go type gozod_test.apiOrderID (in package \"github.com/softwaretechnik-berlin/goats/gotypes/gozod_test\").",
+        "type": "string"
+      },
+      "apiProblem": {
+        "description": "apiProblem corresponds to Go type gozod_test.apiProblem (in package \"github.com/softwaretechnik-berlin/goats/gotypes/gozod_test\").",
+        "type": "object",
+        "properties": {
+          "detail": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "detail"
+        ]
+      }
+    }
+  }
+}`, string(json))
+}
+
+func TestOpenAPIOperationsAreChecked(t *testing.T) {
+	m := gozod.NewJSONSchemaMapper(gozod.WithCommentsLoader(sharedCommentsLoader))
+	_, err := gozod.OpenAPI(m, openapi.Info{Title: "Orders", Version: "1.0"},
+		gozod.Operation{
+			Method:         http.MethodGet,
+			Path:           "/orders/{order}/lines/{line}",
+			PathParameters: reflective.TypeFor[apiOrderPath](),
+			Responses:      []gozod.Response{{Status: http.StatusOK, Type: reflective.TypeFor[apiLine]()}},
+		},
+		gozod.Operation{
+			Method:          "FETCH",
+			Path:            "/orders",
+			QueryParameters: reflective.TypeFor[string](),
+		},
+	)
+	assert.EqualError(t, err, `GET /orders/{order}/lines/{line}: path parameter id of gozod_test.apiOrderPath isn't part of the path
+GET /orders/{order}/lines/{line}: path parameter order isn't declared, e.g. as a field of the PathParameters
+GET /orders/{order}/lines/{line}: path parameter line isn't declared, e.g. as a field of the PathParameters
+FETCH /orders: the query parameters must be given by a struct type, not string
+FETCH /orders: no responses are declared
+FETCH /orders: unsupported HTTP method "FETCH"`)
+}
+
+func TestOpenAPIComponentsIncludeParameterTypesThatBodiesReferTo(t *testing.T) {
+	m := gozod.NewJSONSchemaMapper(gozod.WithCommentsLoader(sharedCommentsLoader))
+	document, err := gozod.OpenAPI(m, openapi.Info{Title: "Orders", Version: "1.0"},
+		gozod.Operation{
+			Method:          http.MethodGet,
+			Path:            "/orders",
+			QueryParameters: reflective.TypeFor[apiOrdersQuery](),
+			Responses:       []gozod.Response{{Status: http.StatusOK, Type: reflective.TypeFor[[]apiOrdersQuery]()}},
+		},
+		gozod.Operation{
+			Method:         http.MethodGet,
+			Path:           "/orders/{id}",
+			PathParameters: reflective.TypeFor[apiOrderPath](),
+			Responses:      []gozod.Response{{Status: http.StatusOK}},
+		},
+	)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"apiOrdersQuery", "apiOrderID"}, slices.Collect(maps.Keys(document.Components.Schemas)))
+	assert.Equal(t, "#/components/schemas/apiOrdersQuery", document.Paths["/orders"].Get.Responses["200"].Content["application/json"].Schema.Items.Ref)
+}
@@ -64,16 +64,16 @@ func TestGenerateForPrimitiveTypes(t *testing.T) {
 		rejects{`null`, `undefined`, `0.5`},
 	)
 	assertSimpleSchemaFor[int8](t, z,
-		`z.number().int()`,
+		`z.number().int().min(-128).max(127)`,
 		examples[int8]{
 			simpleExample[int8](0, `0`),
 			simpleExample[int8](-128, `-128`),
 			simpleExample[int8](127, `127`),
 		},
-		rejects{`null`, `undefined`, `0.5`},
+		rejects{`128`, `-129`, `null`, `undefined`, `0.5`},
 	)
 	assertSimpleSchemaFor[int16](t, z,
-		`z.number().int()`,
+		`z.number().int().min(-32768).max(32767)`,
 		examples[int16]{
 			simpleExample[int16](0, `0`),
 			simpleExample[int16](-12345, `-12345`),
@@ -82,7 +82,7 @@ func TestGenerateForPrimitiveTypes(t *testing.T) {
 		rejects{`null`, `undefined`, `0.5`},
 	)
 	assertSimpleSchemaFor[int32](t, z,
-		`z.number().int()`,
+		`z.number().int().min(-2147483648).max(2147483647)`,
 		examples[int32]{
 			simpleExample[int32](0, `0`),
 			simpleExample[int32](-12345, `-12345`),
@@ -108,15 +108,15 @@ func TestGenerateForPrimitiveTypes(t *testing.T) {
 		rejects{`null`, `undefined`, `-1`, `0.5`},
 	)
 	assertSimpleSchemaFor[uint8](t, z,
-		`z.number().nonnegative().int()`,
+		`z.number().nonnegative().int().max(255)`,
 		examples[uint8]{
 			simpleExample[uint8](0, `0`),
 			simpleExample[uint8](127, `127`),
 		},
-		rejects{`null`, `undefined`, `-1`, `0.5`},
+		rejects{`256`, `null`, `undefined`, `-1`, `0.5`},
 	)
 	assertSimpleSchemaFor[uint16](t, z,
-		`z.number().nonnegative().int()`,
+		`z.number().nonnegative().int().max(65535)`,
 		examples[uint16]{
 			simpleExample[uint16](0, `0`),
 			simpleExample[uint16](12345, `12345`),
@@ -124,7 +124,7 @@ func TestGenerateForPrimitiveTypes(t *testing.T) {
 		rejects{`null`, `undefined`, `-1`, `0.5`},
 	)
 	assertSimpleSchemaFor[uint32](t, z,
-		`z.number().nonnegative().int()`,
+		`z.number().nonnegative().int().max(4294967295)`,
 		examples[uint32]{
 			simpleExample[uint32](0, `0`),
 			simpleExample[uint32](12345, `12345`),
@@ -164,11 +164,12 @@ func TestGenerateForSliceTypes(t *testing.T) {
 	// side we usually want to treat a null as semantically equivalent to an empty array/string. So by default, slices
 	// schemas will accept null but transform it into an empty value.
 	//
-	// TODO Untransformed nullable types that preserve the null vs. empty distinction can be enabled with
+	// Untransformed nullable types that preserve the null vs. empty distinction can be enabled with
 	// `WithNullableSlices()`.
 	//
-	// TODO Finally, if you're willing to assert that you will always populate these types with a non-nil value, you can
+	// Finally, if you're willing to assert that you will always populate these types with a non-nil value, you can
 	// use `WithNonNullSlices()` to suppress the nullability completely and reject null values.
+	// See TestNilPolicies.
 	assertSimpleSchemaFor[[]string](t, z,
 		`z.array(z.string()).nullable().transform(a => a ?? [])`,
 		examples[[]string]{
@@ -242,11 +243,12 @@ func TestGenerateForMapTypes(t *testing.T) {
 	// side we usually want to treat a null as semantically equivalent to an empty object. So by default, map
 	// schemas will accept null but transform it into an empty value.
 	//
-	// TODO Untransformed nullable types that preserve the null vs. empty distinction can be enabled with
+	// Untransformed nullable types that preserve the null vs. empty distinction can be enabled with
 	// `WithNullableSlices()`.
 	//
-	// TODO Finally, if you're willing to assert that you will always populate these types with a non-nil value, you can
+	// Finally, if you're willing to assert that you will always populate these types with a non-nil value, you can
 	// use `WithNonNullSlices()` to suppress the nullability completely and reject null values.
+	// See TestNilPolicies.
 	assertSimpleSchemaFor[map[string]string](t, z,
 		`z.record(z.string(), z.string()).nullable().transform(r => r ?? {})`,
 		examples[map[string]string]{
@@ -504,42 +506,6 @@ export type omittablesStruct = z.infer<typeof omittablesStruct>;
 
 // TODO handle Map types
 
-//type (
-//	embeddedableStringA struct {
-//		A string
-//	}
-//	embeddableStringBWithInnerA struct {
-//		B string
-//		embeddedableStringA
-//	}
-//	embeddedableBoolA struct {
-//		A bool
-//	}
-//	embeddableBoolBWithInnerA struct {
-//		B bool
-//		embeddedableStringA
-//	}
-//)
-//
-//func TestEmbbeddedTypes(t *testing.T) {
-//	// TODO improve behaviour around non-object embedded types that don't define their own behaviour
-//	//assertSimpleSchemaFor[struct{ string }](t,
-//	//	z, `z.object({})`,
-//	//	examples[struct{ string }]{
-//	//		example[struct{ string }]{struct{ string }{}, []struct{ string }{struct{ string }{"foo"}}, `{}`},
-//	//	},
-//	//	rejects{`null`, `undefined`, `"foo"`},
-//	//)
-//	//assertSimpleSchemaFor[struct{ string }](t,
-//	//	z, `z.string()`,
-//	//	examples[struct{ string }]{
-//	//		simpleExample(struct{ string }{"foo"}, `"foo"`),
-//	//	},
-//	//	rejects{`null`, `undefined`, `0`},
-//	//)
-//}
-
-// TODO behaviour around competing property names in embedded structs
 //func TestGenerate(t *testing.T) {
 //	assertSchemaWithSupportFor[struct {
 //		embeddable[string]
@@ -573,9 +539,7 @@ export type omittablesStruct = z.infer<typeof omittablesStruct>;
 //    DoubleEmbedded: z.string(),
 //})`)
 //
-//	// TODO generics (alt 1: instantiate with _ in name; alt 2: generate schema functions)
-//	// TODO self-recursive types
-//	// TODO generally recursive types that recurse in only 1 type
+//	// TODO generics: instantiate with _ in name (schema functions are generated with WithGenericFactories)
 //	// TODO alternate method for optional properties that excludes the ability to assign undefined
 //}
 //
@@ -0,0 +1,274 @@
+package gozod_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+	"github.com/softwaretechnik-berlin/goats/gotypes/gozod"
+	"github.com/softwaretechnik-berlin/goats/gotypes/pydantic"
+	"github.com/softwaretechnik-berlin/goats/gotypes/zod"
+)
+
+func TestPydanticModelsFollowTheSameRulesAsZod(t *testing.T) {
+	m := gozod.NewPydanticMapper(schemaOrderOptions()...)
+	require.NoError(t, m.ResolveAll(reflective.TypeFor[schemaOrder]()))
+	module, err := gozod.Pydantic(m)
+	require.NoError(t, err)
+	assert.Equal(t, `from __future__ import annotations
+
+import re
+from collections.abc import Callable
+from typing import Annotated, Any, Literal, Optional, Union
+
+from pydantic import BaseModel, BeforeValidator, ConfigDict, Field, Json
+
+
+def _template(pattern: str, template: str, *names: str) -> Callable[[Any], Any]:
+    regex = re.compile(pattern)
+
+    def parse(value: Any) -> Any:
+        match = regex.fullmatch(value) if isinstance(value, str) else None
+        if match is None:
+            raise ValueError(f"expected string of the form {template} matching {pattern}")
+        return dict(zip(names, match.groups())) if names else match.group(1)
+
+    return parse
+
+
+class schemaCard(BaseModel):
+    """schemaCard corresponds to Go type gozod_test.schemaCard (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test")."""
+
+    model_config = ConfigDict(populate_by_name=True)
+
+    kind: Literal["card"]
+    number: str = Field(alias="Number")
+
+
+schemaCurrency = str
+"""schemaCurrency corresponds to Go type gozod_test.schemaCurrency (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+The comment on the original Go type follows.
+
+schemaCurrency is marshalled as its ISO 4217 code.
+"""
+
+
+class schemaInvoice(BaseModel):
+    """schemaInvoice corresponds to Go type gozod_test.schemaInvoice (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test")."""
+
+    model_config = ConfigDict(populate_by_name=True)
+
+    kind: Literal["invoice"]
+    days: Annotated[int, Field(ge=0, le=65535)] = Field(alias="Days")
+
+
+class schemaLine(BaseModel):
+    """schemaLine corresponds to Go type gozod_test.schemaLine (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test")."""
+
+    model_config = ConfigDict(populate_by_name=True)
+
+    product: Annotated[str, Field(min_length=1)] = Field(alias="Product")
+    count: Annotated[int, Field(ge=0, le=255)] = Field(alias="Count")
+
+
+schemaOrderID = Annotated[int, Field(ge=0), BeforeValidator(_template(r"^order-(\d+)$", "order-{}"))]
+"""schemaOrderID corresponds to Go type gozod_test.schemaOrderID (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test")."""
+
+
+schemaPayment = Annotated[Union[schemaCard, schemaInvoice], Field(discriminator="kind")]
+"""schemaPayment corresponds to Go type gozod_test.schemaPayment (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test")."""
+
+
+class schemaOrder(BaseModel):
+    """schemaOrder corresponds to Go type gozod_test.schemaOrder (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+    The comment on the original Go type follows.
+
+    schemaOrder is placed by a customer.
+    """
+
+    model_config = ConfigDict(populate_by_name=True, use_attribute_docstrings=True)
+
+    id: schemaOrderID = Field(alias="ID")
+    """ID identifies the order."""
+    quantity: Json[Annotated[int, Field(ge=-2147483648, le=2147483647)]] = Field(alias="Quantity")
+    lines: Annotated[list[schemaLine], BeforeValidator(lambda a: [] if a is None else a)] = Field(alias="Lines")
+    note: Optional[str] = Field(default=None, alias="Note")
+    currency: schemaCurrency = Field(alias="Currency")
+    payment: schemaPayment = Field(alias="Payment")
+    parent: Optional["schemaOrder"] = None
+`, module)
+}
+
+// schemaOrderOptions are the options under which zod accepts the JSON of schemaOrderJSON.
+func schemaOrderOptions() []gozod.Option {
+	return []gozod.Option{
+		gozod.WithCommentsLoader(sharedCommentsLoader),
+		gozod.When[schemaOrderID]().Template("order-{}"),
+		gozod.WithDiscriminatedUnion(reflective.TypeFor[schemaPayment](), "kind", reflective.TypeFor[schemaCard](), reflective.TypeFor[schemaInvoice]()),
+		gozod.WithDiscriminator(reflective.TypeFor[schemaCard](), "kind", "card"),
+		gozod.WithDiscriminator(reflective.TypeFor[schemaInvoice](), "kind", "invoice"),
+	}
+}
+
+// schemaOrderJSON holds sample JSON that the models of schemaOrder under schemaOrderOptions should accept, and sample
+// JSON that they should reject.
+var schemaOrderJSON = struct {
+	accepts, rejects []string
+}{
+	accepts: []string{
+		`{"ID":"order-42","Quantity":"3","Lines":[{"Product":"tea","Count":2}],"Currency":"EUR","Payment":{"kind":"card","Number":"4242"}}`,
+		`{"ID":"order-7","Quantity":"-1","Lines":null,"Note":"gift","Currency":"USD","Payment":{"kind":"invoice","Days":30},"parent":{"ID":"order-6","Quantity":"1","Lines":[],"Currency":"USD","Payment":{"kind":"invoice","Days":0}}}`,
+		`{"ID":"order-8","Quantity":"2147483647","Lines":[],"Currency":"USD","Payment":{"kind":"card","Number":""},"parent":null}`,
+	},
+	rejects: []string{
+		`{"ID":"42","Quantity":"3","Lines":[],"Currency":"EUR","Payment":{"kind":"card","Number":"4242"}}`,
+		`{"ID":"order-42","Quantity":3,"Lines":[],"Currency":"EUR","Payment":{"kind":"card","Number":"4242"}}`,
+		`{"ID":"order-42","Quantity":"2147483648","Lines":[],"Currency":"EUR","Payment":{"kind":"card","Number":"4242"}}`,
+		`{"ID":"order-42","Quantity":"3","Lines":[{"Product":"","Count":2}],"Currency":"EUR","Payment":{"kind":"card","Number":"4242"}}`,
+		`{"ID":"order-42","Quantity":"3","Lines":[{"Product":"tea","Count":256}],"Currency":"EUR","Payment":{"kind":"card","Number":"4242"}}`,
+		`{"ID":"order-42","Quantity":"3","Lines":[],"Currency":"EUR","Payment":{"kind":"cash"}}`,
+		`{"ID":"order-42","Quantity":"3","Lines":[],"Currency":"EUR","Payment":{"kind":"invoice","Days":-1}}`,
+		`{"ID":"order-42","Quantity":"3","Lines":[],"Payment":{"kind":"card","Number":"4242"}}`,
+	},
+}
+
+func TestPydanticModelsAcceptAndRejectSampleJSON(t *testing.T) {
+	m := gozod.NewPydanticMapper(schemaOrderOptions()...)
+	require.NoError(t, m.ResolveAll(reflective.TypeFor[schemaOrder]()))
+	module, err := gozod.Pydantic(m)
+	require.NoError(t, err)
+	assertPydanticAcceptsAndRejects(t, module, "schemaOrder", schemaOrderJSON.accepts, schemaOrderJSON.rejects)
+}
+
+// assertPydanticAcceptsAndRejects validates the given JSON with the named model of the given module, if Pydantic is
+// installed, and asserts that the model accepts and rejects it as given.
+func assertPydanticAcceptsAndRejects(t *testing.T, module, model string, accepts, rejects []string) {
+	if exec.Command("python3", "-c", "import pydantic").Run() != nil {
+		t.Skip("Pydantic isn't installed")
+	}
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "models.py"), []byte(module), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "validate.py"), []byte(`import json
+import sys
+
+from pydantic import TypeAdapter, ValidationError
+
+import models
+
+adapter = TypeAdapter(getattr(models, sys.argv[1]))
+for document in json.load(sys.stdin):
+    try:
+        adapter.validate_json(document)
+        print("accepts")
+    except ValidationError:
+        print("rejects")
+`), 0o644))
+
+	documents, err := json.Marshal(append(slices.Clone(accepts), rejects...))
+	require.NoError(t, err)
+	cmd := exec.Command("python3", "validate.py", model)
+	cmd.Dir = dir
+	cmd.Stdin = bytes.NewReader(documents)
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	require.NoError(t, err)
+
+	verdicts := strings.Fields(string(output))
+	require.Len(t, verdicts, len(accepts)+len(rejects))
+	for i, document := range accepts {
+		assert.Equal(t, "accepts", verdicts[i], "%s should be accepted", document)
+	}
+	for i, document := range rejects {
+		assert.Equal(t, "rejects", verdicts[len(accepts)+i], "%s should be rejected", document)
+	}
+}
+
+func TestPydanticMappersReportHandWrittenSchemas(t *testing.T) {
+	currency := zod.ZodTypeText("z.string().length(3)")
+
+	m := gozod.NewPydanticMapper(gozod.WithSchema(reflective.TypeFor[equivalentCurrency](), currency))
+	assert.EqualError(t, m.ResolveAll(reflective.TypeFor[equivalentCurrency]()), "gozod_test.equivalentCurrency: the hand-written schema has no Pydantic equivalent, which would accept any value; state it with WithPydanticType")
+
+	m = gozod.NewPydanticMapper(
+		gozod.WithSchema(reflective.TypeFor[equivalentCurrency](), currency),
+		gozod.WithPydanticType(reflective.TypeFor[equivalentCurrency](), pydantic.Expr("constr(min_length=3, max_length=3)", pydantic.Import{Module: "pydantic", Name: "constr"})),
+	)
+	require.NoError(t, m.ResolveAll(reflective.TypeFor[equivalentCurrency]()))
+	module, err := gozod.Pydantic(m)
+	require.NoError(t, err)
+	assert.Contains(t, module, "equivalentCurrency = constr(min_length=3, max_length=3)")
+}
+
+func TestPydanticGenericModels(t *testing.T) {
+	m := gozod.NewPydanticMapper(genericsOptions(gozod.WithGenericFactories(), gozod.When[typedRole]().AsEnum())...)
+	require.NoError(t, m.ResolveAll(reflective.TypeFor[typedAccount]()))
+	module, err := gozod.Pydantic(m)
+	require.NoError(t, err)
+	assert.Equal(t, `from __future__ import annotations
+
+from typing import Annotated, Generic, Literal, Optional, TypeVar
+
+from pydantic import BaseModel, BeforeValidator, ConfigDict, Field
+
+T = TypeVar("T")
+
+
+class page(BaseModel, Generic[T]):
+    """page corresponds to Go type gozod_test.page[T] (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+    The comment on the original Go type follows.
+
+    page is a page of items.
+    """
+
+    model_config = ConfigDict(populate_by_name=True, use_attribute_docstrings=True)
+
+    items: Annotated[list[T], BeforeValidator(lambda a: [] if a is None else a)]
+    """Items are the items on the page."""
+    next: Optional[str] = Field(alias="Next")
+
+
+class pagedUser(BaseModel):
+    """pagedUser corresponds to Go type gozod_test.pagedUser (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test")."""
+
+    model_config = ConfigDict(populate_by_name=True)
+
+    name: str = Field(alias="Name")
+
+
+typedHandle = str
+"""typedHandle corresponds to Go type gozod_test.typedHandle (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+The comment on the original Go type follows.
+
+typedHandle is only branded in schemas.
+"""
+
+
+typedRole = Literal["admin", "guest"]
+"""typedRole corresponds to Go type gozod_test.typedRole (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test")."""
+
+
+class typedAccount(BaseModel):
+    """typedAccount corresponds to Go type gozod_test.typedAccount (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+    The comment on the original Go type follows.
+
+    typedAccount is declared as an interface.
+    """
+
+    model_config = ConfigDict(populate_by_name=True, use_attribute_docstrings=True)
+
+    handle: typedHandle = Field(alias="Handle")
+    """Handle is shown to other users."""
+    roles: Annotated[list[typedRole], BeforeValidator(lambda a: [] if a is None else a)] = Field(alias="Roles")
+    friends: Optional[Annotated[list[Optional["typedAccount"]], BeforeValidator(lambda a: [] if a is None else a)]] = Field(default=None, alias="Friends")
+    users: page[pagedUser] = Field(alias="Users")
+`, module)
+}
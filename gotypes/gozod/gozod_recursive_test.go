@@ -0,0 +1,89 @@
+package gozod_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+	"github.com/softwaretechnik-berlin/goats/gotypes/gozod"
+)
+
+type (
+	treeNode struct {
+		Label    string
+		Children []treeNode
+	}
+	employee struct {
+		Name    string
+		Manager *employee `json:",omitempty"`
+		Team    team
+	}
+	team struct {
+		Members []employee
+	}
+)
+
+func TestSelfRecursiveTypes(t *testing.T) {
+	assertSchemaWithSupportFor[treeNode](t,
+		``, `treeNode`,
+		z, `/**
+ * treeNode corresponds to Go type gozod_test.treeNode (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export type treeNode = {
+    Label: string;
+    Children: treeNode[];
+};
+export type treeNodeInput = {
+    Label: string;
+    Children: treeNodeInput[] | null;
+};
+export const treeNode: z.ZodType<treeNode, z.ZodTypeDef, treeNodeInput> = z.object({
+    Label: z.string(),
+    Children: z.array(z.lazy(() => treeNode)).nullable().transform(a => a ?? []),
+});
+`,
+		examples[treeNode]{
+			simpleExample(treeNode{"root", []treeNode{{"leaf", []treeNode{}}}}, `{"Label":"root","Children":[{"Label":"leaf","Children":[]}]}`),
+		},
+		rejects{`null`, `undefined`, `{}`},
+	)
+}
+
+func TestMutuallyRecursiveTypes(t *testing.T) {
+	assertSchemaWithSupportFor[employee](t,
+		``, `employee`,
+		z, `/**
+ * team corresponds to Go type gozod_test.team (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const team = z.object({ Members: z.array(z.lazy(() => employee)).nullable().transform(a => a ?? []) });
+export type team = z.infer<typeof team>;
+
+/**
+ * employee corresponds to Go type gozod_test.employee (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export type employee = {
+    Name: string;
+    Manager?: employee | null | undefined;
+    Team: team;
+};
+export type employeeInput = {
+    Name: string;
+    Manager?: employeeInput | null | undefined;
+    Team: z.input<typeof team>;
+};
+export const employee: z.ZodType<employee, z.ZodTypeDef, employeeInput> = z.object({
+    Name: z.string(),
+    Manager: z.lazy(() => employee).nullable().optional(),
+    Team: team,
+});
+`,
+		nil,
+		rejects{`null`, `undefined`, `{}`},
+	)
+}
+
+func TestRecursiveTypesMustBeDeclared(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader), gozod.When[treeNode]().Unnamed())
+	assert.EqualError(t, m.ResolveAll(reflective.TypeFor[treeNode]()), "gozod_test.treeNode.Children[]: gozod_test.treeNode refers to itself, but only types that are declared can be recursive")
+}
@@ -0,0 +1,133 @@
+package gozod_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+	"github.com/softwaretechnik-berlin/goats/gotypes/gozod"
+)
+
+type sharedTrackingCode string
+
+type sharedShipment struct {
+	Origin struct {
+		Street  string
+		City    string
+		Country string
+	}
+	Code  sharedTrackingCode
+	Codes map[string][]sharedTrackingCode
+}
+
+type sharedReturn struct {
+	Shipment    sharedShipment
+	Destination struct {
+		Street  string
+		City    string
+		Country string
+	}
+	Code  sharedTrackingCode
+	Codes map[string][]sharedTrackingCode
+}
+
+type sharedPage[T any] struct {
+	Items []struct {
+		Item  T
+		Codes map[string][]sharedTrackingCode
+	}
+}
+
+func TestSharedSubSchemasAreDeclaredOnce(t *testing.T) {
+	m := gozod.NewMapper(
+		gozod.WithCommentsLoader(withoutComments{}),
+		gozod.WithSharedSubSchemas(),
+		gozod.When[sharedTrackingCode]().Unnamed().Template("track-{}"),
+	)
+	require.NoError(t, m.ResolveAll(reflective.TypeFor[sharedReturn]()))
+	assertTypeScript(t, gozod.SupportingDeclarations(m), z, `const shared1 = z.object({
+    Street: z.string(),
+    City: z.string(),
+    Country: z.string(),
+});
+
+const shared2 = z.string().transform((s, ctx) => {
+    const re = /^track-(.*)$/;
+    const match = re.exec(s);
+    if (!match) {
+        ctx.addIssue({ code: z.ZodIssueCode.custom, message: "expected string of the form \"track-{}\" matching " + re });
+        return z.NEVER;
+    }
+    return match[1];
+});
+
+const shared3 = z.record(z.string(), z.array(shared2).nullable().transform(a => a ?? [])).nullable().transform(r => r ?? {});
+
+/**
+ * sharedShipment corresponds to Go type gozod_test.sharedShipment (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const sharedShipment = z.object({
+    Origin: shared1,
+    Code: shared2,
+    Codes: shared3,
+});
+export type sharedShipment = z.infer<typeof sharedShipment>;
+
+/**
+ * sharedReturn corresponds to Go type gozod_test.sharedReturn (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const sharedReturn = z.object({
+    Shipment: sharedShipment,
+    Destination: shared1,
+    Code: shared2,
+    Codes: shared3,
+});
+export type sharedReturn = z.infer<typeof sharedReturn>;
+`)
+}
+
+func TestSharedSubSchemasDontReferToTypeParameters(t *testing.T) {
+	m := gozod.NewMapper(
+		gozod.WithCommentsLoader(withoutComments{}),
+		gozod.WithSharedSubSchemas(),
+		gozod.WithGenericFactories(),
+		gozod.When[sharedTrackingCode]().Unnamed().Template("track-{}"),
+	)
+	require.NoError(t, m.ResolveAll(reflective.TypeFor[sharedPage[sharedShipment]]()))
+	assertTypeScript(t, gozod.SupportingDeclarations(m), z, `const shared1 = z.string().transform((s, ctx) => {
+    const re = /^track-(.*)$/;
+    const match = re.exec(s);
+    if (!match) {
+        ctx.addIssue({ code: z.ZodIssueCode.custom, message: "expected string of the form \"track-{}\" matching " + re });
+        return z.NEVER;
+    }
+    return match[1];
+});
+
+const shared2 = z.record(z.string(), z.array(shared1).nullable().transform(a => a ?? [])).nullable().transform(r => r ?? {});
+
+/**
+ * sharedPage returns the schema corresponding to Go type gozod_test.sharedPage[T] (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test"), given the schemas for its type arguments.
+ */
+export const sharedPage = <T extends z.ZodTypeAny>(t: T) => z.object({ Items: z.array(z.object({
+    Item: t,
+    Codes: shared2,
+})).nullable().transform(a => a ?? []) });
+export type sharedPage<T extends z.ZodTypeAny> = z.infer<ReturnType<typeof sharedPage<T>>>;
+
+/**
+ * sharedShipment corresponds to Go type gozod_test.sharedShipment (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const sharedShipment = z.object({
+    Origin: z.object({
+        Street: z.string(),
+        City: z.string(),
+        Country: z.string(),
+    }),
+    Code: shared1,
+    Codes: shared2,
+});
+export type sharedShipment = z.infer<typeof sharedShipment>;
+`)
+}
@@ -0,0 +1,167 @@
+package gozod_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"math/big"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/parsing/comments"
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+	"github.com/softwaretechnik-berlin/goats/gotypes/gozod"
+	"github.com/softwaretechnik-berlin/goats/gotypes/zod"
+)
+
+type standardLibraryStruct struct {
+	Time     time.Time
+	Duration time.Duration
+	Raw      json.RawMessage
+	Number   json.Number
+	Addr     netip.Addr
+	Int      *big.Int
+	Name     sql.NullString
+	Deleted  sql.NullTime
+}
+
+// withoutComments leaves out the lengthy doc comments of the standard library types.
+type withoutComments struct{}
+
+var _ comments.Loader = withoutComments{}
+
+func (withoutComments) Load(goinsp.Type) (string, error)              { return "", nil }
+func (withoutComments) LoadField(goinsp.Type, string) (string, error) { return "", nil }
+func (withoutComments) LoadDirectives(goinsp.Type) ([]comments.Directive, error) {
+	return nil, nil
+}
+func (withoutComments) LoadMethodDirectives(goinsp.Type, string) ([]comments.Directive, error) {
+	return nil, nil
+}
+func (withoutComments) LoadConstants(goinsp.Type) ([]comments.Constant, error) { return nil, nil }
+
+func TestStandardLibrary(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(withoutComments{}), gozod.WithStandardLibrary())
+	assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[standardLibraryStruct]()).Value, "", `standardLibraryStruct`)
+	// Depending on the Go version, json.RawMessage may be an alias of jsontext.Value.
+	rawMessage := reflective.TypeFor[json.RawMessage]()
+	assertTypeScript(t, gozod.SupportingDeclarations(m), z, `/**
+ * JSONNumber corresponds to Go type json.Number (in package "encoding/json").
+ */
+export const JSONNumber = z.number();
+export type JSONNumber = z.infer<typeof JSONNumber>;
+
+/**
+ * RawMessage corresponds to Go type `+rawMessage.String()+` (in package "`+string(rawMessage.PkgPath())+`").
+ */
+export const RawMessage = z.unknown();
+export type RawMessage = z.infer<typeof RawMessage>;
+
+/**
+ * Int corresponds to Go type big.Int (in package "math/big").
+ */
+export const Int = z.number().int();
+export type Int = z.infer<typeof Int>;
+
+/**
+ * Addr corresponds to Go type netip.Addr (in package "net/netip").
+ */
+export const Addr = z.union([
+    z.string().ip(),
+    z.literal(""),
+]);
+export type Addr = z.infer<typeof Addr>;
+
+/**
+ * Duration corresponds to Go type time.Duration (in package "time").
+ */
+export const Duration = z.number().int().describe("duration in nanoseconds");
+export type Duration = z.infer<typeof Duration>;
+
+/**
+ * Time corresponds to Go type time.Time (in package "time").
+ */
+export const Time = z.string().datetime({ offset: true });
+export type Time = z.infer<typeof Time>;
+
+/**
+ * NullString corresponds to Go type sql.NullString (in package "database/sql").
+ */
+export const NullString = z.object({
+    String: z.string(),
+    Valid: z.boolean(),
+}).transform(n => n.Valid ? n.String : null);
+export type NullString = z.infer<typeof NullString>;
+
+/**
+ * NullTime corresponds to Go type sql.NullTime (in package "database/sql").
+ */
+export const NullTime = z.object({
+    Time: Time,
+    Valid: z.boolean(),
+}).transform(n => n.Valid ? n.Time : null);
+export type NullTime = z.infer<typeof NullTime>;
+
+/**
+ * standardLibraryStruct corresponds to Go type gozod_test.standardLibraryStruct (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const standardLibraryStruct = z.object({
+    Time: Time,
+    Duration: Duration,
+    Raw: RawMessage,
+    Number: JSONNumber,
+    Addr: Addr,
+    Int: Int.nullable(),
+    Name: NullString,
+    Deleted: NullTime,
+});
+export type standardLibraryStruct = z.infer<typeof standardLibraryStruct>;
+`)
+
+	assertExamplesAndRejects(t, examples[standardLibraryStruct]{
+		simpleExample(standardLibraryStruct{
+			Time:     time.Date(2024, 2, 29, 12, 30, 0, 0, time.FixedZone("", 3600)),
+			Duration: 1500 * time.Millisecond,
+			Raw:      json.RawMessage(`{"any":["json"]}`),
+			Number:   "1.5e3",
+			Addr:     netip.MustParseAddr("::1"),
+			Int:      big.NewInt(42),
+			Name:     sql.NullString{String: "name", Valid: true},
+		}, `{"Time":"2024-02-29T12:30:00+01:00","Duration":1500000000,"Raw":{"any":["json"]},"Number":1.5e3,"Addr":"::1","Int":42,"Name":{"String":"name","Valid":true},"Deleted":{"Time":"0001-01-01T00:00:00Z","Valid":false}}`),
+	}, rejects{})
+}
+
+func TestStandardLibraryWithTimeAsDate(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(withoutComments{}), gozod.WithStandardLibrary(), gozod.WithTimeAsDate())
+	m.Resolve(reflective.TypeFor[time.Time]())
+	assertTypeScript(t, gozod.SupportingDeclarations(m), z, `/**
+ * Time corresponds to Go type time.Time (in package "time").
+ */
+export const Time = z.string().datetime({ offset: true }).transform(s => new Date(s));
+export type Time = z.infer<typeof Time>;
+`)
+}
+
+func TestExplicitOptionsTakePrecedenceOverTheStandardLibrary(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(withoutComments{}), gozod.When[time.Duration]().Schema(zod.String()), gozod.WithStandardLibrary())
+	m.Resolve(reflective.TypeFor[time.Duration]())
+	assertTypeScript(t, gozod.SupportingDeclarations(m), z, `/**
+ * Duration corresponds to Go type time.Duration (in package "time").
+ */
+export const Duration = z.string();
+export type Duration = z.infer<typeof Duration>;
+`)
+}
+
+func TestBigIntsFollowTheIntegerPolicy(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(withoutComments{}), gozod.WithStandardLibrary(), gozod.WithIntegerPolicy(gozod.SafeIntegers))
+	assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[big.Int]()).Value, "", `Int`)
+	assert.Contains(t, gozod.SupportingDeclarations(m).String(), `export const Int = z.number().int().safe();`)
+
+	m = gozod.NewMapper(gozod.WithCommentsLoader(withoutComments{}), gozod.WithStandardLibrary(), gozod.When[big.Int]().IntegerPolicy(gozod.IntegersAsDecimalStrings))
+	m.Resolve(reflective.TypeFor[big.Int]())
+	assert.Contains(t, gozod.SupportingDeclarations(m).String(), `n => String(n)`)
+}
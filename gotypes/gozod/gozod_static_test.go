@@ -0,0 +1,76 @@
+package gozod_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/parsing/comments"
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/static"
+	"github.com/softwaretechnik-berlin/goats/gotypes/gozod"
+)
+
+var staticLoader = static.NewLoader()
+
+func TestStaticallyLoadedTypesAreMappedLikeReflectedTypes(t *testing.T) {
+	for _, name := range []goinsp.TypeName{"demoStruct", "omittablesStruct", "newTypeS", "newTypeStruct"} {
+		statically, err := staticLoader.Lookup("github.com/softwaretechnik-berlin/goats/gotypes/gozod_test", name)
+		require.NoError(t, err)
+		reflectively := testTypesByName[name]
+
+		staticMapper := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader))
+		reflectiveMapper := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader))
+		assert.Equal(t, reflectiveMapper.Resolve(reflectively).Value.TypeScript().String(), staticMapper.Resolve(statically).Value.TypeScript().String())
+		assert.Equal(t, gozod.SupportingDeclarations(reflectiveMapper).String(), gozod.SupportingDeclarations(staticMapper).String())
+	}
+}
+
+var testTypesByName = map[goinsp.TypeName]goinsp.Type{
+	"demoStruct":       reflective.TypeFor[demoStruct](),
+	"omittablesStruct": reflective.TypeFor[omittablesStruct](),
+	"newTypeS":         reflective.TypeFor[newTypeS](),
+	"newTypeStruct":    reflective.TypeFor[newTypeStruct](),
+}
+
+func TestConfigurationForReflectedTypesAppliesToStaticallyLoadedTypes(t *testing.T) {
+	statically, err := staticLoader.Lookup("github.com/softwaretechnik-berlin/goats/gotypes/gozod_test", "newTypeS")
+	require.NoError(t, err)
+
+	m := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader), gozod.When[newTypeS]().Named("RenamedS"))
+	assertTypeScriptRepresentationOf(t, m.Resolve(statically).Value, "", `RenamedS`)
+}
+
+// directivesNotLoaded fails to load directives, which statically loaded types provide themselves.
+type directivesNotLoaded struct{ comments.Loader }
+
+func (directivesNotLoaded) LoadDirectives(t goinsp.Type) ([]comments.Directive, error) {
+	return nil, fmt.Errorf("the directives of %v were loaded", t)
+}
+
+func (directivesNotLoaded) LoadMethodDirectives(t goinsp.Type, method string) ([]comments.Directive, error) {
+	return nil, fmt.Errorf("the directives of %v.%s were loaded", t, method)
+}
+
+func TestDirectivesOfStaticallyLoadedTypesAreTakenFromThem(t *testing.T) {
+	for _, name := range []goinsp.TypeName{"instant", "pixels", "tags"} {
+		statically, err := staticLoader.Lookup("github.com/softwaretechnik-berlin/goats/gotypes/gozod_test", name)
+		require.NoError(t, err)
+
+		staticMapper := gozod.NewMapper(gozod.WithCommentsLoader(directivesNotLoaded{sharedCommentsLoader}))
+		staticMapper.Resolve(statically)
+		require.NoError(t, staticMapper.Err())
+		reflectiveMapper := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader))
+		reflectiveMapper.Resolve(directedTypesByName[name])
+		assert.Equal(t, gozod.SupportingDeclarations(reflectiveMapper).String(), gozod.SupportingDeclarations(staticMapper).String())
+	}
+}
+
+var directedTypesByName = map[goinsp.TypeName]goinsp.Type{
+	"instant": reflective.TypeFor[instant](),
+	"pixels":  reflective.TypeFor[pixels](),
+	"tags":    reflective.TypeFor[tags](),
+}
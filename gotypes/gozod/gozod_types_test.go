@@ -0,0 +1,141 @@
+package gozod_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+	"github.com/softwaretechnik-berlin/goats/gotypes/gozod"
+)
+
+type (
+	// typedAccount is declared as an interface.
+	typedAccount struct {
+		// Handle is shown to other users.
+		Handle  typedHandle
+		Roles   []typedRole
+		Friends []*typedAccount `json:",omitempty"`
+		Users   page[pagedUser]
+	}
+	// typedHandle is only branded in schemas.
+	typedHandle string
+	typedRole   string
+	typedEvent  struct {
+		At    time.Time
+		Count int64 `json:",string"`
+		Total int64 `gotypes:",int=bigint"`
+	}
+)
+
+const (
+	typedAdmin typedRole = "admin"
+	typedGuest typedRole = "guest"
+)
+
+func TestTypesAreDeclaredWithoutZod(t *testing.T) {
+	m := gozod.NewTypesMapper(genericsOptions(gozod.WithGenericFactories(), gozod.When[typedRole]().AsEnum())...)
+	assert.NoError(t, m.ResolveAll(reflective.TypeFor[typedAccount]()))
+	source, err := gozod.GenerateSource(m)
+	require.NoError(t, err)
+	assert.Equal(t, `/**
+ * page corresponds to Go type gozod_test.page[T] (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ * The comment on the original Go type follows.
+ *
+ * page is a page of items.
+ */
+export interface page<T> {
+    /**
+     * Items are the items on the page.
+     */
+    items: T[] | null;
+    Next: string | null;
+}
+
+/**
+ * pagedUser corresponds to Go type gozod_test.pagedUser (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export interface pagedUser { Name: string }
+
+/**
+ * typedHandle corresponds to Go type gozod_test.typedHandle (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ * The comment on the original Go type follows.
+ *
+ * typedHandle is only branded in schemas.
+ */
+export type typedHandle = string;
+
+/**
+ * typedRole corresponds to Go type gozod_test.typedRole (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export type typedRole = "admin" | "guest";
+
+/**
+ * typedRoleValues maps the names of the members of typedRole to their values.
+ */
+export const typedRoleValues = {
+    typedAdmin: "admin",
+    typedGuest: "guest",
+} as const;
+
+/**
+ * typedAccount corresponds to Go type gozod_test.typedAccount (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ * The comment on the original Go type follows.
+ *
+ * typedAccount is declared as an interface.
+ */
+export interface typedAccount {
+    /**
+     * Handle is shown to other users.
+     */
+    Handle: typedHandle;
+    Roles: typedRole[] | null;
+    Friends?: (typedAccount | null)[] | null | undefined;
+    Users: page<pagedUser>;
+}
+`, source)
+}
+
+func TestTypesDescribeTheValuesOnTheWire(t *testing.T) {
+	m := gozod.NewTypesMapper(gozod.WithCommentsLoader(withoutComments{}), gozod.WithStandardLibrary(), gozod.WithTimeAsDate())
+	assert.NoError(t, m.ResolveAll(reflective.TypeFor[typedEvent]()))
+	source, err := gozod.GenerateSource(m)
+	require.NoError(t, err)
+	assert.Equal(t, `/**
+ * Time corresponds to Go type time.Time (in package "time").
+ */
+export type Time = string;
+
+/**
+ * typedEvent corresponds to Go type gozod_test.typedEvent (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export interface typedEvent {
+    At: Time;
+    Count: string;
+    Total: number | string | bigint;
+}
+`, source)
+}
+
+func TestExplicitTypesAreCheckedBySchemas(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader), gozod.WithExplicitTypes())
+	assert.NoError(t, m.ResolveAll(reflective.TypeFor[pagedUser](), reflective.TypeFor[typedHandle]()))
+	assertTypeScript(t, gozod.SupportingDeclarations(m), z, `/**
+ * pagedUser corresponds to Go type gozod_test.pagedUser (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export interface pagedUser { Name: string }
+export const pagedUser = z.object({ Name: z.string() }) satisfies z.ZodType<pagedUser>;
+
+/**
+ * typedHandle corresponds to Go type gozod_test.typedHandle (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ * The comment on the original Go type follows.
+ *
+ * typedHandle is only branded in schemas.
+ */
+export type typedHandle = string & z.BRAND<"typedHandle">;
+export type typedHandleInput = string;
+export const typedHandle = z.string().brand("typedHandle") satisfies z.ZodType<typedHandle, z.ZodTypeDef, typedHandleInput>;
+`)
+}
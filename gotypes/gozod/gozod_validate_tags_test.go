@@ -0,0 +1,122 @@
+package gozod_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+	"github.com/softwaretechnik-berlin/goats/gotypes/gozod"
+)
+
+type (
+	signupRole    string
+	signupRequest struct {
+		Name     string            `validate:"required,max=64"`
+		Email    string            `json:",omitempty" validate:"omitempty,email"`
+		Plan     string            `validate:"oneof=free pro 'pro plus'"`
+		Age      int               `validate:"gte=18,lt=150"`
+		Seats    uint8             `validate:"oneof=1 5 10"`
+		Manager  *string           `json:",omitempty" validate:"required,uuid4"`
+		Tags     []string          `validate:"required,max=8,dive,alphanum,max=16"`
+		Invitees [2]*string        `validate:"dive,required,email"`
+		Labels   map[string]string `validate:"dive,startswith=x-"`
+		Role     signupRole        `validate:"required"`
+		Accepted bool              `validate:"required"`
+		Note     string            `validate:"-"`
+	}
+	unsupportedValidation struct {
+		Colour  string            `validate:"iscolor"`
+		Either  string            `validate:"email|url"`
+		Labels  map[string]string `validate:"min=1,dive,keys,alpha,endkeys,required"`
+		Matrix  [][]int           `validate:"dive,dive,min=1"`
+		Ignored string            `validate:"custom"`
+	}
+)
+
+func TestValidateTags(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader), gozod.WithValidateTags())
+	assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[signupRequest]()).Value, "", `signupRequest`)
+	assert.NoError(t, m.Err())
+	assertTypeScript(t, gozod.SupportingDeclarations(m), z, `/**
+ * signupRole corresponds to Go type gozod_test.signupRole (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const signupRole = z.string().brand("signupRole");
+export type signupRole = z.infer<typeof signupRole>;
+
+/**
+ * signupRequest corresponds to Go type gozod_test.signupRequest (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const signupRequest = z.object({
+    Name: z.string().max(64).min(1),
+    Email: z.string().email().or(z.literal("")).optional(),
+    Plan: z.enum([
+        "free",
+        "pro",
+        "pro plus",
+    ]),
+    Age: z.number().int().min(18).lt(150),
+    Seats: z.union([
+        z.literal(1),
+        z.literal(5),
+        z.literal(10),
+    ]),
+    Manager: z.string().regex(/^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$/),
+    Tags: z.array(z.string().regex(/^[a-zA-Z0-9]+$/).max(16)).max(8),
+    Invitees: z.array(z.string().email()).length(2),
+    Labels: z.record(z.string(), z.string().startsWith("x-")).nullable().transform(r => r ?? {}),
+    Role: signupRole.refine(s => s !== ""),
+    Accepted: z.boolean().refine(b => b),
+    Note: z.string(),
+});
+export type signupRequest = z.infer<typeof signupRequest>;
+`)
+}
+
+func TestValidateTagsAreOptIn(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader))
+	assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[struct {
+		Name string `validate:"required,min=1"`
+	}]()).Value, z, `z.object({ Name: z.string() })`)
+}
+
+func TestUnsupportedValidateRulesAreReported(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader), gozod.WithValidateTags("custom"))
+	m.Resolve(reflective.TypeFor[unsupportedValidation]())
+	assert.EqualError(t, m.Err(), `gozod_test.unsupportedValidation.Colour: the rule iscolor in the validate tag "iscolor" isn't supported: strings and arrays can't be validated by it; represent it with the field's gotypes tag or ignore it with WithValidateTags
+gozod_test.unsupportedValidation.Either: the rule email|url in the validate tag "email|url" isn't supported: strings and arrays can't be validated by it; represent it with the field's gotypes tag or ignore it with WithValidateTags
+gozod_test.unsupportedValidation.Labels: the rule keys in the validate tag "min=1,dive,keys,alpha,endkeys,required" isn't supported: map keys can't be validated; represent it with the field's gotypes tag or ignore it with WithValidateTags
+gozod_test.unsupportedValidation.Labels: the rule min=1 in the validate tag "min=1,dive,keys,alpha,endkeys,required" isn't supported: the number of entries of map type map[string]string can't be validated; represent it with the field's gotypes tag or ignore it with WithValidateTags
+gozod_test.unsupportedValidation.Matrix[]: the rule dive in the validate tag "dive,dive,min=1" isn't supported: the elements of type []int can't be validated; represent it with the field's gotypes tag or ignore it with WithValidateTags`)
+}
+
+func TestOneOfRetainsTheOtherRules(t *testing.T) {
+	m := gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader), gozod.WithValidateTags())
+	assertTypeScriptRepresentationOf(t, m.Resolve(reflective.TypeFor[struct {
+		Code    string `validate:"min=2,oneof=a bb"`
+		Letter  string `validate:"oneof=é 😀 ab,max=1"`
+		Contact string `validate:"email,oneof=a@example.com b@example.com"`
+		Level   int    `validate:"required,oneof=0 1 5 10,lt=10"`
+	}]()).Value, z, `z.object({
+    Code: z.enum(["bb"]),
+    Letter: z.enum([
+        "é",
+        "😀",
+    ]),
+    Contact: z.string().email().refine(v => [
+        "a@example.com",
+        "b@example.com",
+    ].includes(v)),
+    Level: z.union([
+        z.literal(1),
+        z.literal(5),
+    ]),
+})`)
+	assert.NoError(t, m.Err())
+
+	m = gozod.NewMapper(gozod.WithCommentsLoader(sharedCommentsLoader), gozod.WithValidateTags())
+	m.Resolve(reflective.TypeFor[struct {
+		Code string `validate:"oneof=a bb,len=3"`
+	}]())
+	assert.ErrorContains(t, m.Err(), `the rule oneof=a bb in the validate tag "oneof=a bb,len=3" isn't supported: none of its values satisfies the other rules`)
+}
@@ -0,0 +1,278 @@
+package gozod_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/effect"
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+	"github.com/softwaretechnik-berlin/goats/gotypes/gozod"
+	"github.com/softwaretechnik-berlin/goats/gotypes/valibot"
+	"github.com/softwaretechnik-berlin/goats/gotypes/zod"
+)
+
+func TestValibotSchemasFollowTheSameRulesAsZod(t *testing.T) {
+	m := gozod.NewValibotMapper(
+		gozod.WithCommentsLoader(sharedCommentsLoader),
+		gozod.When[schemaOrderID]().Template("order-{}"),
+		gozod.WithDiscriminatedUnion(reflective.TypeFor[schemaPayment](), "kind", reflective.TypeFor[schemaCard](), reflective.TypeFor[schemaInvoice]()),
+		gozod.WithDiscriminator(reflective.TypeFor[schemaCard](), "kind", "card"),
+		gozod.WithDiscriminator(reflective.TypeFor[schemaInvoice](), "kind", "invoice"),
+	)
+	require.NoError(t, m.ResolveAll(reflective.TypeFor[schemaOrder]()))
+	source, err := gozod.GenerateSource(m)
+	require.NoError(t, err)
+	assert.Equal(t, `import * as v from "valibot";
+
+/**
+ * schemaCard corresponds to Go type gozod_test.schemaCard (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const schemaCard = v.object({
+    kind: v.literal("card"),
+    Number: v.string(),
+});
+export type schemaCard = v.InferOutput<typeof schemaCard>;
+
+/**
+ * schemaCurrency corresponds to Go type gozod_test.schemaCurrency (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ * The comment on the original Go type follows.
+ *
+ * schemaCurrency is marshalled as its ISO 4217 code.
+ */
+export const schemaCurrency = v.pipe(v.string(), v.brand("schemaCurrency"));
+export type schemaCurrency = v.InferOutput<typeof schemaCurrency>;
+
+/**
+ * schemaInvoice corresponds to Go type gozod_test.schemaInvoice (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const schemaInvoice = v.object({
+    kind: v.literal("invoice"),
+    Days: v.pipe(v.number(), v.minValue(0), v.integer(), v.maxValue(65535)),
+});
+export type schemaInvoice = v.InferOutput<typeof schemaInvoice>;
+
+/**
+ * schemaLine corresponds to Go type gozod_test.schemaLine (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const schemaLine = v.object({
+    Product: v.pipe(v.string(), v.minLength(1)),
+    Count: v.pipe(v.number(), v.minValue(0), v.integer(), v.maxValue(255)),
+});
+export type schemaLine = v.InferOutput<typeof schemaLine>;
+
+/**
+ * schemaOrderID corresponds to Go type gozod_test.schemaOrderID (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const schemaOrderID = v.pipe(v.string(), v.regex(/^order-(\d+)$/), v.transform((s) => {
+    const match = /^order-(\d+)$/.exec(s)!;
+    return v.parse(v.pipe(v.number(), v.minValue(0), v.integer()), Number(match[1]));
+}), v.brand("schemaOrderID"));
+export type schemaOrderID = v.InferOutput<typeof schemaOrderID>;
+
+/**
+ * schemaPayment corresponds to Go type gozod_test.schemaPayment (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const schemaPayment = v.variant("kind", [
+    schemaCard,
+    schemaInvoice,
+]);
+export type schemaPayment = v.InferOutput<typeof schemaPayment>;
+
+/**
+ * schemaOrder corresponds to Go type gozod_test.schemaOrder (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ * The comment on the original Go type follows.
+ *
+ * schemaOrder is placed by a customer.
+ */
+export interface schemaOrder {
+    /**
+     * ID identifies the order.
+     */
+    ID: schemaOrderID;
+    Quantity: number;
+    Lines: schemaLine[];
+    Note?: string | undefined;
+    Currency: schemaCurrency;
+    Payment: schemaPayment;
+    parent?: schemaOrder | null | undefined;
+}
+export const schemaOrder: v.GenericSchema<unknown, schemaOrder> = v.object({
+    /**
+     * ID identifies the order.
+     */
+    ID: schemaOrderID,
+    Quantity: v.pipe(v.string(), v.transform(s => JSON.parse(s)), v.pipe(v.number(), v.integer(), v.minValue(-2147483648), v.maxValue(2147483647))),
+    Lines: v.pipe(v.nullable(v.array(schemaLine)), v.transform(a => a ?? [])),
+    Note: v.optional(v.string()),
+    Currency: schemaCurrency,
+    Payment: schemaPayment,
+    parent: v.optional(v.nullable(v.lazy(() => schemaOrder))),
+});
+`, source)
+}
+
+func TestEffectSchemasFollowTheSameRulesAsZod(t *testing.T) {
+	m := gozod.NewEffectMapper(genericsOptions(gozod.WithGenericFactories(), gozod.When[typedRole]().AsEnum())...)
+	require.NoError(t, m.ResolveAll(reflective.TypeFor[typedAccount]()))
+	source, err := gozod.GenerateSource(m)
+	require.NoError(t, err)
+	assert.Equal(t, `import { ParseResult, Schema } from "effect";
+
+/**
+ * page returns the schema corresponding to Go type gozod_test.page[T] (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test"), given the schemas for its type arguments.
+ * The comment on the original Go type follows.
+ *
+ * page is a page of items.
+ */
+export const page = <T extends Schema.Schema.Any>(t: T) => Schema.Struct({
+    /**
+     * Items are the items on the page.
+     */
+    items: Schema.transformOrFail(Schema.NullOr(Schema.Array(t)), Schema.typeSchema(Schema.Array(t)), {
+        strict: false,
+        decode: (x) => ParseResult.succeed((a => a ?? [])(x)),
+        encode: (x, _, ast) => ParseResult.fail(new ParseResult.Forbidden(ast, x, "encoding isn't supported")),
+    }),
+    Next: Schema.NullOr(Schema.String),
+});
+export type page<T> = Schema.Schema.Type<ReturnType<typeof page<Schema.Schema<T, any>>>>;
+
+/**
+ * pagedUser corresponds to Go type gozod_test.pagedUser (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const pagedUser = Schema.Struct({ Name: Schema.String });
+export type pagedUser = typeof pagedUser.Type;
+
+/**
+ * typedHandle corresponds to Go type gozod_test.typedHandle (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ * The comment on the original Go type follows.
+ *
+ * typedHandle is only branded in schemas.
+ */
+export const typedHandle = Schema.String.pipe(Schema.brand("typedHandle"));
+export type typedHandle = typeof typedHandle.Type;
+
+/**
+ * typedRole corresponds to Go type gozod_test.typedRole (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const typedRole = Schema.Literal("admin", "guest");
+export type typedRole = typeof typedRole.Type;
+
+/**
+ * typedRoleValues maps the names of the members of typedRole to their values.
+ */
+export const typedRoleValues = {
+    typedAdmin: "admin",
+    typedGuest: "guest",
+} as const;
+
+/**
+ * typedAccount corresponds to Go type gozod_test.typedAccount (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ * The comment on the original Go type follows.
+ *
+ * typedAccount is declared as an interface.
+ */
+export interface typedAccount {
+    /**
+     * Handle is shown to other users.
+     */
+    Handle: typedHandle;
+    Roles: typedRole[];
+    Friends?: (typedAccount | null)[] | undefined;
+    Users: page<pagedUser>;
+}
+export const typedAccount = Schema.Struct({
+    /**
+     * Handle is shown to other users.
+     */
+    Handle: typedHandle,
+    Roles: Schema.transformOrFail(Schema.NullOr(Schema.Array(typedRole)), Schema.typeSchema(Schema.Array(typedRole)), {
+        strict: false,
+        decode: (x) => ParseResult.succeed((a => a ?? [])(x)),
+        encode: (x, _, ast) => ParseResult.fail(new ParseResult.Forbidden(ast, x, "encoding isn't supported")),
+    }),
+    Friends: Schema.optional(Schema.transformOrFail(Schema.NullOr(Schema.Array(Schema.NullOr(Schema.suspend((): Schema.Schema<any, any> => typedAccount)))), Schema.typeSchema(Schema.Array(Schema.NullOr(Schema.suspend((): Schema.Schema<any, any> => typedAccount)))), {
+        strict: false,
+        decode: (x) => ParseResult.succeed((a => a ?? [])(x)),
+        encode: (x, _, ast) => ParseResult.fail(new ParseResult.Forbidden(ast, x, "encoding isn't supported")),
+    })),
+    Users: page(pagedUser),
+});
+`, source)
+}
+
+// validatedProfile has the tags whose schemas Valibot and Effect express differently from zod.
+type validatedProfile struct {
+	UserID string          `json:"user_id" gotypes:",name=userId,format=url"`
+	Score  float64         `json:"score,omitempty" gotypes:",default=0,min=0"`
+	Flags  map[string]bool `gotypes:",nonnull"`
+}
+
+func TestValibotAndEffectSchemasOfTags(t *testing.T) {
+	v := gozod.NewValibotMapper(gozod.WithCommentsLoader(withoutComments{}))
+	require.NoError(t, v.ResolveAll(reflective.TypeFor[validatedProfile]()))
+	source, err := gozod.GenerateSource(v)
+	require.NoError(t, err)
+	assert.Equal(t, `import * as v from "valibot";
+
+/**
+ * validatedProfile corresponds to Go type gozod_test.validatedProfile (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const validatedProfile = v.pipe(v.object({
+    user_id: v.pipe(v.string(), v.url()),
+    score: v.optional(v.pipe(v.number(), v.minValue(0)), 0),
+    Flags: v.record(v.string(), v.boolean()),
+}), v.transform(({ user_id: userId, ...rest }) => ({ ...rest, userId })));
+export type validatedProfile = v.InferOutput<typeof validatedProfile>;
+`, source)
+
+	e := gozod.NewEffectMapper(gozod.WithCommentsLoader(withoutComments{}))
+	require.NoError(t, e.ResolveAll(reflective.TypeFor[validatedProfile]()))
+	source, err = gozod.GenerateSource(e)
+	require.NoError(t, err)
+	assert.Equal(t, `import { Schema } from "effect";
+
+/**
+ * validatedProfile corresponds to Go type gozod_test.validatedProfile (in package "github.com/softwaretechnik-berlin/goats/gotypes/gozod_test").
+ */
+export const validatedProfile = Schema.Struct({
+    user_id: Schema.String.pipe(Schema.filter((s) => URL.canParse(s))),
+    score: Schema.optionalWith(Schema.Number.pipe(Schema.greaterThanOrEqualTo(0)), { default: () => 0 }),
+    Flags: Schema.Record({
+        key: Schema.String,
+        value: Schema.Boolean,
+    }),
+}).pipe(Schema.rename({ user_id: "userId" }));
+export type validatedProfile = typeof validatedProfile.Type;
+`, source)
+}
+
+// equivalentTotal has hand-written schemas, whose equivalents in other backends must be stated.
+type equivalentTotal struct {
+	Amount   string `gotypes:",schema=z.string().regex(/^\\d+$/)"`
+	Currency equivalentCurrency
+}
+
+type equivalentCurrency string
+
+func TestHandWrittenSchemasMustStateTheirEquivalents(t *testing.T) {
+	currency := gozod.WithSchema(reflective.TypeFor[equivalentCurrency](), zod.ZodTypeText("z.string().length(3)"))
+
+	m := gozod.NewValibotMapper(currency)
+	assert.EqualError(t, m.ResolveAll(reflective.TypeFor[equivalentTotal]()), `gozod_test.equivalentTotal.Amount: the hand-written schema has no Valibot equivalent, which would accept any value; state it with WithValibotSchema
+gozod_test.equivalentTotal.Currency: the hand-written schema has no Valibot equivalent, which would accept any value; state it with WithValibotSchema`)
+
+	m = gozod.NewValibotMapper(currency, gozod.WithValibotSchema(reflective.TypeFor[equivalentCurrency](), valibot.Text("v.pipe(v.string(), v.length(3))")))
+	require.NoError(t, m.ResolveAll(reflective.TypeFor[equivalentCurrency]()))
+	source, err := gozod.GenerateSource(m)
+	require.NoError(t, err)
+	assert.Contains(t, source, "export const equivalentCurrency = v.pipe(v.string(), v.length(3));")
+
+	e := gozod.NewEffectMapper(currency, gozod.WithValibotSchema(reflective.TypeFor[equivalentCurrency](), valibot.Text("v.pipe(v.string(), v.length(3))")))
+	assert.EqualError(t, e.ResolveAll(reflective.TypeFor[equivalentCurrency]()), "gozod_test.equivalentCurrency: the hand-written schema has no Effect equivalent, which would accept any value; state it with WithEffectSchema")
+
+	e = gozod.NewEffectMapper(currency, gozod.WithEffectSchema(reflective.TypeFor[equivalentCurrency](), effect.Text("Schema.String.pipe(Schema.length(3))")))
+	assert.NoError(t, e.ResolveAll(reflective.TypeFor[equivalentCurrency]()))
+}
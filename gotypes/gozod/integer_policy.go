@@ -0,0 +1,189 @@
+package gozod
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
+)
+
+// IntegerPolicy determines how schemas represent 64-bit integers, i.e. those of kinds int, int64, uint, uint64 and
+// uintptr, whose values beyond Number.MAX_SAFE_INTEGER JavaScript numbers can't represent exactly.
+type IntegerPolicy int
+
+const (
+	// IntegersAsNumbers represents 64-bit integers as numbers, silently losing the precision of those that JavaScript
+	// can't represent exactly. It is the default.
+	IntegersAsNumbers IntegerPolicy = iota
+	// SafeIntegers represents 64-bit integers as numbers, rejecting those that JavaScript can't represent exactly.
+	SafeIntegers
+	// IntegersAsBigInts transforms 64-bit integers into bigints.
+	//
+	// Since JSON.parse turns all JSON numbers into JavaScript numbers, the schemas accept the decimal strings that
+	// encoding/json produces for fields with the `,string` option in their json tag, which they don't parse with
+	// JSON.parse, as well as bigints, e.g. as produced by a custom reviver, besides safe integers.
+	IntegersAsBigInts
+	// IntegersAsDecimalStrings transforms 64-bit integers into decimal strings,
+	// accepting the same values as IntegersAsBigInts.
+	IntegersAsDecimalStrings
+)
+
+// integerPolicyNames are the names of the policies in the `int=` option of gotypes struct tags.
+var integerPolicyNames = map[IntegerPolicy]string{
+	IntegersAsNumbers:        "number",
+	SafeIntegers:             "safe",
+	IntegersAsBigInts:        "bigint",
+	IntegersAsDecimalStrings: "string",
+}
+
+func (p IntegerPolicy) String() string {
+	if name, ok := integerPolicyNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("IntegerPolicy(%d)", int(p))
+}
+
+// WithIntegerPolicy sets the IntegerPolicy for all 64-bit integer types that aren't configured with
+// WithTypeIntegerPolicy or in the tags of the fields using them.
+func WithIntegerPolicy(policy IntegerPolicy) Option {
+	return funcOption(func(c *config) {
+		if c.knownIntegerPolicy(policy) {
+			c.integerPolicy = policy
+		}
+	})
+}
+
+// WithTypeIntegerPolicy sets the IntegerPolicy for the given 64-bit integer type, or for big.Int, see
+// WithStandardLibrary.
+//
+// The policy for individual struct fields of unnamed 64-bit integer types, or pointers to them, can be set in their
+// tags, e.g. `gotypes:",int=bigint"`, where the policies are named `number`, `safe`, `bigint` and `string`.
+func WithTypeIntegerPolicy(t goinsp.GenType, policy IntegerPolicy) Option {
+	return funcOption(func(c *config) {
+		if !c.knownIntegerPolicy(policy) {
+			return
+		}
+		if c.integerPolicies == nil {
+			c.integerPolicies = make(map[typeKey]IntegerPolicy)
+		}
+		c.integerPolicies[keyFor(t)] = policy
+	})
+}
+
+// knownIntegerPolicy tells whether the given policy is one of the declared ones, recording a problem otherwise.
+func (c *config) knownIntegerPolicy(policy IntegerPolicy) bool {
+	if _, ok := integerPolicyNames[policy]; ok {
+		return true
+	}
+	c.problems = append(c.problems, fmt.Errorf("unknown integer policy %v; the known policies are IntegersAsNumbers, SafeIntegers, IntegersAsBigInts and IntegersAsDecimalStrings", policy))
+	return false
+}
+
+// typeIntegerPolicy returns the IntegerPolicy configured for the given type, or the general one.
+func (c config) typeIntegerPolicy(t goinsp.Type) IntegerPolicy {
+	if policy, ok := lookupConfig(c.integerPolicies, t); ok {
+		return policy
+	}
+	return c.integerPolicy
+}
+
+// parseIntegerPolicy returns the IntegerPolicy with the given name from the `int=` option of the given gotypes tag.
+func parseIntegerPolicy(name string, tag string) (IntegerPolicy, error) {
+	for policy, policyName := range integerPolicyNames {
+		if policyName == name {
+			return policy, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown integer policy %q in gotypes tag %q; the known policies are number, safe, bigint and string", name, tag)
+}
+
+var (
+	signedDecimal   = regexp.MustCompile(`^-?\d+$`)
+	unsignedDecimal = regexp.MustCompile(`^\d+$`)
+)
+
+// integerSchema returns the schema of integers of the given kind, bounding those of the sized kinds by their ranges and
+// representing the 64-bit ones according to the given policy.
+func integerSchema(kind reflect.Kind, policy IntegerPolicy) node {
+	signed := numberNode{}.int()
+	unsigned := numberNode{}.nonNegative().int()
+	switch kind {
+	case reflect.Int8:
+		return signed.min(math.MinInt8).max(math.MaxInt8)
+	case reflect.Int16:
+		return signed.min(math.MinInt16).max(math.MaxInt16)
+	case reflect.Int32:
+		return signed.min(math.MinInt32).max(math.MaxInt32)
+	case reflect.Uint8:
+		return unsigned.max(math.MaxUint8)
+	case reflect.Uint16:
+		return unsigned.max(math.MaxUint16)
+	case reflect.Uint32:
+		return unsigned.max(math.MaxUint32)
+	case reflect.Int, reflect.Int64:
+		return largeIntegerSchema(signed, signedDecimal, policy)
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return largeIntegerSchema(unsigned, unsignedDecimal, policy)
+	default:
+		panic(kind)
+	}
+}
+
+func largeIntegerSchema(number numberNode, decimal *regexp.Regexp, policy IntegerPolicy) node {
+	switch policy {
+	case IntegersAsNumbers:
+		return number
+	case SafeIntegers:
+		return number.safe()
+	case IntegersAsBigInts:
+		return largeIntegerNode{number, decimal, false}
+	case IntegersAsDecimalStrings:
+		return largeIntegerNode{number, decimal, true}
+	default:
+		panic(policy)
+	}
+}
+
+func isLargeIntegerKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return true
+	default:
+		return false
+	}
+}
+
+// fieldIntegerSchema returns the schema of a field of the given unnamed 64-bit integer type, or pointer to one,
+// with the IntegerPolicy given in its tag.
+func (b nodeBuilder) fieldIntegerSchema(t goinsp.Type, policy IntegerPolicy) (node, error) {
+	integer := t
+	if t.Kind() == reflect.Pointer {
+		integer = t.Elem()
+	}
+	if _, named := b.name(integer); named || !isLargeIntegerKind(integer.Kind()) {
+		return nil, fmt.Errorf("the integer policy %v of a field can only be applied to unnamed 64-bit integer types and pointers to them, not %v", policy, t)
+	}
+	n := integerSchema(integer.Kind(), policy)
+	if integer != t {
+		return ensureNullable(n), nil
+	}
+	return n, nil
+}
+
+// acceptsDecimalStrings tells whether the schema of the given type, or of the type it points to, accepts the decimal
+// strings encoding/json produces for the `,string` option, which mustn't be parsed with JSON.parse to retain precision.
+func (b nodeBuilder) acceptsDecimalStrings(t goinsp.Type, fieldPolicy IntegerPolicy, hasFieldPolicy bool) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if !isLargeIntegerKind(t.Kind()) {
+		return false
+	}
+	policy := fieldPolicy
+	if !hasFieldPolicy {
+		policy = b.typeIntegerPolicy(t)
+	}
+	return policy == IntegersAsBigInts || policy == IntegersAsDecimalStrings
+}
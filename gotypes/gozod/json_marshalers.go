@@ -0,0 +1,202 @@
+package gozod
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+)
+
+// WithJSONSamples infers the schema of the given type from the JSON its sample values marshal to.
+//
+// It is meant for types implementing json.Marshaler, whose JSON can't be derived from their Go types. The inferred
+// schema accepts the JSON of all the samples, e.g. its objects only require the properties all samples have, so the
+// samples should cover the variety of the type's values. The samples may also be pointers to values of the type, which
+// encoding/json marshals using the methods with pointer receivers.
+func WithJSONSamples(t goinsp.GenType, samples ...any) Option {
+	return funcOption(func(c *config) {
+		if c.jsonSamples == nil {
+			c.jsonSamples = make(map[typeKey][]any)
+		}
+		c.jsonSamples[keyFor(t)] = samples
+	})
+}
+
+// marshalsItself tells whether the values of type t with addressability a implement json.Marshaler without their JSON
+// being described otherwise, in which case the schema derived from their Go type would be wrong.
+//
+// Types that also implement encoding.TextMarshaler are assumed to marshal to the same text in JSON strings.
+func (b nodeBuilder) marshalsItself(t goinsp.Type, a goinsp.Addressability, directives directives) bool {
+	if t.Kind() == reflect.Pointer || t.Kind() == reflect.Interface || !a.Implements(t, reflective.TypeFor[json.Marshaler]()) {
+		return false
+	}
+	if _, ok := b.template(t, directives); ok || a.Implements(t, reflective.TypeFor[encoding.TextMarshaler]()) {
+		return false
+	}
+	if t.Kind() == reflect.Struct {
+		if _, ok := promotedJSONMarshaller(t, a); ok {
+			return false
+		}
+		for i := range t.NumField() {
+			if tag, _ := parseFieldTag(t.Field(i).Tag.Get("gotypes")); tag.value {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// sampledSchema returns the node inferred from the JSON the given samples of type t marshal to.
+func (b nodeBuilder) sampledSchema(t goinsp.Type, samples []any) node {
+	if len(samples) == 0 {
+		return b.invalid(fmt.Errorf("no JSON samples of %v were given", t))
+	}
+	reflected, isReflected := reflective.Reflected(t)
+	var shape jsonShape
+	for _, sample := range samples {
+		if isReflected && reflect.TypeOf(sample) != reflected && reflect.TypeOf(sample) != reflect.PointerTo(reflected) {
+			return b.invalid(fmt.Errorf("the JSON sample %#v of %v has type %T", sample, t, sample))
+		}
+		marshalled, err := json.Marshal(sample)
+		if err != nil {
+			return b.invalid(fmt.Errorf("the JSON sample %#v of %v can't be marshalled: %w", sample, t, err))
+		}
+		decoder := json.NewDecoder(bytes.NewReader(marshalled))
+		decoder.UseNumber()
+		if err := shape.add(decoder); err != nil {
+			return b.invalid(fmt.Errorf("the JSON %s of the sample %#v of %v can't be decoded: %w", marshalled, sample, t, err))
+		}
+	}
+	return shape.schema()
+}
+
+// jsonShape accumulates the kinds of the JSON values marshalled from samples, from which their schema is inferred.
+type jsonShape struct {
+	null, boolean, integer, number, string bool
+	// elements accumulates the elements of the arrays, if there are any.
+	elements *jsonShape
+	// objects is the number of objects, whose properties are accumulated in the order they were first found.
+	objects    int
+	properties []*propertyShape
+}
+
+type propertyShape struct {
+	name string
+	jsonShape
+	// count is the number of objects having the property.
+	count int
+}
+
+// add accumulates the next JSON value from the given decoder, which must use numbers.
+func (s *jsonShape) add(decoder *json.Decoder) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	switch token := token.(type) {
+	case nil:
+		s.null = true
+	case bool:
+		s.boolean = true
+	case json.Number:
+		if strings.ContainsAny(token.String(), ".eE") {
+			s.number = true
+		} else {
+			s.integer = true
+		}
+	case string:
+		s.string = true
+	case json.Delim:
+		if token == '[' {
+			if s.elements == nil {
+				s.elements = &jsonShape{}
+			}
+			for decoder.More() {
+				if err := s.elements.add(decoder); err != nil {
+					return err
+				}
+			}
+		} else {
+			s.objects++
+			for decoder.More() {
+				name, err := decoder.Token()
+				if err != nil {
+					return err
+				}
+				property := s.property(name.(string))
+				property.count++
+				if err := property.add(decoder); err != nil {
+					return err
+				}
+			}
+		}
+		// Consume the closing delimiter.
+		_, err = decoder.Token()
+		return err
+	}
+	return nil
+}
+
+func (s *jsonShape) property(name string) *propertyShape {
+	for _, p := range s.properties {
+		if p.name == name {
+			return p
+		}
+	}
+	p := &propertyShape{name: name}
+	s.properties = append(s.properties, p)
+	return p
+}
+
+// schema returns the node accepting all the accumulated values.
+func (s *jsonShape) schema() node {
+	var alternatives []node
+	if s.boolean {
+		alternatives = append(alternatives, booleanNode{})
+	}
+	if s.number {
+		alternatives = append(alternatives, numberNode{})
+	} else if s.integer {
+		alternatives = append(alternatives, numberNode{}.int())
+	}
+	if s.string {
+		alternatives = append(alternatives, stringNode{})
+	}
+	if s.elements != nil {
+		alternatives = append(alternatives, arrayNode{elem: s.elements.schema()})
+	}
+	if s.objects > 0 {
+		properties := make([]property, len(s.properties))
+		for i, p := range s.properties {
+			n := p.schema()
+			if p.count < s.objects {
+				n = optionalNode{n}
+			}
+			properties[i] = property{name: p.name, node: n}
+		}
+		alternatives = append(alternatives, objectNode{properties})
+	}
+
+	var n node
+	switch len(alternatives) {
+	case 0:
+		if s.null {
+			return nullNode{}
+		}
+		// The elements of arrays that were all empty are unknown.
+		return unknownNode{}
+	case 1:
+		n = alternatives[0]
+	default:
+		n = unionNode{members: alternatives}
+	}
+	if s.null {
+		n = ensureNullable(n)
+	}
+	return n
+}
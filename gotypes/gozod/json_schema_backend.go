@@ -0,0 +1,343 @@
+package gozod
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
+	"github.com/softwaretechnik-berlin/goats/gotypes/jsonschema"
+	"github.com/softwaretechnik-berlin/goats/gotypes/ts"
+	"github.com/softwaretechnik-berlin/goats/gotypes/util"
+)
+
+type goToJSONSchemaMapper = mapper[goinsp.Type, jsonschema.Schema, ts.Identifier, JSONSchemaDeclaration]
+
+// NewJSONSchemaMapper returns a mapper like NewMapper, whose declarations are JSON Schema definitions rather than zod
+// schemas, for the `$defs` of a JSON Schema document, see JSONSchema.
+//
+// JSON Schema has no generics, so generic types aren't declared as factories even if WithGenericFactories or
+// WithGenericFactory are given; their instantiations are declared instead. Hand-written schemas, e.g. those of
+// gotypes:schema directives or WithExternal, are reported by Err unless their JSON Schemas are given with
+// WithJSONSchema, since they would accept any value.
+func NewJSONSchemaMapper(options ...Option) goToJSONSchemaMapper {
+	c := newConfig(options...)
+	c.genericFactories, c.factories = false, nil
+	return newBackendMapper(newNodeBuilder(c), jsonSchemaBackend{c.jsonSchemas})
+}
+
+// WithJSONSchema gives the JSON Schema of the JSON values of the given type, which NewJSONSchemaMapper uses in place of
+// the one derived from the type, e.g. for types whose zod schemas are given with WithSchema.
+func WithJSONSchema(t goinsp.GenType, schema jsonschema.Schema) Option {
+	return funcOption(func(c *config) {
+		if c.jsonSchemas == nil {
+			c.jsonSchemas = make(map[typeKey]jsonschema.Schema)
+		}
+		c.jsonSchemas[keyFor(t)] = schema
+	})
+}
+
+// JSONSchemaDeclaration defines a JSON Schema under the name of the declared type, for the `$defs` of a JSON Schema
+// document. The comment of the declaration becomes the description of the definition.
+type JSONSchemaDeclaration struct {
+	comment string
+	name    ts.Identifier
+	schema  jsonschema.Schema
+}
+
+func (d JSONSchemaDeclaration) Identifier() ts.Identifier { return d.name }
+
+// Recursive returns the declaration unchanged, since definitions can refer to themselves with `$ref`.
+func (d JSONSchemaDeclaration) Recursive() JSONSchemaDeclaration {
+	return d
+}
+
+// JSONSchema returns the definition, which other definitions refer to with jsonschema.Ref.
+func (d JSONSchemaDeclaration) JSONSchema() jsonschema.Schema {
+	schema := d.schema
+	if comment := strings.TrimSpace(d.comment); comment != "" {
+		schema.Description = comment
+	}
+	return schema
+}
+
+// jsonSchemaBackend renders nodes as the JSON Schemas of the JSON values their schemas accept, as far as they can be
+// expressed in JSON Schema. Refinements are left out of them.
+type jsonSchemaBackend struct {
+	schemas map[typeKey]jsonschema.Schema
+}
+
+var _ backend[jsonschema.Schema, JSONSchemaDeclaration] = jsonSchemaBackend{}
+
+func (b jsonSchemaBackend) name() string   { return "JSON Schema" }
+func (b jsonSchemaBackend) option() string { return "WithJSONSchema" }
+
+func (b jsonSchemaBackend) configures(t goinsp.Type) bool {
+	_, ok := lookupConfig(b.schemas, t)
+	return ok
+}
+
+func (b jsonSchemaBackend) handWritten(t goinsp.Type, _ Resolver[goinsp.Type, node]) (node, bool) {
+	schema, ok := lookupConfig(b.schemas, t)
+	if !ok {
+		return nil, false
+	}
+	return resolved[jsonschema.Schema]{schema, jsonStructure(schema), ""}, true
+}
+
+func (b jsonSchemaBackend) zodExpression(string) (node, bool) {
+	return nil, false
+}
+
+// jsonStructure returns the node telling what kind of schema the given hand-written JSON Schema is, as far as it is
+// known, so that it can be refined like the schemas built from Go types.
+func jsonStructure(schema jsonschema.Schema) node {
+	if len(schema.Type) != 1 {
+		return unknownNode{}
+	}
+	switch schema.Type[0] {
+	case jsonschema.BooleanType:
+		return booleanNode{}
+	case jsonschema.StringType:
+		return stringNode{}
+	case jsonschema.NumberType:
+		return numberNode{}
+	case jsonschema.IntegerType:
+		return numberNode{}.int()
+	case jsonschema.ArrayType:
+		return arrayNode{}
+	case jsonschema.ObjectType:
+		return objectNode{util.Map(schema.Properties, func(p jsonschema.Property) property {
+			var n node = resolved[jsonschema.Schema]{p.Schema, jsonStructure(p.Schema), ""}
+			if !slices.Contains(schema.Required, p.Name) {
+				n = optionalNode{n}
+			}
+			return property{name: p.Name, node: n}
+		})}
+	default:
+		return unknownNode{}
+	}
+}
+
+func (b jsonSchemaBackend) render(n node, nodes Resolver[goinsp.Type, node]) jsonschema.Schema {
+	return renderJSONSchema(n)
+}
+
+func (b jsonSchemaBackend) reference(name ts.Identifier, _ jsonschema.Schema) jsonschema.Schema {
+	return jsonschema.Ref(string(name))
+}
+
+func (b jsonSchemaBackend) declare(d declared[jsonschema.Schema]) JSONSchemaDeclaration {
+	return JSONSchemaDeclaration{d.comment, d.name, d.schema}
+}
+
+// renderJSONSchema renders the given node as the JSON Schema of the JSON values its schema accepts.
+func renderJSONSchema(n node) jsonschema.Schema {
+	switch n := n.(type) {
+	case anyNode, unknownNode, typeParameterNode, factoryNode, invocationNode:
+		return jsonschema.Schema{}
+	case booleanNode:
+		return jsonschema.Of(jsonschema.BooleanType)
+	case bigIntNode:
+		return jsonschema.Of(jsonschema.IntegerType)
+	case nullNode:
+		return jsonschema.Of(jsonschema.NullType)
+	case stringNode:
+		schema := jsonschema.Of(jsonschema.StringType)
+		if n.base != nil {
+			schema = renderJSONSchema(n.base)
+		}
+		for _, c := range n.checks {
+			schema = jsonStringCheck(schema, c)
+		}
+		return schema
+	case numberNode:
+		schema := jsonschema.Of(jsonschema.NumberType)
+		if n.base != nil {
+			schema = renderJSONSchema(n.base)
+		}
+		for _, c := range n.checks {
+			schema = jsonNumberCheck(schema, c)
+		}
+		return schema
+	case arrayNode:
+		var schema jsonschema.Schema
+		if n.base != nil {
+			schema = renderJSONSchema(n.base)
+		} else {
+			items := renderJSONSchema(n.elem)
+			schema = jsonschema.Schema{Type: jsonschema.Types{jsonschema.ArrayType}, Items: &items}
+		}
+		for _, c := range n.checks {
+			length := c.length
+			switch c.kind {
+			case arrayMinLength:
+				schema.MinItems = &length
+			case arrayMaxLength:
+				schema.MaxItems = &length
+			case arrayExactLength:
+				schema.MinItems, schema.MaxItems = &length, &length
+			}
+		}
+		return schema
+	case recordNode:
+		return recordJSONSchema(renderJSONSchema(n.key), renderJSONSchema(n.value))
+	case literalNode:
+		return jsonschema.Schema{Const: n.value}
+	case stringEnumNode:
+		return jsonschema.Schema{Type: jsonschema.Types{jsonschema.StringType}, Enum: util.Map(n.values, func(v string) any { return v })}
+	case enumNode:
+		jsonType := jsonschema.IntegerType
+		if _, isString := n.members[0].value.(string); isString {
+			jsonType = jsonschema.StringType
+		}
+		return jsonschema.Schema{Type: jsonschema.Types{jsonType}, Enum: n.values()}
+	case objectNode:
+		return objectJSONSchema(n)
+	case unionNode:
+		members := util.Map(n.members, renderJSONSchema)
+		if n.discriminator != "" {
+			return jsonschema.OneOf(n.discriminator, members...)
+		}
+		return jsonschema.AnyOf(members...)
+	case nullableNode:
+		return jsonschema.Nullable(renderJSONSchema(n.inner))
+	case defaultNode:
+		schema := renderJSONSchema(n.inner)
+		if json.Valid([]byte(n.value.String())) {
+			schema.Default = json.RawMessage(n.value.String())
+		}
+		return schema
+	case describedNode:
+		schema := renderJSONSchema(n.inner)
+		schema.Description = n.description
+		return schema
+	case optionalNode:
+		return renderJSONSchema(n.inner)
+	case readonlyNode:
+		return renderJSONSchema(n.inner)
+	case brandedNode:
+		return renderJSONSchema(n.inner)
+	case hoistableNode:
+		return renderJSONSchema(n.inner)
+	case refinedNode:
+		return renderJSONSchema(n.inner)
+	case transformNode:
+		return renderJSONSchema(n.inner)
+	case orNode:
+		return jsonschema.AnyOf(renderJSONSchema(n.inner), renderJSONSchema(n.other))
+	case quotedNode:
+		// encoding/json quotes the JSON in strings, regardless of whether the strings are parsed.
+		return jsonschema.Of(jsonschema.StringType).WithPattern(n.pattern.String())
+	case templateNode:
+		return jsonschema.Of(jsonschema.StringType).WithPattern(n.pattern.String())
+	case largeIntegerNode:
+		// The JSON Schemas describe the integers encoding/json produces, which are only decimal strings for fields with
+		// the `,string` option, see quotedNode.
+		return renderJSONSchema(n.number)
+	case lazyNode:
+		return jsonschema.Ref(string(n.name))
+	case resolved[jsonschema.Schema]:
+		return n.schema
+	default:
+		panic(unknownNodeError(n))
+	}
+}
+
+func jsonStringCheck(schema jsonschema.Schema, c stringCheck) jsonschema.Schema {
+	switch c.kind {
+	case stringMin:
+		schema.MinLength = &c.length
+	case stringMax:
+		schema.MaxLength = &c.length
+	case stringLength:
+		schema.MinLength, schema.MaxLength = &c.length, &c.length
+	case stringEmail:
+		return schema.WithFormat("email")
+	case stringURL:
+		return schema.WithFormat("uri")
+	case stringUUID:
+		return schema.WithFormat("uuid")
+	case stringDatetime:
+		return schema.WithFormat("date-time")
+	case stringIP:
+		schema.AllOf = append(slices.Clip(schema.AllOf), jsonschema.AnyOf(jsonschema.Schema{Format: "ipv4"}, jsonschema.Schema{Format: "ipv6"}))
+	case stringIPv4:
+		return schema.WithFormat("ipv4")
+	case stringIPv6:
+		return schema.WithFormat("ipv6")
+	case stringIncludes:
+		return schema.WithPattern(regexp.QuoteMeta(c.text))
+	case stringStartsWith:
+		return schema.WithPattern("^" + regexp.QuoteMeta(c.text))
+	case stringEndsWith:
+		return schema.WithPattern(regexp.QuoteMeta(c.text) + "$")
+	case stringRegex:
+		return schema.WithPattern(c.pattern.String())
+	default:
+		panic(c.kind)
+	}
+	return schema
+}
+
+func jsonNumberCheck(schema jsonschema.Schema, c numberCheck) jsonschema.Schema {
+	switch c.kind {
+	case numberInt:
+		schema.Type = jsonschema.Types{jsonschema.IntegerType}
+		return schema
+	case numberNonNegative:
+		return schema.AtLeast(0)
+	case numberMin:
+		return schema.AtLeast(c.bound)
+	case numberMax:
+		return schema.AtMost(c.bound)
+	case numberGt:
+		return schema.Above(c.bound)
+	case numberLt:
+		return schema.Below(c.bound)
+	case numberSafe:
+		return schema.AtLeast(-maxSafeInteger).AtMost(maxSafeInteger)
+	default:
+		panic(c.kind)
+	}
+}
+
+// objectJSONSchema is the JSON Schema of the objects of the given node, in which later properties override earlier ones
+// of the same name. The comments of the properties become their descriptions.
+func objectJSONSchema(n objectNode) jsonschema.Schema {
+	schema := jsonschema.Of(jsonschema.ObjectType)
+	required := make(map[string]bool)
+	for _, p := range n.properties {
+		property := jsonschema.Property{Name: p.name, Schema: renderJSONSchema(p.node)}
+		if property.Schema.Description == "" {
+			property.Schema.Description = strings.TrimSpace(p.comment)
+		}
+		required[p.name] = !acceptsUndefined(p.node)
+		if i := slices.IndexFunc(schema.Properties, func(q jsonschema.Property) bool { return q.Name == p.name }); i >= 0 {
+			schema.Properties[i] = property
+			continue
+		}
+		schema.Properties = append(schema.Properties, property)
+	}
+	for _, p := range schema.Properties {
+		if required[p.Name] {
+			schema.Required = append(schema.Required, p.Name)
+		}
+	}
+	return schema
+}
+
+// recordJSONSchema is the JSON Schema of objects whose property names and values the given schemas accept.
+// Property names are strings, so the key schema only constrains them if it does more than accept strings.
+func recordJSONSchema(key, value jsonschema.Schema) jsonschema.Schema {
+	schema := jsonschema.Schema{Type: jsonschema.Types{jsonschema.ObjectType}, AdditionalProperties: &value}
+	if slices.Equal(key.Type, jsonschema.Types{jsonschema.StringType}) {
+		key.Type = nil
+	}
+	if !reflect.DeepEqual(key, jsonschema.Schema{}) {
+		schema.PropertyNames = &key
+	}
+	return schema
+}
@@ -0,0 +1,33 @@
+package gozod
+
+import (
+	"os"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/jsonschema"
+)
+
+// JSONSchema returns the JSON Schema document defining the declarations of the given mapper in its `$defs`, e.g.
+// `#/$defs/User`, unless the mapper found problems while resolving types, in which case it returns all of them instead.
+func JSONSchema(mapper goToJSONSchemaMapper) (jsonschema.Schema, error) {
+	if err := mapper.Err(); err != nil {
+		return jsonschema.Schema{}, err
+	}
+	defs := make(map[string]jsonschema.Schema, len(mapper.declarations))
+	for name, decl := range mapper.declarations {
+		defs[string(name)] = decl.declaration.Value.JSONSchema()
+	}
+	return jsonschema.Schema{Schema: jsonschema.Dialect, Defs: defs}, nil
+}
+
+// GenerateJSONSchemaFile writes the JSON Schema document of the given mapper to the named file, see JSONSchema.
+func GenerateJSONSchemaFile(mapper goToJSONSchemaMapper, outputFileName string) error {
+	document, err := JSONSchema(mapper)
+	if err != nil {
+		return err
+	}
+	json, err := document.Marshal()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(outputFileName, append(json, '\n'), 0o644)
+}
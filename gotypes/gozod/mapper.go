@@ -1,63 +1,163 @@
 package gozod
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 )
 
 // mapper is a tool for accumulating Declarations created from a B Value with a ID,
 // where we know how to build B values and potentially a ID for each A Value.
-type mapper[A comparable, B any, ID comparable, Declaration withIdentifier[ID]] struct {
+type mapper[A comparable, B any, ID comparable, Declaration declaration[ID, Declaration]] struct {
 	namesByInput map[A]ID
 	declarations map[ID]mappedValue[A, B, ID, Declaration]
-	builder      builder[A, B, Declaration]
+	builder      builder[A, B, ID, Declaration]
+	// inProgress holds the As that are currently being built, so that recursive references to them can be detected.
+	inProgress map[A]*inProgress[ID]
+	// collisions holds the names that were given to more than one A, in the order they were found.
+	collisions *[]nameCollision[A, ID]
 }
 
-type mappedValue[A comparable, B any, Name comparable, Declaration withIdentifier[Name]] struct {
+// nameCollision is the error of different As being given the same name, of which only the first is declared.
+type nameCollision[A any, ID any] struct {
+	name   ID
+	inputs []A
+}
+
+func (c nameCollision[A, ID]) Error() string {
+	inputs := make([]string, len(c.inputs))
+	for i, a := range c.inputs {
+		inputs[i] = fmt.Sprint(a)
+	}
+	return fmt.Sprintf("%s would all be declared as %v; give them distinct names, e.g. with WithNamingStrategy or WithName", strings.Join(inputs, ", "), c.name)
+}
+
+type inProgress[ID comparable] struct {
+	name      ID
+	named     bool
+	recursive bool
+}
+
+type mappedValue[A comparable, B any, Name comparable, Declaration declaration[Name, Declaration]] struct {
 	in          A
 	declaration withAccounting[Declaration, Name]
 	reference   withAccounting[B, Name]
 }
 
-type withIdentifier[I any] interface {
+type declaration[I any, Self any] interface {
 	Identifier() I
+	// Recursive returns the declaration as it should be output if it contains Lazy references to itself.
+	Recursive() Self
 }
 
-func newMapper[A comparable, B any, Identifier comparable, Declaration withIdentifier[Identifier]](builder builder[A, B, Declaration]) mapper[A, B, Identifier, Declaration] {
+func newMapper[A comparable, B any, Identifier comparable, Declaration declaration[Identifier, Declaration]](builder builder[A, B, Identifier, Declaration]) mapper[A, B, Identifier, Declaration] {
 	return mapper[A, B, Identifier, Declaration]{
 		make(map[A]Identifier),
 		make(map[Identifier]mappedValue[A, B, Identifier, Declaration]),
 		builder,
+		make(map[A]*inProgress[Identifier]),
+		new([]nameCollision[A, Identifier]),
 	}
 }
 
 func (m mapper[A, B, ID, Declaration]) Resolve(a A) withAccounting[B, ID] {
+	a = m.builder.Canonical(a)
 	if name, ok := m.namesByInput[a]; ok {
 		decl := m.declarations[name]
 		return decl.reference
 	}
 
+	if building, ok := m.inProgress[a]; ok {
+		if !building.named {
+			return withAccounting[B, ID]{m.builder.Invalid(fmt.Errorf("%v refers to itself, but only types that are declared can be recursive", a)), accountingInfo[ID]{}}
+		}
+		building.recursive = true
+		// The reference is lazy, so it doesn't constrain the order of the declarations.
+		return withAccounting[B, ID]{m.builder.Lazy(building.name), accountingInfo[ID]{lazyDependencies: map[ID]struct{}{building.name: {}}}}
+	}
+	building := &inProgress[ID]{}
+	building.name, building.named = m.builder.Name(a)
+	m.inProgress[a] = building
+	defer delete(m.inProgress, a)
+
 	r := newAccountingResolver[A, B, ID](m)
 	b, declaration, hasDeclaration := m.builder.Build(a, &r)
+	if hasDeclaration != building.named || hasDeclaration && declaration.Identifier() != building.name {
+		panic(fmt.Sprintf("%v was expected to be declared as %v, but wasn't", a, building.name))
+	}
 	if !hasDeclaration {
 		return withAccounting[B, ID]{b, r.Observed}
 	}
 
 	name := declaration.Identifier()
-	if _, ok := m.declarations[name]; ok {
-		panic(fmt.Sprintf("would declare %v as %v, but there is already another declaration with that name", a, name))
+	if existing, ok := m.declarations[name]; ok {
+		m.addCollision(name, existing.in, a)
+		m.namesByInput[a] = name
+		return existing.reference
+	}
+	if building.recursive {
+		declaration = declaration.Recursive()
 	}
 	decl := mappedValue[A, B, ID, Declaration]{
 		a,
 		withAccounting[Declaration, ID]{declaration, r.Observed},
-		withAccounting[B, ID]{b, accountingInfo[ID]{map[ID]struct{}{name: {}}, r.Observed.depth + 1}},
+		withAccounting[B, ID]{b, accountingInfo[ID]{dependencies: map[ID]struct{}{name: {}}, depth: r.Observed.depth + 1}},
 	}
 	m.declarations[name] = decl
 	m.namesByInput[a] = name
 	return decl.reference
 }
 
-func (m mapper[A, B, ID, Declaration]) ResolveAll(inputs ...A) {
+func (m mapper[A, B, ID, Declaration]) addCollision(name ID, existing A, a A) {
+	for i, c := range *m.collisions {
+		if c.name == name {
+			(*m.collisions)[i].inputs = append(c.inputs, a)
+			return
+		}
+	}
+	*m.collisions = append(*m.collisions, nameCollision[A, ID]{name, []A{existing, a}})
+}
+
+// Err reports the problems found while resolving, which prevent the declarations from being output correctly.
+// Resolution carries on despite them, so that all of them are reported.
+func (m mapper[A, B, ID, Declaration]) Err() error {
+	errs := make([]error, len(*m.collisions))
+	for i, c := range *m.collisions {
+		errs[i] = c
+	}
+	return errors.Join(append(errs, m.builder.Err())...)
+}
+
+// ResolveAll resolves all the given As, returning the problems found while resolving them, as Err does.
+func (m mapper[A, B, ID, Declaration]) ResolveAll(inputs ...A) error {
 	for _, a := range inputs {
 		m.Resolve(a)
 	}
+	return m.Err()
+}
+
+// reachable returns the names of the declarations that the given references depend on, directly or indirectly,
+// including lazily.
+func (m mapper[A, B, ID, Declaration]) reachable(references ...withAccounting[B, ID]) map[ID]bool {
+	reached := make(map[ID]bool)
+	var pending []ID
+	reach := func(info accountingInfo[ID]) {
+		for _, names := range []map[ID]struct{}{info.dependencies, info.lazyDependencies} {
+			for name := range names {
+				if !reached[name] {
+					reached[name] = true
+					pending = append(pending, name)
+				}
+			}
+		}
+	}
+	for _, r := range references {
+		reach(r.info)
+	}
+	for len(pending) > 0 {
+		name := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		reach(m.declarations[name].declaration.info)
+	}
+	return reached
 }
@@ -0,0 +1,76 @@
+package gozod
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
+	"github.com/softwaretechnik-berlin/goats/gotypes/ts"
+)
+
+// NamingStrategy determines the names under which named Go types are declared, unless they are named explicitly
+// with WithName.
+type NamingStrategy func(goinsp.Type) ts.Identifier
+
+var _ NamingStrategy = FlattenedTypeNames
+var _ NamingStrategy = PackagePrefixedTypeNames
+
+var (
+	// packageQualifier matches the import path qualifying a type name in the type arguments of a reflected type name,
+	// e.g. `example.com/api.` in `Page[example.com/api.User]`.
+	packageQualifier = regexp.MustCompile(`[\w.~/-]*\.`)
+	nonIdentifierRun = regexp.MustCompile(`[^\w$]+`)
+	// arrayLength matches the length of an array type in the type arguments of a type name, e.g. `[3]` in `Page[[3]int]`.
+	arrayLength = regexp.MustCompile(`\[(\d+)\]`)
+	// typeConstructors spells out the slice and pointer types in the type arguments of a type name, so that e.g.
+	// `Page[[]*User]` and `Page[User]` get different names.
+	typeConstructors = strings.NewReplacer("[]", "ListOf_", "*", "PtrTo_")
+)
+
+// globals are the JavaScript globals and TypeScript types that generated code refers to, which declarations mustn't
+// shadow, e.g. `Number` in `Number.MAX_SAFE_INTEGER`.
+var globals = map[ts.Identifier]bool{
+	"Array":      true,
+	"BigInt":     true,
+	"Date":       true,
+	"JSON":       true,
+	"Number":     true,
+	"Object":     true,
+	"Record":     true,
+	"ReturnType": true,
+	"String":     true,
+}
+
+// FlattenedTypeNames names types like their Go types, flattening the type arguments of instantiated generic types into
+// the name, e.g. `Page_User` for `Page[User]` and `Pair_string_ListOf_PtrTo_User` for `Pair[string, []*User]`.
+// Types whose names would shadow the JavaScript globals that generated code refers to, e.g. `Number`, are named like
+// PackagePrefixedTypeNames names them instead.
+// It is the default NamingStrategy.
+func FlattenedTypeNames(t goinsp.Type) ts.Identifier {
+	if name := flattenedTypeName(t); !globals[name] {
+		return name
+	}
+	return PackagePrefixedTypeNames(t)
+}
+
+// flattenedTypeName is the name FlattenedTypeNames gives t, regardless of whether it shadows a global.
+func flattenedTypeName(t goinsp.Type) ts.Identifier {
+	name := packageQualifier.ReplaceAllString(t.Name().String(), "")
+	name = typeConstructors.Replace(arrayLength.ReplaceAllString(name, "ArrayOf${1}_"))
+	return ts.Identifier(strings.TrimSuffix(nonIdentifierRun.ReplaceAllString(name, "_"), "_"))
+}
+
+// PackagePrefixedTypeNames is like FlattenedTypeNames, but prefixes the names with the last segment of the import path
+// of the package declaring the type that isn't a major version suffix, e.g. `billingStatus` for the `Status` declared
+// in `example.com/billing/v2`. This disambiguates types with the same name in different packages.
+func PackagePrefixedTypeNames(t goinsp.Type) ts.Identifier {
+	prefix := nonIdentifierRun.ReplaceAllString(t.PkgPath().LastNonVersionSegment(), "_")
+	return ts.Identifier(prefix) + flattenedTypeName(t)
+}
+
+// WithNamingStrategy sets the NamingStrategy.
+func WithNamingStrategy(strategy NamingStrategy) Option {
+	return funcOption(func(c *config) {
+		c.namingStrategy = strategy
+	})
+}
@@ -0,0 +1,97 @@
+package gozod
+
+import (
+	"fmt"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
+)
+
+// NilPolicy determines how schemas represent the JSON null that encoding/json produces for nil slices and maps.
+type NilPolicy int
+
+const (
+	// NilAsEmpty accepts null and transforms it into an empty array, string or object,
+	// just like Go code can usually treat nil slices and maps like empty ones. It is the default.
+	NilAsEmpty NilPolicy = iota
+	// NilAsNull accepts null and keeps it distinct from empty values.
+	NilAsNull
+	// NilRejected rejects null, asserting that the slices and maps are never nil.
+	// This keeps the input types of the schemas free of null.
+	NilRejected
+)
+
+// nilPolicyNames are the names of the policies in the `nil=` option of gotypes struct tags.
+var nilPolicyNames = map[NilPolicy]string{
+	NilAsEmpty:  "empty",
+	NilAsNull:   "null",
+	NilRejected: "reject",
+}
+
+func (p NilPolicy) String() string {
+	if name, ok := nilPolicyNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("NilPolicy(%d)", int(p))
+}
+
+// WithNilPolicy sets the NilPolicy for all slice and map types that aren't configured with WithTypeNilPolicy or in the
+// tags of the fields using them.
+func WithNilPolicy(policy NilPolicy) Option {
+	return funcOption(func(c *config) {
+		if c.knownNilPolicy(policy) {
+			c.nilPolicy = policy
+		}
+	})
+}
+
+// WithNullableSlices keeps the null of nil slices and maps distinct from empty values, see NilAsNull.
+func WithNullableSlices() Option {
+	return WithNilPolicy(NilAsNull)
+}
+
+// WithNonNullSlices rejects null for slices and maps, asserting that they are never nil, see NilRejected.
+func WithNonNullSlices() Option {
+	return WithNilPolicy(NilRejected)
+}
+
+// WithTypeNilPolicy sets the NilPolicy for the given slice or map type.
+//
+// The policy for individual struct fields of unnamed slice and map types can be set in their tags,
+// e.g. `gotypes:",nil=reject"`, where the policies are named `empty`, `null` and `reject`.
+func WithTypeNilPolicy(t goinsp.GenType, policy NilPolicy) Option {
+	return funcOption(func(c *config) {
+		if !c.knownNilPolicy(policy) {
+			return
+		}
+		if c.nilPolicies == nil {
+			c.nilPolicies = make(map[typeKey]NilPolicy)
+		}
+		c.nilPolicies[keyFor(t)] = policy
+	})
+}
+
+// knownNilPolicy tells whether the given policy is one of the declared ones, recording a problem otherwise.
+func (c *config) knownNilPolicy(policy NilPolicy) bool {
+	if _, ok := nilPolicyNames[policy]; ok {
+		return true
+	}
+	c.problems = append(c.problems, fmt.Errorf("unknown nil policy %v; the known policies are NilAsEmpty, NilAsNull and NilRejected", policy))
+	return false
+}
+
+func (b nodeBuilder) nilPolicy(t goinsp.Type) NilPolicy {
+	if policy, ok := lookupConfig(b.nilPolicies, t); ok {
+		return policy
+	}
+	return b.config.nilPolicy
+}
+
+// parseNilPolicy returns the NilPolicy with the given name from the `nil=` option of the given gotypes tag.
+func parseNilPolicy(name string, tag string) (NilPolicy, error) {
+	for policy, policyName := range nilPolicyNames {
+		if policyName == name {
+			return policy, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown nil policy %q in gotypes tag %q; the known policies are empty, null and reject", name, tag)
+}
@@ -0,0 +1,586 @@
+package gozod
+
+import (
+	"regexp"
+	"slices"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
+	"github.com/softwaretechnik-berlin/goats/gotypes/ts"
+)
+
+// node is the schema of a Go type as the nodeBuilder builds it, before a backend renders it in the library its
+// declarations use, e.g. as a zod.ZodType or a valibot.Schema.
+//
+// Nodes tell what the schemas accept and produce rather than how a library expresses it, so that the options, tags and
+// directives apply to all backends alike. Like the schemas of the libraries, they are values.
+type node interface {
+	isNode()
+}
+
+type (
+	anyNode     struct{}
+	unknownNode struct{}
+	booleanNode struct{}
+	bigIntNode  struct{}
+	nullNode    struct{}
+)
+
+// stringNode is a string schema with the given checks, which refines base unless it is nil, e.g. a reference to a
+// declared string schema.
+type stringNode struct {
+	base   node
+	checks []stringCheck
+}
+
+type stringCheckKind uint8
+
+const (
+	stringMin stringCheckKind = iota
+	stringMax
+	stringLength
+	stringEmail
+	stringURL
+	stringUUID
+	stringDatetime
+	stringIP
+	stringIPv4
+	stringIPv6
+	stringIncludes
+	stringStartsWith
+	stringEndsWith
+	stringRegex
+)
+
+// stringCheck is a check of a stringNode, with the length, text or pattern its kind takes.
+type stringCheck struct {
+	kind    stringCheckKind
+	length  int
+	text    string
+	pattern *regexp.Regexp
+}
+
+func (s stringNode) check(c stringCheck) stringNode {
+	s.checks = append(slices.Clip(s.checks), c)
+	return s
+}
+
+func (s stringNode) min(length int) stringNode {
+	return s.check(stringCheck{kind: stringMin, length: length})
+}
+func (s stringNode) max(length int) stringNode {
+	return s.check(stringCheck{kind: stringMax, length: length})
+}
+func (s stringNode) length(length int) stringNode {
+	return s.check(stringCheck{kind: stringLength, length: length})
+}
+func (s stringNode) email() stringNode { return s.check(stringCheck{kind: stringEmail}) }
+func (s stringNode) url() stringNode   { return s.check(stringCheck{kind: stringURL}) }
+func (s stringNode) uuid() stringNode  { return s.check(stringCheck{kind: stringUUID}) }
+
+// datetime requires an ISO 8601 date and time with a time zone offset, e.g. `2006-01-02T15:04:05+07:00`.
+func (s stringNode) datetime() stringNode { return s.check(stringCheck{kind: stringDatetime}) }
+func (s stringNode) ip() stringNode       { return s.check(stringCheck{kind: stringIP}) }
+func (s stringNode) ipv4() stringNode     { return s.check(stringCheck{kind: stringIPv4}) }
+func (s stringNode) ipv6() stringNode     { return s.check(stringCheck{kind: stringIPv6}) }
+func (s stringNode) includes(text string) stringNode {
+	return s.check(stringCheck{kind: stringIncludes, text: text})
+}
+func (s stringNode) startsWith(text string) stringNode {
+	return s.check(stringCheck{kind: stringStartsWith, text: text})
+}
+func (s stringNode) endsWith(text string) stringNode {
+	return s.check(stringCheck{kind: stringEndsWith, text: text})
+}
+func (s stringNode) regex(pattern *regexp.Regexp) stringNode {
+	return s.check(stringCheck{kind: stringRegex, pattern: pattern})
+}
+
+// numberNode is a number schema with the given checks, which refines base unless it is nil.
+type numberNode struct {
+	base   node
+	checks []numberCheck
+}
+
+type numberCheckKind uint8
+
+const (
+	numberInt numberCheckKind = iota
+	numberNonNegative
+	numberMin
+	numberMax
+	numberGt
+	numberLt
+	// numberSafe restricts the numbers to those from Number.MIN_SAFE_INTEGER to Number.MAX_SAFE_INTEGER, which
+	// JavaScript represents exactly.
+	numberSafe
+)
+
+// numberCheck is a check of a numberNode, with the bound its kind takes.
+type numberCheck struct {
+	kind  numberCheckKind
+	bound float64
+}
+
+// maxSafeInteger is Number.MAX_SAFE_INTEGER.
+const maxSafeInteger = 1<<53 - 1
+
+func (n numberNode) check(c numberCheck) numberNode {
+	n.checks = append(slices.Clip(n.checks), c)
+	return n
+}
+
+func (n numberNode) int() numberNode              { return n.check(numberCheck{kind: numberInt}) }
+func (n numberNode) nonNegative() numberNode      { return n.check(numberCheck{kind: numberNonNegative}) }
+func (n numberNode) min(bound float64) numberNode { return n.check(numberCheck{numberMin, bound}) }
+func (n numberNode) max(bound float64) numberNode { return n.check(numberCheck{numberMax, bound}) }
+func (n numberNode) gt(bound float64) numberNode  { return n.check(numberCheck{numberGt, bound}) }
+func (n numberNode) lt(bound float64) numberNode  { return n.check(numberCheck{numberLt, bound}) }
+func (n numberNode) safe() numberNode             { return n.check(numberCheck{kind: numberSafe}) }
+
+// isInt tells whether the numbers are integers.
+func (n numberNode) isInt() bool {
+	if base, ok := structureOf(n.base).(numberNode); ok && base.isInt() {
+		return true
+	}
+	return slices.ContainsFunc(n.checks, func(c numberCheck) bool { return c.kind == numberInt })
+}
+
+// isNonNegative tells whether the numbers are known not to be negative.
+func (n numberNode) isNonNegative() bool {
+	if base, ok := structureOf(n.base).(numberNode); ok && base.isNonNegative() {
+		return true
+	}
+	return slices.ContainsFunc(n.checks, func(c numberCheck) bool {
+		return c.kind == numberNonNegative || (c.kind == numberMin || c.kind == numberGt) && c.bound >= 0
+	})
+}
+
+// arrayNode is an array schema of elements of the schema elem, or one refining base unless it is nil, with the given
+// checks of its length.
+type arrayNode struct {
+	elem   node
+	base   node
+	checks []arrayCheck
+}
+
+type arrayCheckKind uint8
+
+const (
+	arrayMinLength arrayCheckKind = iota
+	arrayMaxLength
+	arrayExactLength
+)
+
+type arrayCheck struct {
+	kind   arrayCheckKind
+	length uint
+}
+
+func (a arrayNode) check(c arrayCheck) arrayNode {
+	a.checks = append(slices.Clip(a.checks), c)
+	return a
+}
+
+func (a arrayNode) min(length uint) arrayNode { return a.check(arrayCheck{arrayMinLength, length}) }
+func (a arrayNode) max(length uint) arrayNode { return a.check(arrayCheck{arrayMaxLength, length}) }
+func (a arrayNode) length(length uint) arrayNode {
+	return a.check(arrayCheck{arrayExactLength, length})
+}
+
+type recordNode struct{ key, value node }
+
+// literalNode accepts only the given value, which is a string or a float64.
+type literalNode struct{ value any }
+
+// stringEnumNode accepts only the given strings.
+type stringEnumNode struct{ values []string }
+
+// enumNode is an enum of the given members, see enumSchema.
+type enumNode struct{ members []enumMember }
+
+// enumMember is a permissible value of an enum.
+type enumMember struct {
+	// name is the key of the member in the value map declared along with the enum.
+	name string
+	// value is a string, int64 or uint64.
+	value any
+	// comment is rendered as the doc comment of the member in the value map.
+	comment string
+	// label is the key of the member's value in the label map declared along with the enum, unless it is empty.
+	label string
+}
+
+// values returns the distinct values of the enum's members, in order.
+func (e enumNode) values() []any {
+	var values []any
+	for _, member := range e.members {
+		if !slices.Contains(values, member.value) {
+			values = append(values, member.value)
+		}
+	}
+	return values
+}
+
+type objectNode struct{ properties []property }
+
+// property is a property of an objectNode. Later properties override earlier ones of the same name.
+type property struct {
+	name string
+	node node
+	// comment is rendered as a doc comment on the property.
+	comment string
+	// outputName renames the property in the schema's output, unless it is empty.
+	outputName ts.Identifier
+	// fieldName is the name of the Go field the property corresponds to, from which the attribute name of the
+	// equivalent Pydantic field is derived, unless it is empty.
+	fieldName string
+}
+
+// unionNode accepts the values any of its members accepts, telling apart objects by the given discriminator property
+// unless it is empty.
+type unionNode struct {
+	members       []node
+	discriminator string
+}
+
+type (
+	nullableNode  struct{ inner node }
+	optionalNode  struct{ inner node }
+	readonlyNode  struct{ inner node }
+	describedNode struct {
+		inner       node
+		description string
+	}
+	brandedNode struct {
+		inner node
+		brand string
+	}
+	// defaultNode replaces undefined with the given value, a JSON literal or a string.
+	defaultNode struct {
+		inner node
+		value ts.Source
+	}
+	// hoistableNode may be declared as a constant of its own where it occurs repeatedly, see WithSharedSubSchemas.
+	hoistableNode struct{ inner node }
+	// orNode accepts the values either inner or other accepts.
+	orNode struct{ inner, other node }
+)
+
+// refinedNode rejects the values of inner for which its check returns false.
+type refinedNode struct {
+	inner node
+	check refinement
+}
+
+// refinement is a check of the values of a schema as a TypeScript and a Python function.
+type refinement struct {
+	typeScript ts.Source
+	python     string
+}
+
+// transformNode transforms the values of inner with a TypeScript function, which may refer to the schemas of the Go
+// types it is given the resolver for.
+//
+// The output of the function has the type outputType, or that of the output of outputOf, unless both are nil, in
+// which case it is unknown. Pydantic validates outputOf, after applying the Python function before to the values if
+// it isn't empty.
+type transformNode struct {
+	inner      node
+	fn         func(resolver Resolver[goinsp.Type, ts.Source]) ts.Source
+	outputType *ts.TypeExpression
+	outputOf   node
+	before     string
+}
+
+// transform transforms the values of inner with the given function, whose output is unknown.
+func transform(inner node, fn ts.Source) transformNode {
+	return transformNode{inner: inner, fn: func(Resolver[goinsp.Type, ts.Source]) ts.Source { return fn }}
+}
+
+// transformTo transforms the values of inner with the given function, whose output has the given type.
+func transformTo(inner node, outputType ts.TypeExpression, fn ts.Source) transformNode {
+	n := transform(inner, fn)
+	n.outputType = &outputType
+	return n
+}
+
+// transformToOutputOf transforms the values of inner with the given function, whose output the given node validates,
+// and which Pydantic replaces by the given Python function applied before validating the output.
+func transformToOutputOf(inner node, outputOf node, fn ts.Source, before string) transformNode {
+	n := transform(inner, fn)
+	n.outputOf, n.before = outputOf, before
+	return n
+}
+
+// quotedNode accepts the strings matching pattern in which encoding/json quotes the JSON of the values of inner, for
+// fields with the `,string` option, which are parsed with JSON.parse if parse is set.
+type quotedNode struct {
+	inner   node
+	parse   bool
+	pattern *regexp.Regexp
+}
+
+// templateNode parses strings of the given template, which match pattern, into values of inner, see
+// applyTemplateTransform.
+type templateNode struct {
+	inner    node
+	template string
+	pattern  *regexp.Regexp
+	// match returns the TypeScript expression transforming the `match` of pattern into a value of inner, parsing the
+	// placeholders with the given parser.
+	match func(parse parser) ts.Source
+	// names are the names of the properties of inner matched by the groups of pattern in order, if inner is an object.
+	names []string
+}
+
+// largeIntegerNode is an integer beyond Number.MAX_SAFE_INTEGER, see IntegersAsBigInts and IntegersAsDecimalStrings.
+type largeIntegerNode struct {
+	number   numberNode
+	decimal  *regexp.Regexp
+	asString bool
+}
+
+// transformed returns the transformation of the numbers, decimal strings and bigints the node accepts into bigints, or
+// into decimal strings if asString is set.
+func (n largeIntegerNode) transformed() transformNode {
+	accepted := unionNode{members: []node{n.number.safe(), stringNode{}.regex(n.decimal), bigIntNode{}}}
+	if n.asString {
+		return transformTo(accepted, ts.StringType, ts.AsSource(`n => String(n)`))
+	}
+	return transformTo(accepted, ts.BigIntType, ts.AsSource(`n => BigInt(n)`))
+}
+
+type (
+	// typeParameterNode is the schema passed to a factory for its type parameter of the given name.
+	typeParameterNode struct{ name ts.Identifier }
+	// factoryNode refers to the factory declared under the given name, which isn't a schema itself.
+	factoryNode struct{ name ts.Identifier }
+	// lazyNode refers to the schema or factory declared under the given name, which is still being built.
+	lazyNode struct{ name ts.Identifier }
+	// invocationNode invokes the given factory with the schemas for its type parameters.
+	invocationNode struct {
+		factory   node
+		arguments []node
+	}
+)
+
+// referredFactory returns the factoryNode or lazyNode the given factory of an invocationNode refers to, looking through
+// the reference to its declaration, or else the factory itself.
+func referredFactory(factory node) node {
+	if r, ok := factory.(reference); ok {
+		target, _ := r.referent()
+		return target
+	}
+	return factory
+}
+
+// resolved is a schema the backend has already rendered, along with the node it renders, e.g. the reference to a
+// declared schema. Hand-written schemas render unknownNodes, unless their structure is known.
+type resolved[S any] struct {
+	schema S
+	target node
+	// name is the name the schema is declared under, unless it is empty.
+	name ts.Identifier
+}
+
+// reference is a resolved schema regardless of its backend.
+type reference interface {
+	node
+	// referent returns the node the schema renders and the name it is declared under, if any.
+	referent() (target node, name ts.Identifier)
+	// retargeted returns the same schema as rendering the given node.
+	retargeted(target node) node
+}
+
+func (r resolved[S]) referent() (node, ts.Identifier) { return r.target, r.name }
+
+func (r resolved[S]) retargeted(target node) node {
+	r.target = target
+	return r
+}
+
+func (anyNode) isNode()           {}
+func (unknownNode) isNode()       {}
+func (booleanNode) isNode()       {}
+func (bigIntNode) isNode()        {}
+func (nullNode) isNode()          {}
+func (stringNode) isNode()        {}
+func (numberNode) isNode()        {}
+func (arrayNode) isNode()         {}
+func (recordNode) isNode()        {}
+func (literalNode) isNode()       {}
+func (stringEnumNode) isNode()    {}
+func (enumNode) isNode()          {}
+func (objectNode) isNode()        {}
+func (unionNode) isNode()         {}
+func (nullableNode) isNode()      {}
+func (optionalNode) isNode()      {}
+func (readonlyNode) isNode()      {}
+func (describedNode) isNode()     {}
+func (brandedNode) isNode()       {}
+func (defaultNode) isNode()       {}
+func (hoistableNode) isNode()     {}
+func (orNode) isNode()            {}
+func (refinedNode) isNode()       {}
+func (transformNode) isNode()     {}
+func (quotedNode) isNode()        {}
+func (templateNode) isNode()      {}
+func (largeIntegerNode) isNode()  {}
+func (typeParameterNode) isNode() {}
+func (factoryNode) isNode()       {}
+func (lazyNode) isNode()          {}
+func (invocationNode) isNode()    {}
+func (resolved[S]) isNode()       {}
+
+// structureOf returns the node telling what kind of schema n is, looking through hoisting and references.
+//
+// References to declared schemas only reveal that they are strings, numbers, arrays, records, objects or brands, which can be
+// refined or taken apart while still referring to the declaration; otherwise, the reference itself is returned.
+func structureOf(n node) node {
+	switch n := n.(type) {
+	case hoistableNode:
+		return structureOf(n.inner)
+	case reference:
+		target, name := n.referent()
+		structure := structureOf(target)
+		if name == "" {
+			return structure
+		}
+		switch structure := structure.(type) {
+		case stringNode, numberNode, arrayNode, recordNode, objectNode:
+			return structure
+		case brandedNode:
+			// The branded schema is still referred to by the name it is declared under.
+			return brandedNode{n.retargeted(structure.inner), structure.brand}
+		}
+	}
+	return n
+}
+
+// asString returns n as a stringNode that can be refined, if it is a string schema.
+func asString(n node) (stringNode, bool) {
+	if s, ok := n.(stringNode); ok {
+		return s, true
+	}
+	_, ok := structureOf(n).(stringNode)
+	return stringNode{base: n}, ok
+}
+
+// asNumber returns n as a numberNode that can be refined, if it is a number schema.
+func asNumber(n node) (numberNode, bool) {
+	if number, ok := n.(numberNode); ok {
+		return number, true
+	}
+	_, ok := structureOf(n).(numberNode)
+	return numberNode{base: n}, ok
+}
+
+// asArray returns n as an arrayNode that can be refined, if it is an array schema.
+func asArray(n node) (arrayNode, bool) {
+	if a, ok := n.(arrayNode); ok {
+		return a, true
+	}
+	_, ok := structureOf(n).(arrayNode)
+	return arrayNode{base: n}, ok
+}
+
+// stripNullable strips away the nullable wrappers of n and tells whether there were any.
+func stripNullable(n node) (node, bool) {
+	if nullable, ok := structureOf(n).(nullableNode); ok {
+		inner, _ := stripNullable(nullable.inner)
+		return inner, true
+	}
+	return n, false
+}
+
+// ensureNullable makes n accept null, unless it is nullable already.
+func ensureNullable(n node) node {
+	if _, ok := structureOf(n).(nullableNode); ok {
+		return n
+	}
+	return nullableNode{n}
+}
+
+// isOptional tells whether n is known to accept undefined in objects already. Like the wrappers of zod, nullable and
+// branded schemas are taken for optional ones, so that they are left as they are.
+func isOptional(n node) bool {
+	switch structureOf(n).(type) {
+	case optionalNode, nullableNode, brandedNode:
+		return true
+	default:
+		return false
+	}
+}
+
+// unwrapped returns the structure of n without its nullable, optional and branded wrappers.
+func unwrapped(n node) node {
+	for {
+		switch structure := structureOf(n).(type) {
+		case nullableNode:
+			n = structure.inner
+		case optionalNode:
+			n = structure.inner
+		case brandedNode:
+			n = structure.inner
+		default:
+			return structure
+		}
+	}
+}
+
+// acceptsUndefined tells whether n accepts undefined, so that the properties of its schema may be left out.
+func acceptsUndefined(n node) bool {
+	switch n := n.(type) {
+	case optionalNode, defaultNode, anyNode, unknownNode, factoryNode, invocationNode:
+		return true
+	case reference:
+		target, _ := n.referent()
+		return acceptsUndefined(target)
+	case unionNode:
+		return slices.ContainsFunc(n.members, acceptsUndefined)
+	case orNode:
+		return acceptsUndefined(n.inner) || acceptsUndefined(n.other)
+	case quotedNode:
+		return !n.parse && acceptsUndefined(n.inner)
+	case nullableNode:
+		return acceptsUndefined(n.inner)
+	case describedNode:
+		return acceptsUndefined(n.inner)
+	case brandedNode:
+		return acceptsUndefined(n.inner)
+	case readonlyNode:
+		return acceptsUndefined(n.inner)
+	case hoistableNode:
+		return acceptsUndefined(n.inner)
+	case refinedNode:
+		return acceptsUndefined(n.inner)
+	case transformNode:
+		return acceptsUndefined(n.inner)
+	default:
+		return false
+	}
+}
+
+// describe returns a description of the values n accepts for problems, e.g. "values of object schemas".
+func describe(n node) string {
+	switch unwrapped(n).(type) {
+	case booleanNode:
+		return "values of boolean schemas"
+	case bigIntNode, largeIntegerNode:
+		return "values of bigint schemas"
+	case arrayNode:
+		return "values of array schemas"
+	case recordNode:
+		return "values of record schemas"
+	case objectNode:
+		return "values of object schemas"
+	case enumNode, stringEnumNode, literalNode:
+		return "values of enum schemas"
+	case unionNode, orNode:
+		return "values of union schemas"
+	case transformNode, templateNode, quotedNode:
+		return "values of transforming schemas"
+	default:
+		return "values of schemas of unknown structure"
+	}
+}
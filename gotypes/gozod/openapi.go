@@ -0,0 +1,195 @@
+package gozod
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
+	"github.com/softwaretechnik-berlin/goats/gotypes/jsonschema"
+	"github.com/softwaretechnik-berlin/goats/gotypes/openapi"
+	"github.com/softwaretechnik-berlin/goats/gotypes/ts"
+)
+
+// Operation declares an operation of an HTTP API for an OpenAPI document, see OpenAPI.
+type Operation struct {
+	// Method is the HTTP method of the operation, e.g. http.MethodGet.
+	Method string
+	// Path is the path template of the operation relative to the URL of the API, e.g. `/orders/{id}`.
+	Path        string
+	ID          string
+	Summary     string
+	Description string
+	Tags        []string
+	// PathParameters is the struct type whose JSON properties are the parameters of the Path template, if it has any.
+	PathParameters goinsp.Type
+	// QueryParameters is the struct type whose JSON properties are the query parameters of the operation, if it has
+	// any. They are required unless their properties are optional, e.g. because of `omitempty`.
+	QueryParameters goinsp.Type
+	// Request is the type of the JSON body of the requests, if they have one.
+	Request   goinsp.Type
+	Responses []Response
+}
+
+// Response declares a response of an Operation.
+type Response struct {
+	Status int
+	// Description describes the response, which is the text of its Status by default, e.g. `Not Found`.
+	Description string
+	// Type is the type of the JSON body of the response, if it has one.
+	Type goinsp.Type
+}
+
+// pathParameter matches the parameters of path templates, e.g. `{id}`.
+var pathParameter = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// OpenAPI returns the OpenAPI document of the given operations, after resolving the types of their parameters, requests
+// and responses with the given mapper. The schemas of its components are the declarations of the mapper, except for
+// those of the parameters that nothing else refers to.
+//
+// It returns the problems the mapper found while resolving types, as well as those of the operations, instead if there
+// are any.
+func OpenAPI(mapper goToJSONSchemaMapper, info openapi.Info, operations ...Operation) (openapi.Document, error) {
+	document := openapi.Document{OpenAPI: openapi.Version, Info: info, Paths: make(map[string]openapi.PathItem)}
+	var problems []error
+	parameterTypes := make(map[ts.Identifier]bool)
+	// references are those of the operations, which refer to the components along with the other components.
+	var references []withAccounting[jsonschema.Schema, ts.Identifier]
+	for _, o := range operations {
+		operation, operationProblems := o.resolve(mapper, parameterTypes, &references)
+		item := document.Paths[o.Path]
+		operationProblems = append(operationProblems, item.Add(o.Method, operation))
+		document.Paths[o.Path] = item
+		for _, problem := range operationProblems {
+			if problem != nil {
+				problems = append(problems, fmt.Errorf("%s %s: %w", o.Method, o.Path, problem))
+			}
+		}
+	}
+	if err := errors.Join(append([]error{mapper.Err()}, problems...)...); err != nil {
+		return openapi.Document{}, err
+	}
+
+	// The types of parameters are only components if the operations or other components refer to them.
+	for name, decl := range mapper.declarations {
+		if !parameterTypes[name] {
+			references = append(references, decl.reference)
+		}
+	}
+	document.Components.Schemas = make(map[string]jsonschema.Schema)
+	for name := range mapper.reachable(references...) {
+		document.Components.Schemas[string(name)] = mapper.declarations[name].declaration.Value.JSONSchema().Relocated(openapi.SchemasPrefix)
+	}
+	return document, nil
+}
+
+// GenerateOpenAPIFile writes the OpenAPI document of the given operations to the named file, see OpenAPI.
+func GenerateOpenAPIFile(mapper goToJSONSchemaMapper, outputFileName string, info openapi.Info, operations ...Operation) error {
+	document, err := OpenAPI(mapper, info, operations...)
+	if err != nil {
+		return err
+	}
+	json, err := document.Marshal()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(outputFileName, append(json, '\n'), 0o644)
+}
+
+// resolve returns the OpenAPI operation along with its problems, recording the names of the declared types of its
+// parameters and the references of its schemas.
+func (o Operation) resolve(mapper goToJSONSchemaMapper, parameterTypes map[ts.Identifier]bool, references *[]withAccounting[jsonschema.Schema, ts.Identifier]) (*openapi.Operation, []error) {
+	operation := &openapi.Operation{OperationID: o.ID, Summary: o.Summary, Description: o.Description, Tags: o.Tags, Responses: make(map[string]openapi.Response)}
+	var problems []error
+
+	var templateParameters []string
+	for _, match := range pathParameter.FindAllStringSubmatch(o.Path, -1) {
+		templateParameters = append(templateParameters, match[1])
+	}
+	if o.PathParameters != nil {
+		parameters, err := resolveParameters(mapper, o.PathParameters, openapi.InPath, parameterTypes, references)
+		problems = append(problems, err)
+		for _, p := range parameters {
+			if !slices.Contains(templateParameters, p.Name) {
+				problems = append(problems, fmt.Errorf("path parameter %s of %v isn't part of the path", p.Name, o.PathParameters))
+			}
+		}
+		operation.Parameters = append(operation.Parameters, parameters...)
+	}
+	for _, name := range templateParameters {
+		if !slices.ContainsFunc(operation.Parameters, func(p openapi.Parameter) bool { return p.Name == name }) {
+			problems = append(problems, fmt.Errorf("path parameter %s isn't declared, e.g. as a field of the PathParameters", name))
+		}
+	}
+	if o.QueryParameters != nil {
+		parameters, err := resolveParameters(mapper, o.QueryParameters, openapi.InQuery, parameterTypes, references)
+		problems = append(problems, err)
+		operation.Parameters = append(operation.Parameters, parameters...)
+	}
+
+	if o.Request != nil {
+		operation.RequestBody = &openapi.RequestBody{Required: true, Content: jsonContent(mapper, o.Request, references)}
+	}
+	if len(o.Responses) == 0 {
+		problems = append(problems, errors.New("no responses are declared"))
+	}
+	for _, r := range o.Responses {
+		status := strconv.Itoa(r.Status)
+		if _, ok := operation.Responses[status]; ok {
+			problems = append(problems, fmt.Errorf("more than one response with status %d is declared", r.Status))
+		}
+		response := openapi.Response{Description: r.Description}
+		if response.Description == "" {
+			response.Description = http.StatusText(r.Status)
+		}
+		if r.Type != nil {
+			response.Content = jsonContent(mapper, r.Type, references)
+		}
+		operation.Responses[status] = response
+	}
+	return operation, problems
+}
+
+// resolveParameters returns the parameters in the given location corresponding to the JSON properties of the given
+// struct type.
+func resolveParameters(mapper goToJSONSchemaMapper, t goinsp.Type, in string, parameterTypes map[ts.Identifier]bool, references *[]withAccounting[jsonschema.Schema, ts.Identifier]) ([]openapi.Parameter, error) {
+	reference := mapper.Resolve(t)
+	schema := reference.Value
+	if name, ok := mapper.namesByInput[mapper.builder.Canonical(t)]; ok {
+		// The parameters are those of the declaration, rather than a reference to it.
+		parameterTypes[name] = true
+		declaration := mapper.declarations[name].declaration
+		schema = declaration.Value.JSONSchema()
+		reference.info = declaration.info
+	}
+	*references = append(*references, reference)
+	if !slices.Equal(schema.Type, jsonschema.Types{jsonschema.ObjectType}) {
+		return nil, fmt.Errorf("the %s parameters must be given by a struct type, not %v", in, t)
+	}
+	parameters := make([]openapi.Parameter, len(schema.Properties))
+	for i, p := range schema.Properties {
+		description := p.Schema.Description
+		p.Schema.Description = ""
+		parameters[i] = openapi.Parameter{
+			Name:        p.Name,
+			In:          in,
+			Description: description,
+			// Path parameters are always required.
+			Required: in == openapi.InPath || slices.Contains(schema.Required, p.Name),
+			Schema:   p.Schema.Relocated(openapi.SchemasPrefix),
+		}
+	}
+	return parameters, nil
+}
+
+// jsonContent is the JSON content of a request or response body of the given type, whose reference it records.
+func jsonContent(mapper goToJSONSchemaMapper, t goinsp.Type, references *[]withAccounting[jsonschema.Schema, ts.Identifier]) map[string]openapi.MediaType {
+	reference := mapper.Resolve(t)
+	*references = append(*references, reference)
+	schema := reference.Value
+	return map[string]openapi.MediaType{"application/json": {Schema: schema.Relocated(openapi.SchemasPrefix)}}
+}
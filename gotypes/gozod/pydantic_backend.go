@@ -0,0 +1,328 @@
+package gozod
+
+import (
+	"encoding/json"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
+	"github.com/softwaretechnik-berlin/goats/gotypes/pydantic"
+	"github.com/softwaretechnik-berlin/goats/gotypes/ts"
+	"github.com/softwaretechnik-berlin/goats/gotypes/util"
+)
+
+type goToPydanticMapper = mapper[goinsp.Type, pydantic.Type, ts.Identifier, PydanticDeclaration]
+
+// NewPydanticMapper returns a mapper like NewMapper, whose declarations are Pydantic v2 models and type aliases rather
+// than zod schemas, e.g. `class User(BaseModel):`, for the Python module written by GeneratePydanticFile.
+//
+// Hand-written schemas, e.g. those of gotypes:schema directives, are reported by Err unless their Pydantic equivalents
+// are given with WithPydanticType, since they would accept any value.
+func NewPydanticMapper(options ...Option) goToPydanticMapper {
+	c := newConfig(options...)
+	b := newNodeBuilder(c)
+	b.genericTypes = true
+	return newBackendMapper(b, pydanticBackend{c.pydanticTypes})
+}
+
+// WithPydanticType gives the annotation of the Pydantic fields of the given type, which NewPydanticMapper uses in place
+// of the one derived from the type, e.g. for types whose zod schemas are given with WithSchema.
+func WithPydanticType(t goinsp.GenType, annotation pydantic.Type) Option {
+	return funcOption(func(c *config) {
+		if c.pydanticTypes == nil {
+			c.pydanticTypes = make(map[typeKey]pydantic.Type)
+		}
+		c.pydanticTypes[keyFor(t)] = annotation
+	})
+}
+
+// PydanticDeclaration declares a Pydantic annotation under the name of the declared type, with the comment of the
+// declaration as its docstring: objects as models, e.g. `class User(BaseModel):`, and other annotations as type aliases,
+// e.g. `Role = Literal["admin", "guest"]`.
+//
+// Generic types are declared as generic models, e.g. `class Page(BaseModel, Generic[T]):`. Enums are declared without
+// the value and label maps declared along with zod schemas.
+type PydanticDeclaration struct {
+	comment        string
+	name           ts.Identifier
+	typeParameters []ts.Identifier
+	annotation     pydantic.Type
+}
+
+func (d PydanticDeclaration) Identifier() ts.Identifier { return d.name }
+
+// Recursive returns the declaration unchanged, since lazy references are Forward references in Python.
+func (d PydanticDeclaration) Recursive() PydanticDeclaration {
+	return d
+}
+
+func (d PydanticDeclaration) Python() pydantic.Declaration {
+	typeParameters := util.Map(d.typeParameters, func(p ts.Identifier) string { return string(p) })
+	return pydantic.Declare(string(d.name), d.comment, typeParameters, d.annotation)
+}
+
+// pydanticBackend renders nodes as the annotations of Pydantic fields, which validate the same JSON values as their
+// schemas. Refinements are left out of them, except for those of validate tags.
+type pydanticBackend struct {
+	types map[typeKey]pydantic.Type
+}
+
+var _ backend[pydantic.Type, PydanticDeclaration] = pydanticBackend{}
+
+func (b pydanticBackend) name() string   { return "Pydantic" }
+func (b pydanticBackend) option() string { return "WithPydanticType" }
+
+func (b pydanticBackend) configures(t goinsp.Type) bool {
+	_, ok := lookupConfig(b.types, t)
+	return ok
+}
+
+func (b pydanticBackend) handWritten(t goinsp.Type, _ Resolver[goinsp.Type, node]) (node, bool) {
+	annotation, ok := lookupConfig(b.types, t)
+	if !ok {
+		return nil, false
+	}
+	return resolved[pydantic.Type]{annotation, unknownNode{}, ""}, true
+}
+
+func (b pydanticBackend) zodExpression(string) (node, bool) {
+	return nil, false
+}
+
+func (b pydanticBackend) render(n node, _ Resolver[goinsp.Type, node]) pydantic.Type {
+	return renderPydantic(n)
+}
+
+func (b pydanticBackend) reference(name ts.Identifier, _ pydantic.Type) pydantic.Type {
+	return pydantic.Expr(string(name))
+}
+
+func (b pydanticBackend) declare(d declared[pydantic.Type]) PydanticDeclaration {
+	return PydanticDeclaration{d.comment, d.name, d.typeParameters, d.schema}
+}
+
+// renderPydantic renders the given node as the annotation of Pydantic fields.
+func renderPydantic(n node) pydantic.Type {
+	switch n := n.(type) {
+	case anyNode, unknownNode:
+		return pydantic.Any()
+	case booleanNode:
+		return pydantic.Bool()
+	case bigIntNode:
+		return pydantic.Int()
+	case nullNode:
+		return pydantic.None()
+	case stringNode:
+		annotation := pydantic.Str()
+		if n.base != nil {
+			annotation = renderPydantic(n.base)
+		}
+		for _, c := range n.checks {
+			annotation = pydanticStringCheck(annotation, c)
+		}
+		return annotation
+	case numberNode:
+		annotation := pydantic.Float()
+		if n.base != nil {
+			annotation = renderPydantic(n.base)
+		}
+		for _, c := range n.checks {
+			annotation = pydanticNumberCheck(annotation, c)
+		}
+		return annotation
+	case arrayNode:
+		var annotation pydantic.Type
+		if n.base != nil {
+			annotation = renderPydantic(n.base)
+		} else {
+			annotation = pydantic.List(renderPydantic(n.elem))
+		}
+		for _, c := range n.checks {
+			switch c.kind {
+			case arrayMinLength:
+				annotation = annotation.MinLength(int(c.length))
+			case arrayMaxLength:
+				annotation = annotation.MaxLength(int(c.length))
+			case arrayExactLength:
+				annotation = annotation.Length(int(c.length))
+			}
+		}
+		return annotation
+	case recordNode:
+		return pydantic.Dict(renderPydantic(n.key), renderPydantic(n.value))
+	case literalNode:
+		return pydantic.Literal(n.value)
+	case stringEnumNode:
+		return pydantic.Literal(util.Map(n.values, func(v string) any { return v })...)
+	case enumNode:
+		return pydantic.Literal(n.values()...)
+	case objectNode:
+		return objectPydantic(n)
+	case unionNode:
+		members := util.Map(n.members, renderPydantic)
+		if n.discriminator != "" {
+			return pydantic.DiscriminatedUnion(n.discriminator, members...)
+		}
+		return pydantic.Union(members...)
+	case nullableNode:
+		return renderPydantic(n.inner).Nullable()
+	case optionalNode:
+		return renderPydantic(n.inner).Optional()
+	case defaultNode:
+		var literal any
+		if err := json.Unmarshal([]byte(n.value.String()), &literal); err == nil {
+			return renderPydantic(n.inner).Default(pydantic.Repr(literal))
+		}
+		return renderPydantic(n.inner).Optional()
+	case describedNode:
+		return renderPydantic(n.inner).Description(n.description)
+	case readonlyNode:
+		return renderPydantic(n.inner)
+	case brandedNode:
+		return renderPydantic(n.inner)
+	case hoistableNode:
+		return renderPydantic(n.inner)
+	case orNode:
+		return renderPydantic(n.inner).Or(renderPydantic(n.other))
+	case refinedNode:
+		return renderPydantic(n.inner).Check(n.check.python)
+	case transformNode:
+		switch {
+		case n.outputType != nil:
+			return pythonType(*n.outputType)
+		case n.outputOf != nil && n.before != "":
+			return renderPydantic(n.outputOf).Before(n.before)
+		case n.outputOf != nil:
+			return renderPydantic(n.outputOf)
+		default:
+			return pydantic.Any()
+		}
+	case quotedNode:
+		if n.parse {
+			return renderPydantic(n.inner).Json()
+		}
+		return renderPydantic(n.inner)
+	case templateNode:
+		return renderPydantic(n.inner).Template(n.pattern, n.template, n.names...)
+	case largeIntegerNode:
+		if n.asString {
+			// Pydantic parses decimal strings as integers, which it then turns back into strings.
+			return pydantic.Int().After("str")
+		}
+		return pydantic.Int()
+	case typeParameterNode:
+		return pydantic.TypeVar(string(n.name))
+	case factoryNode:
+		return pydantic.Expr(string(n.name))
+	case lazyNode:
+		return pydantic.Forward(pydantic.Expr(string(n.name)))
+	case invocationNode:
+		arguments := util.Map(n.arguments, renderPydantic)
+		if lazy, ok := referredFactory(n.factory).(lazyNode); ok {
+			// The factory is still being declared, so the generic model has to be referred to by a forward reference.
+			return pydantic.Forward(pydantic.Generic(pydantic.Expr(string(lazy.name)), arguments...))
+		}
+		return pydantic.Generic(renderPydantic(n.factory), arguments...)
+	case resolved[pydantic.Type]:
+		return n.schema
+	default:
+		panic(unknownNodeError(n))
+	}
+}
+
+func pydanticStringCheck(annotation pydantic.Type, c stringCheck) pydantic.Type {
+	switch c.kind {
+	case stringMin:
+		return annotation.MinLength(c.length)
+	case stringMax:
+		return annotation.MaxLength(c.length)
+	case stringLength:
+		return annotation.Length(c.length)
+	case stringEmail:
+		return annotation.Email()
+	case stringURL:
+		return annotation.URL()
+	case stringUUID:
+		return annotation.UUID()
+	case stringDatetime:
+		return annotation.DatetimeWithOffset()
+	case stringIP:
+		return annotation.IP()
+	case stringIPv4:
+		return annotation.IPv4()
+	case stringIPv6:
+		return annotation.IPv6()
+	case stringIncludes:
+		return annotation.Includes(c.text)
+	case stringStartsWith:
+		return annotation.StartsWith(c.text)
+	case stringEndsWith:
+		return annotation.EndsWith(c.text)
+	case stringRegex:
+		return annotation.Pattern(c.pattern)
+	default:
+		panic(c.kind)
+	}
+}
+
+func pydanticNumberCheck(annotation pydantic.Type, c numberCheck) pydantic.Type {
+	switch c.kind {
+	case numberInt:
+		return annotation.Integer()
+	case numberNonNegative:
+		return annotation.Ge(0)
+	case numberMin:
+		return annotation.Ge(c.bound)
+	case numberMax:
+		return annotation.Le(c.bound)
+	case numberGt:
+		return annotation.Gt(c.bound)
+	case numberLt:
+		return annotation.Lt(c.bound)
+	case numberSafe:
+		return annotation.Ge(-maxSafeInteger).Le(maxSafeInteger)
+	default:
+		panic(c.kind)
+	}
+}
+
+// objectPydantic is the Pydantic model of the objects of the given node, in which later properties override earlier
+// ones of the same name. The fields are named after the Go fields the properties correspond to, and have aliases if
+// their names differ from those of the properties.
+func objectPydantic(n objectNode) pydantic.Type {
+	var fields []pydantic.Field
+	indices := make(map[string]int)
+	for _, p := range n.properties {
+		field := pydantic.Field{Name: pydantic.AttributeName(p.name), Type: renderPydantic(p.node), Doc: p.comment}
+		if p.fieldName != "" {
+			field.Name = pydantic.AttributeName(p.fieldName)
+		}
+		if field.Name != p.name {
+			field.Alias = p.name
+		}
+		if i, ok := indices[p.name]; ok {
+			fields[i] = field
+			continue
+		}
+		indices[p.name] = len(fields)
+		fields = append(fields, field)
+	}
+	return pydantic.Model(fields...)
+}
+
+// pythonType is the Pydantic annotation of values of the given type, as far as it is known, which is that of the
+// output of transformations to the type.
+func pythonType(t ts.TypeExpression) pydantic.Type {
+	switch t.String() {
+	case ts.StringType.String():
+		return pydantic.Str()
+	case ts.NumberType.String():
+		return pydantic.Float()
+	case ts.BooleanType.String():
+		return pydantic.Bool()
+	case ts.BigIntType.String():
+		return pydantic.Int()
+	case "Date":
+		return pydantic.Datetime()
+	default:
+		return pydantic.Any()
+	}
+}
@@ -0,0 +1,27 @@
+package gozod
+
+import (
+	"os"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/pydantic"
+	"github.com/softwaretechnik-berlin/goats/gotypes/util"
+)
+
+// Pydantic returns the Python module declaring the models and type aliases of the given mapper, in the order of
+// SupportingDeclarations, unless the mapper found problems while resolving types, in which case it returns all of them
+// instead.
+func Pydantic(mapper goToPydanticMapper) (string, error) {
+	if err := mapper.Err(); err != nil {
+		return "", err
+	}
+	return pydantic.Module(util.Map(orderedDeclarations(mapper), PydanticDeclaration.Python)...), nil
+}
+
+// GeneratePydanticFile writes the Python module of the given mapper to the named file, see Pydantic.
+func GeneratePydanticFile(mapper goToPydanticMapper, outputFileName string) error {
+	module, err := Pydantic(mapper)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(outputFileName, []byte(module), 0o644)
+}
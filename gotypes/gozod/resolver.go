@@ -26,12 +26,18 @@ func (r *accountingResolver[A, B, Name]) Resolve(a A) B {
 		r.Observed.dependencies = make(map[Name]struct{})
 	}
 	maps.Copy(r.Observed.dependencies, resolved.info.dependencies)
+	if len(resolved.info.lazyDependencies) > 0 && len(r.Observed.lazyDependencies) == 0 {
+		r.Observed.lazyDependencies = make(map[Name]struct{})
+	}
+	maps.Copy(r.Observed.lazyDependencies, resolved.info.lazyDependencies)
 	return resolved.Value
 }
 
 type accountingInfo[Name comparable] struct {
 	dependencies map[Name]struct{}
-	depth        uint
+	// lazyDependencies are referred to lazily, so unlike dependencies, they don't need to be declared first.
+	lazyDependencies map[Name]struct{}
+	depth            uint
 }
 
 type withAccounting[B any, Name comparable] struct {
@@ -0,0 +1,102 @@
+package gozod
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/netip"
+	"time"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+	"github.com/softwaretechnik-berlin/goats/gotypes/pydantic"
+	"github.com/softwaretechnik-berlin/goats/gotypes/ts"
+)
+
+// WithStandardLibrary configures schemas for the standard library types that commonly occur in DTOs,
+// matching how encoding/json marshals them:
+//
+//   - time.Time is an RFC 3339 date-time string with a time zone offset (see also WithTimeAsDate).
+//   - time.Duration is an integer number of nanoseconds.
+//   - json.RawMessage is any JSON value, i.e. unknown, and json.Number is a number, declared as JSONNumber.
+//   - netip.Addr is an IP address string, or the empty string for the zero Addr.
+//   - big.Int is an integer number, represented according to its IntegerPolicy like 64-bit integers, while big.Float
+//     and big.Rat are strings.
+//   - The sql.Null* types are objects with the value and a Valid flag, which are transformed into the value or null.
+//
+// url.URL isn't configured, since encoding/json marshals it as an object of its fields.
+//
+// Options configuring these types explicitly take precedence, regardless of the order the options are given in.
+func WithStandardLibrary() Option {
+	return funcOption(func(c *config) {
+		c.standardLibrary = true
+	})
+}
+
+// WithTimeAsDate transforms the date-time strings of time.Time into JavaScript Dates, when given with WithStandardLibrary.
+func WithTimeAsDate() Option {
+	return funcOption(func(c *config) {
+		c.timeAsDate = true
+	})
+}
+
+// standardLibrary returns the options of WithStandardLibrary, given the configuration of the other options.
+func standardLibrary(c config) []Option {
+	var timeSchema node = stringNode{}.datetime()
+	if c.timeAsDate {
+		timeSchema = transformTo(timeSchema, ts.TypeName(ts.Identifier("Date")), ts.AsSource(`s => new Date(s)`))
+	}
+	return []Option{
+		When[time.Time]().standardSchema(timeSchema),
+		When[time.Duration]().standardSchema(describedNode{numberNode{}.int(), "duration in nanoseconds"}),
+		// Where json.RawMessage is an alias of jsontext.Value, it would otherwise be named Value, which is too vague.
+		When[json.RawMessage]().Named("RawMessage").standardSchema(unknownNode{}),
+		// json.Number would shadow the global Number.
+		When[json.Number]().Named("JSONNumber").standardSchema(numberNode{}),
+		When[netip.Addr]().standardSchema(unionNode{members: []node{stringNode{}.ip(), literalNode{""}}}),
+		// Like those of 64-bit integers, the values of big.Int may exceed Number.MAX_SAFE_INTEGER.
+		When[big.Int]().standardSchema(largeIntegerSchema(numberNode{}.int(), signedDecimal, c.typeIntegerPolicy(reflective.TypeFor[big.Int]()))),
+		When[big.Float]().standardSchema(stringNode{}),
+		When[big.Rat]().standardSchema(stringNode{}),
+		sqlNull[sql.NullBool](),
+		sqlNull[sql.NullByte](),
+		sqlNull[sql.NullFloat64](),
+		sqlNull[sql.NullInt16](),
+		sqlNull[sql.NullInt32](),
+		sqlNull[sql.NullInt64](),
+		sqlNull[sql.NullString](),
+		sqlNull[sql.NullTime](),
+	}
+}
+
+// withStandardSchema configures the node of the given standard library type, which the schemas configured for it with
+// the other options take precedence over.
+func withStandardSchema(t goinsp.GenType, schema func(nodes Resolver[goinsp.Type, node]) node) Option {
+	return funcOption(func(c *config) {
+		if c.standardSchemas == nil {
+			c.standardSchemas = make(map[typeKey]func(nodes Resolver[goinsp.Type, node]) node)
+		}
+		c.standardSchemas[keyFor(t)] = schema
+	})
+}
+
+func (o TypeOptions) standardSchema(n node) TypeOptions {
+	return o.add(withStandardSchema(o.t, func(Resolver[goinsp.Type, node]) node { return n }))
+}
+
+// sqlNull configures the node of one of the sql.Null* types, whose first field holds the value and whose second field
+// tells whether the value is valid.
+func sqlNull[N any]() Option {
+	t := reflective.TypeFor[N]()
+	return withStandardSchema(t, func(nodes Resolver[goinsp.Type, node]) node {
+		value, valid := t.Field(0), t.Field(1)
+		valueNode := nodes.Resolve(value.Type())
+		return transformToOutputOf(
+			objectNode{[]property{{name: value.Name, node: valueNode}, {name: valid.Name, node: booleanNode{}}}},
+			nullableNode{valueNode},
+			ts.Sourcef("n => n.%s ? n.%s : null", ts.Identifier(valid.Name), ts.Identifier(value.Name)),
+			fmt.Sprintf("lambda n: n[%s] if n[%s] else None", pydantic.Repr(value.Name), pydantic.Repr(valid.Name)),
+		)
+	})
+}
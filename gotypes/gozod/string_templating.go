@@ -8,103 +8,134 @@ import (
 
 	"github.com/softwaretechnik-berlin/goats/gotypes/ts"
 	"github.com/softwaretechnik-berlin/goats/gotypes/util"
-	"github.com/softwaretechnik-berlin/goats/gotypes/zod"
 )
 
 type templateEmbedding interface {
 	RegexString() string
-	Parse(str ts.Source) ts.Source
+	// Parse parses the matched string into a value of the embedded schema with the given parser.
+	Parse(parser parser, str ts.Source) ts.Source
 }
 
+// parser parses a TypeScript value with the rendering of a node in the validator library of a backend, e.g.
+// `schema.parse(value)` for zod.
+type parser func(n node, value ts.Source) ts.Source
+
 var _ templateEmbedding = numberEmbedding{}
 var _ templateEmbedding = stringEmbedding{}
 
-type numberEmbedding struct{ schema zod.ZodNumber }
+// numberEmbedding embeds the values of the number schema n, whose structure is number.
+type numberEmbedding struct {
+	n      node
+	number numberNode
+}
 
 func (n numberEmbedding) RegexString() string {
 	regex := `\d+`
-	if !n.schema.IsInt() {
+	if !n.number.isInt() {
 		regex += `(?:\.\d+)?`
 	}
-	if !n.schema.IsNonNegative() {
+	if !n.number.isNonNegative() {
 		regex = `-?` + regex
 	}
 	return regex
 }
 
-func (n numberEmbedding) Parse(str ts.Source) ts.Source {
-	return n.schema.Parsef("Number(%s)", str)
+func (n numberEmbedding) Parse(parser parser, str ts.Source) ts.Source {
+	return parser(n.n, ts.Sourcef("Number(%s)", str))
 }
 
-type stringEmbedding struct{ schema zod.ZodString }
+type stringEmbedding struct{}
 
 func (s stringEmbedding) RegexString() string {
 	return ".*"
 }
 
-func (s stringEmbedding) Parse(str ts.Source) ts.Source {
+func (s stringEmbedding) Parse(_ parser, str ts.Source) ts.Source {
 	return str
 }
 
-func resolveEmbedding(schema zod.ZodType) templateEmbedding {
-	switch schema := schema.(type) {
-	case zod.ZodBranded:
-		return resolveEmbedding(schema.Unwrap())
-	case zod.ZodNumber:
-		return numberEmbedding{schema}
-	case zod.ZodString:
-		return stringEmbedding{schema}
+func resolveEmbedding(n node) (templateEmbedding, error) {
+	switch structure := structureOf(n).(type) {
+	case brandedNode:
+		return resolveEmbedding(structure.inner)
+	case nullableNode:
+		return resolveEmbedding(structure.inner)
+	case optionalNode:
+		return resolveEmbedding(structure.inner)
+	case numberNode:
+		return numberEmbedding{n, structure}, nil
+	case stringNode:
+		return stringEmbedding{}, nil
 	default:
-		panic(fmt.Sprintf("%#v", schema))
+		return nil, fmt.Errorf("only numbers and strings can be embedded in templates, not %s", describe(n))
 	}
 }
 
-func applyTemplateTransform(schema zod.ZodType, template string) zod.ZodType {
-	r, transformMatch := fromTemplatedString(schema, template)
-	return zod.String().Transformf(`(s, ctx) => {
-    const re = %s;
-    const match = re.exec(s);
-    if (!match) {
-        ctx.addIssue({ code: %s.ZodIssueCode.custom, message: "expected string of the form %s matching " + re });
-        return z.NEVER;
-    }
-    return %s;
-}`, ts.RegexLiteral(regexp.MustCompile(`^`+r+`$`)), ts.ImportedName("zod", "z"), ts.StringEscape(ts.StringLiteral(template).String()), transformMatch) // TODO
+// applyTemplateTransform returns a node parsing strings of the given template into values of the given node.
+func applyTemplateTransform(n node, template string) (node, error) {
+	r, transformMatch, names, err := fromTemplatedString(n, template)
+	if err != nil {
+		return nil, err
+	}
+	return templateNode{n, template, regexp.MustCompile(`^` + r + `$`), transformMatch, names}, nil
 }
 
-func fromTemplatedString(schema zod.ZodType, template string) (string, ts.Source) {
-	if schema, ok := schema.(zod.ZodObject); ok {
-		return objectFromTemplatedString(schema, template)
+// fromTemplatedString returns the regular expression matching strings of the given template, along with a function
+// returning the TypeScript expression that transforms its `match` into a value of the given node with a given parser,
+// and the names of the properties matched by the groups of the expression in order if the node is an object.
+func fromTemplatedString(n node, template string) (string, func(parser) ts.Source, []string, error) {
+	if object, ok := structureOf(n).(objectNode); ok {
+		return objectFromTemplatedString(object, template)
+	}
+	embedding, err := resolveEmbedding(n)
+	if err != nil {
+		return "", nil, nil, err
 	}
-	embedding := resolveEmbedding(schema)
 	prefix, suffix, ok := strings.Cut(template, "{}")
 	if !ok {
-		panic(fmt.Sprintf("can't find placeholder {} in template %#v", template))
+		return "", nil, nil, fmt.Errorf("can't find placeholder {} in template %#v", template)
 	}
 	regex := regexp.QuoteMeta(prefix) + "(" + embedding.RegexString() + ")" + regexp.QuoteMeta(suffix)
-	return regex, embedding.Parse(ts.Sourcef("match[1]"))
+	return regex, func(parser parser) ts.Source { return embedding.Parse(parser, ts.Sourcef("match[1]")) }, nil, nil
 }
 
-func objectFromTemplatedString(schema zod.ZodObject, template string) (string, ts.Source) {
-	shape := schema.Shape()
-	embeddings := util.Map(shape, func(p zod.ShapeProperty) templateEmbedding { return resolveEmbedding(p.Schema) })
-	placeholder := regexp.MustCompile(`\{(` + strings.Join(util.Map(shape, func(p zod.ShapeProperty) string { return regexp.QuoteMeta(p.Name) }), `|`) + `)\}`)
+func objectFromTemplatedString(object objectNode, template string) (string, func(parser) ts.Source, []string, error) {
+	shape := object.properties
+	embeddings := make([]templateEmbedding, len(shape))
+	for i, p := range shape {
+		embedding, err := resolveEmbedding(p.node)
+		if err != nil {
+			return "", nil, nil, fmt.Errorf("property %s: %w", p.name, err)
+		}
+		embeddings[i] = embedding
+	}
+	placeholder := regexp.MustCompile(`\{(` + strings.Join(util.Map(shape, func(p property) string { return regexp.QuoteMeta(p.name) }), `|`) + `)\}`)
 	var regex strings.Builder
-	outputProperties := make([]ts.Property, len(shape))
+	var names []string
+	matchIndices := make([]int, len(shape))
 	for matchIndex := 1; ; matchIndex++ {
 		loc := placeholder.FindStringIndex(template)
 		if loc == nil {
 			break
 		}
 		regex.WriteString(regexp.QuoteMeta(template[:loc[0]]))
-		propertyIndex := slices.IndexFunc(shape, func(p zod.ShapeProperty) bool { return p.Name == template[loc[0]+1:loc[1]-1] })
+		propertyIndex := slices.IndexFunc(shape, func(p property) bool { return p.name == template[loc[0]+1:loc[1]-1] })
 		embedding := embeddings[propertyIndex]
 		regex.WriteByte('(')
 		regex.WriteString(embedding.RegexString())
 		regex.WriteByte(')')
-		outputProperties[propertyIndex] = ts.Property{shape[propertyIndex].Name, embedding.Parse(ts.Sourcef("match[%s]", ts.NumberLiteral(matchIndex)))}
+		matchIndices[propertyIndex] = matchIndex
+		names = append(names, shape[propertyIndex].name)
 		template = template[loc[1]:]
 	}
 	regex.WriteString(regexp.QuoteMeta(template))
-	return regex.String(), ts.Object(outputProperties...)
+	return regex.String(), func(parser parser) ts.Source {
+		outputProperties := make([]ts.Property, len(shape))
+		for i, p := range shape {
+			if matchIndices[i] != 0 {
+				outputProperties[i] = ts.Property{Name: p.name, Value: embeddings[i].Parse(parser, ts.Sourcef("match[%s]", ts.NumberLiteral(matchIndices[i])))}
+			}
+		}
+		return ts.Object(outputProperties...)
+	}, names, nil
 }
@@ -0,0 +1,6 @@
+// Package index is written to a module called index by GenerateFiles, which collides with the index file.
+package index
+
+type Entry struct {
+	Key string
+}
@@ -1,21 +1,50 @@
 package gozod
 
 import (
+	"errors"
 	"os"
 
 	"github.com/samber/lo"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
+	"github.com/softwaretechnik-berlin/goats/gotypes/ts"
 )
 
-func Generate(mapper goToZodMapper, outputFileName string) {
+// GenerateFile writes the declarations of the given mapper to the named file, unless the mapper found problems while
+// resolving types, in which case it returns all of them instead.
+func GenerateFile[S any, D typeScriptDeclaration[D]](mapper mapper[goinsp.Type, S, ts.Identifier, D], outputFileName string) (err error) {
+	if err := mapper.Err(); err != nil {
+		return err
+	}
 	declarations := SupportingDeclarations(mapper)
 
-	w := lo.Must(os.Create(outputFileName))
-	defer func() { lo.Must0(w.Close()) }()
+	w, err := os.Create(outputFileName)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, w.Close()) }()
 
-	lo.Must(w.WriteString(declarations.String()))
+	_, err = w.WriteString(declarations.String())
+	return err
 }
 
-func GenerateString(mapper goToZodMapper, outputFileName string) string {
-	declarations := SupportingDeclarations(mapper)
-	return declarations.String()
+// Generate is like GenerateFile, but panics instead of returning an error.
+func Generate[S any, D typeScriptDeclaration[D]](mapper mapper[goinsp.Type, S, ts.Identifier, D], outputFileName string) {
+	lo.Must0(GenerateFile(mapper, outputFileName))
+}
+
+// GenerateSource returns the declarations of the given mapper, unless the mapper found problems while resolving types,
+// in which case it returns all of them instead.
+func GenerateSource[S any, D typeScriptDeclaration[D]](mapper mapper[goinsp.Type, S, ts.Identifier, D]) (string, error) {
+	if err := mapper.Err(); err != nil {
+		return "", err
+	}
+	return SupportingDeclarations(mapper).String(), nil
+}
+
+// GenerateString is like GenerateSource, but panics instead of returning an error.
+//
+// Deprecated: Use GenerateSource, which reports the problems as an error. The outputFileName is unused.
+func GenerateString[S any, D typeScriptDeclaration[D]](mapper mapper[goinsp.Type, S, ts.Identifier, D], outputFileName string) string {
+	return lo.Must(GenerateSource(mapper))
 }
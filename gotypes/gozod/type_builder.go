@@ -0,0 +1,790 @@
+package gozod
+
+import (
+	"cmp"
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/exp/maps"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp/reflective"
+	"github.com/softwaretechnik-berlin/goats/gotypes/ts"
+	"github.com/softwaretechnik-berlin/goats/gotypes/util"
+)
+
+// nodeBuilder builds the nodes of the schemas of Go types, which backends render in the libraries their declarations
+// use, see typeBuilder.
+type nodeBuilder struct {
+	config
+	// canonical holds the first of the named types with each key to be built,
+	// so that the same type obtained from different goinsp implementations is only declared once.
+	canonical map[typeKey]goinsp.Type
+	// problems holds the problems found while building, in the order they were found.
+	problems *[]error
+	// path holds the segments of the Go type path from the type being resolved to the position being built, e.g.
+	// `api.Order`, `.Lines`, `[]`.
+	path *[]string
+	// genericTypes tells whether generic types are declared as generic types rather than as factories of schemas, see
+	// NewTypesMapper and NewPydanticMapper.
+	genericTypes bool
+	// library is that of the backend rendering the nodes, whose hand-written schemas take the place of the nodes of the
+	// types they are given for.
+	library library
+}
+
+func newNodeBuilder(config config) nodeBuilder {
+	problems := slices.Clone(config.problems)
+	return nodeBuilder{config, make(map[typeKey]goinsp.Type), &problems, new([]string), false, nil}
+}
+
+func (b nodeBuilder) Canonical(t goinsp.Type) goinsp.Type {
+	key := keyFor(t)
+	if key.unnamed != nil || containsTypeParameters(t) {
+		// Type parameters with the same name may belong to different generic types.
+		return t
+	}
+	if canonical, ok := b.canonical[key]; ok {
+		return canonical
+	}
+	b.canonical[key] = t
+	return t
+}
+
+func (b nodeBuilder) Name(t goinsp.Type) (ts.Identifier, bool) {
+	return b.name(t)
+}
+
+// build returns the node of the schema of t, along with the name it is declared under, if any, and the type parameters
+// of the factory or generic type declared for it, if any.
+func (b nodeBuilder) build(t goinsp.Type, nodes Resolver[goinsp.Type, node]) (n node, name ts.Identifier, named bool, typeParameterNames []ts.Identifier) {
+	if b.instantiatesFactory(t) {
+		n = b.invokeFactory(t, nodes)
+		if name, named = b.name(t); named {
+			b.checkName(t, name)
+		}
+		return n, name, named, nil
+	}
+	directives := b.directives(t)
+	nodeBeforeTemplating := b.transformed(t, directives, b.buildRawSchema(t, goinsp.Unaddressable, directives, nodes))
+	n = b.templated(t, directives, nodeBeforeTemplating)
+	name, named = b.name(t)
+	if !named {
+		if b.sharedSubSchemas && !containsTypeParameters(t) {
+			// Schemas referring to type parameters can't be declared outside of their factories.
+			n = hoistableNode{n}
+		}
+		return n, "", false, nil
+	}
+	b.checkName(t, name)
+	if b.shouldBrand(t, directives, nodeBeforeTemplating) {
+		n = brandedNode{n, string(name)}
+	}
+	typeParameterNames = mapSlice(typeParameters(t), func(p goinsp.Type) ts.Identifier { return ts.Identifier(p.Name()) })
+	return n, name, true, typeParameterNames
+}
+
+// transformed returns the given node of type t with the transform configured for t or given in its directives.
+func (b nodeBuilder) transformed(t goinsp.Type, directives directives, n node) node {
+	if transform, ok := lookupConfig(b.transforms, t); ok {
+		return transformNode{inner: n, fn: transform}
+	}
+	if directives.transform != "" {
+		return transform(n, directives.transformExpr())
+	}
+	return n
+}
+
+// templated returns the given node of type t with the template configured for t or given in its directives applied.
+func (b nodeBuilder) templated(t goinsp.Type, directives directives, n node) node {
+	template, ok := b.template(t, directives)
+	if !ok {
+		return n
+	}
+	templated, err := applyTemplateTransform(n, template)
+	if err != nil {
+		b.report(fmt.Errorf("can't apply the template of %v: %w", t, err))
+		return n
+	}
+	return templated
+}
+
+// invalid reports a problem preventing the node of a type from being built and returns the node accepting any value
+// in its place.
+func (b nodeBuilder) invalid(problem error) node {
+	b.report(problem)
+	return unknownNode{}
+}
+
+func (b nodeBuilder) Err() error {
+	return errors.Join(*b.problems...)
+}
+
+// report records a problem that doesn't prevent the schema from being built, so that Err reports it along with the path
+// to the position it was found at. Problems found repeatedly at the same position are only reported once.
+func (b nodeBuilder) report(problem error) {
+	if len(*b.path) > 0 {
+		problem = &ResolutionError{strings.Join(*b.path, ""), problem}
+	}
+	if slices.ContainsFunc(*b.problems, func(p error) bool { return p.Error() == problem.Error() }) {
+		return
+	}
+	*b.problems = append(*b.problems, problem)
+}
+
+// enter appends the given segment to the Go type path, e.g. the field `.Price` or the elements `[]`, so that the problems
+// found until the returned function is called are reported with the path.
+func (b nodeBuilder) enter(segment string) (leave func()) {
+	*b.path = append(*b.path, segment)
+	return func() { *b.path = (*b.path)[:len(*b.path)-1] }
+}
+
+// configuresSchema tells whether a schema is given for t, e.g. with WithSchema or WithStandardLibrary, which takes the
+// place of the one derived from its Go type.
+func (b nodeBuilder) configuresSchema(t goinsp.Type) bool {
+	_, configured := lookupConfig(b.schemas, t)
+	_, standard := lookupConfig(b.standardSchemas, t)
+	return configured || standard || b.library.configures(t)
+}
+
+// configuredSchema returns the node of the schema given for t, if any: the one hand-written in the library of the
+// backend, or else that of WithStandardLibrary. Hand-written zod schemas lacking an equivalent in the library are
+// reported.
+func (b nodeBuilder) configuredSchema(t goinsp.Type, nodes Resolver[goinsp.Type, node]) (node, bool) {
+	if n, ok := b.library.handWritten(t, nodes); ok {
+		return n, true
+	}
+	if _, ok := lookupConfig(b.schemas, t); ok {
+		return b.lacksEquivalent(), true
+	}
+	if schema, ok := lookupConfig(b.standardSchemas, t); ok {
+		return schema(nodes), true
+	}
+	return nil, false
+}
+
+// zodExpression returns the node of the given hand-written zod expression, e.g. of a gotypes:schema directive,
+// reporting it if it lacks an equivalent in the library of the backend.
+func (b nodeBuilder) zodExpression(expr string) node {
+	if n, ok := b.library.zodExpression(expr); ok {
+		return n
+	}
+	return b.lacksEquivalent()
+}
+
+// lacksEquivalent reports a hand-written zod schema lacking an equivalent in the library of the backend, which would
+// accept any value, and returns the node accepting any value in its place.
+func (b nodeBuilder) lacksEquivalent() node {
+	return b.invalid(fmt.Errorf("the hand-written schema has no %s equivalent, which would accept any value; state it with %s", b.library.name(), b.library.option()))
+}
+
+// at returns the node returned by build for the given segment of the Go type path.
+func (b nodeBuilder) at(segment string, build func() node) node {
+	defer b.enter(segment)()
+	return build()
+}
+
+// ResolutionError is a problem found while resolving the schema of a Go type.
+type ResolutionError struct {
+	// Path is the Go type path from the resolved type to the position the problem was found at, e.g.
+	// `api.Order.Lines[].Product.Price`. Slice and array elements and map values are denoted by `[]`, map keys by
+	// `[key]`.
+	Path string
+	Err  error
+}
+
+func (e *ResolutionError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+func (e *ResolutionError) Unwrap() error {
+	return e.Err
+}
+
+func (b nodeBuilder) docComment(name ts.Identifier, t goinsp.Type) string {
+	docComment := fmt.Sprintf("%s corresponds to Go type %s (in package %#v).\n", name, t, t.PkgPath())
+	if parameters := typeParameters(t); len(parameters) > 0 {
+		generic := fmt.Sprintf("%s[%s]", t, strings.Join(mapSlice(parameters, goinsp.Type.String), ","))
+		if b.genericTypes {
+			docComment = fmt.Sprintf("%s corresponds to Go type %s (in package %#v).\n", name, generic, t.PkgPath())
+		} else {
+			docComment = fmt.Sprintf("%s returns the schema corresponding to Go type %s (in package %#v), given the schemas for its type arguments.\n", name, generic, t.PkgPath())
+		}
+	}
+	if goComment := b.comment(t); goComment != "" {
+		docComment += "The comment on the original Go type follows.\n\n" + goComment
+	}
+	return docComment
+}
+
+func (b nodeBuilder) comment(t goinsp.Type) string {
+	if comment := t.Comment(); comment.Available {
+		return comment.Value.Value
+	}
+	comment, err := b.commentsLoader.Load(t)
+	if err != nil {
+		b.report(fmt.Errorf("can't load the comment of %v: %w", t, err))
+	}
+	return comment
+}
+
+func (b nodeBuilder) fieldComment(t goinsp.Type, field goinsp.StructField) string {
+	if comment := field.Comment(); comment.Available {
+		return comment.Value.Value
+	}
+	if t.PkgPath() == "" || t.Name() == "" {
+		// There's no declaration we could find the comment with.
+		return ""
+	}
+	comment, err := b.commentsLoader.LoadField(t, field.Name)
+	if err != nil {
+		b.report(fmt.Errorf("can't load the comment of field %s of %v: %w", field.Name, t, err))
+	}
+	return comment
+}
+
+func (b nodeBuilder) name(t goinsp.Type) (ts.Identifier, bool) {
+	if b.instantiatesFactory(t) {
+		// Only instantiations that are named explicitly are declared, rather than just invoking the factory.
+		name, ok := b.names[keyFor(t)]
+		return name, ok
+	}
+	if name, ok := lookupConfig(b.names, t); ok {
+		return name, true
+	}
+	if _, unnamed := lookupConfig(b.unnamedTypes, t); unnamed || t.PkgPath() == "" {
+		return "", false
+	}
+	return b.namingStrategy(t), true
+}
+
+// checkName reports the name t is declared under if it would shadow one of the globals.
+func (b nodeBuilder) checkName(t goinsp.Type, name ts.Identifier) {
+	if globals[name] {
+		b.report(fmt.Errorf("%v can't be declared as %s, since that would shadow the JavaScript global the generated code refers to; give it another name, e.g. with WithName", t, name))
+	}
+}
+
+func (b nodeBuilder) template(t goinsp.Type, directives directives) (string, bool) {
+	if template, ok := lookupConfig(b.templates, t); ok {
+		return template, true
+	}
+	return directives.template, directives.template != ""
+}
+
+func (b nodeBuilder) shouldBrand(t goinsp.Type, directives directives, n node) bool {
+	if b.configuresSchema(t) {
+		return false
+	}
+	if directives.schema != "" || directives.transform != "" {
+		return false
+	}
+	if _, ok := lookupConfig(b.undiscriminatedUnions, t); ok {
+		return false
+	}
+	if _, ok := lookupConfig(b.discriminatedUnions, t); ok {
+		return false
+	}
+	if _, ok := lookupConfig(b.transforms, t); ok {
+		return false
+	}
+
+	for {
+		switch structure := structureOf(n).(type) {
+		case objectNode, enumNode:
+			return false
+		case brandedNode:
+			n = structure.inner
+		case nullableNode:
+			n = structure.inner
+		case optionalNode:
+			n = structure.inner
+		default:
+			return true
+		}
+	}
+}
+
+func (b nodeBuilder) buildRawSchema(t goinsp.Type, a goinsp.Addressability, directives directives, nodes Resolver[goinsp.Type, node]) node {
+	if t.IsTypeParameter() {
+		return typeParameterNode{ts.Identifier(t.Name())}
+	}
+	if n, ok := b.configuredSchema(t, nodes); ok {
+		return n
+	}
+	if types, ok := lookupConfig(b.undiscriminatedUnions, t); ok {
+		return unionNode{members: mapSlice(types, nodes.Resolve)}
+	}
+	if union, ok := lookupConfig(b.discriminatedUnions, t); ok {
+		return unionNode{mapSlice(union.Types, nodes.Resolve), union.DiscriminatorProperty}
+	}
+	if directives.schema != "" {
+		return b.zodExpression(directives.schema)
+	}
+	if samples, ok := lookupConfig(b.jsonSamples, t); ok {
+		return b.sampledSchema(t, samples)
+	}
+	if _, ok := b.template(t, directives); !ok {
+		if enum, ok := b.enumSchema(t); ok {
+			return enum
+		}
+	}
+	if t.Kind() == reflect.Pointer && b.configuresSchema(t.Elem()) {
+		// The schema configured for the element takes precedence over the methods of the pointer type.
+		return ensureNullable(nodes.Resolve(t.Elem()))
+	}
+	if b.marshalsItself(t, a, directives) {
+		if a == goinsp.Addressable {
+			b.report(fmt.Errorf("pointers to %v implement json.Marshaler, so the JSON of its addressable values can't be derived from its Go type; give its schema, e.g. with WithSchema, or samples of pointers to its values with WithJSONSamples", t))
+		} else {
+			b.report(fmt.Errorf("%v implements json.Marshaler, so its JSON can't be derived from its Go type; give its schema, e.g. with WithSchema, or samples of its values with WithJSONSamples", t))
+		}
+		return unknownNode{}
+	}
+	if _, ok := b.template(t, directives); !ok && t.Kind() != reflect.Pointer && a.Implements(t, reflective.TypeFor[encoding.TextMarshaler]()) {
+		return stringNode{}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return booleanNode{}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return integerSchema(t.Kind(), b.typeIntegerPolicy(t))
+	case reflect.Float32, reflect.Float64:
+		return numberNode{}
+	case reflect.Array:
+		// Unnamed array types may occur in any position, so their elements may be addressable.
+		return arrayNode{elem: b.at("[]", func() node { return b.resolveAt(t.Elem(), goinsp.MaybeAddressable, nodes) })}.length(t.Len())
+	case reflect.Interface:
+		return anyNode{}
+	case reflect.Map, reflect.Slice:
+		return b.nilableSchema(t, b.nilPolicy(t), nodes)
+	case reflect.Pointer:
+		return ensureNullable(b.resolveAt(t.Elem(), goinsp.Addressable, nodes))
+	case reflect.String:
+		return stringNode{}
+	case reflect.Struct:
+
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if tag, _ := parseFieldTag(field.Tag.Get("gotypes")); tag.value {
+				defer b.enter("." + field.Name)()
+				tag, v := b.fieldTags(t, field)
+				return b.resolveFieldSchema(field.Type(), goinsp.MaybeAddressable, field.Tag.Get("json"), tag, v, nodes)
+			}
+		}
+
+		if embedded, ok := promotedJSONMarshaller(t, a); ok {
+			return b.resolveAt(embedded, a, nodes)
+		}
+
+		var properties []property
+		if discriminator, ok := lookupConfig(b.discriminators, t); ok {
+			properties = append(properties, property{name: discriminator.Property, node: literalNode{discriminator.Value}})
+		}
+		fields := jsonFields(t, b.promotesFields, func(f jsonField) []string {
+			object, ok := b.embeddedObject(f, nodes)
+			if !ok {
+				return nil
+			}
+			return mapSlice(object.properties, func(p property) string { return p.name })
+		})
+		for _, f := range fields {
+			if f.embedded {
+				properties = append(properties, b.promotedProperty(f, nodes))
+				continue
+			}
+			properties = append(properties, b.property(f, nodes))
+		}
+		return objectNode{properties}
+	default:
+		return b.invalid(fmt.Errorf("unsupported kind %v", t.Kind()))
+	}
+}
+
+// promotesFields tells whether the fields of the given struct type are promoted where it is embedded, rather than
+// those of the object schema given for it, e.g. with WithSchema or by a discriminator.
+func (b nodeBuilder) promotesFields(t goinsp.Type) bool {
+	if b.configuresSchema(t) {
+		return false
+	}
+	if _, ok := lookupConfig(b.discriminators, t); ok {
+		return false
+	}
+	if _, ok := lookupConfig(b.transforms, t); ok {
+		return false
+	}
+	directives := b.directives(t)
+	_, templated := b.template(t, directives)
+	return directives.schema == "" && directives.transform == "" && !templated
+}
+
+// embeddedObject returns the object schema given for the given embedded struct, whose fields aren't promoted
+// themselves, reporting if the schema isn't an object.
+func (b nodeBuilder) embeddedObject(f jsonField, nodes Resolver[goinsp.Type, node]) (objectNode, bool) {
+	defer b.enter("." + f.field.Name)()
+	t := f.embeddedType()
+	object, ok := structureOf(nodes.Resolve(t)).(objectNode)
+	if !ok {
+		b.report(fmt.Errorf("can't promote the fields of embedded %v, since the schema given for it isn't an object", t))
+	}
+	return object, ok
+}
+
+// promotedProperty returns the property that the given embedded struct, whose fields aren't promoted themselves,
+// contributes to the object schema of the struct embedding it, taken from the object schema given for it.
+func (b nodeBuilder) promotedProperty(f jsonField, nodes Resolver[goinsp.Type, node]) property {
+	object, _ := b.embeddedObject(f, nodes)
+	p := object.properties[slices.IndexFunc(object.properties, func(p property) bool { return p.name == f.name })]
+	if f.viaPointer && !isOptional(p.node) {
+		// encoding/json omits the fields promoted through a nil pointer.
+		p.node = optionalNode{p.node}
+	}
+	return p
+}
+
+// property returns the property of a struct's object schema corresponding to the given field.
+func (b nodeBuilder) property(f jsonField, nodes Resolver[goinsp.Type, node]) property {
+	defer b.enter("." + f.field.Name)()
+	tag, v := b.fieldTags(f.declaringType, f.field)
+	// Whether the struct's fields are addressable depends on whether its values are, unless they're promoted through
+	// embedded pointers.
+	addressability := goinsp.MaybeAddressable
+	if f.viaPointer {
+		addressability = goinsp.Addressable
+	}
+	n := b.resolveFieldSchema(f.field.Type(), addressability, f.tag, tag, v, nodes)
+	if f.viaPointer && !isOptional(n) && !tag.required && tag.defaultValue == nil {
+		// encoding/json omits the fields promoted through a nil pointer.
+		n = optionalNode{n}
+	}
+	comment := b.fieldComment(f.declaringType, f.field)
+	if b.describeFields && comment != "" {
+		n = describedNode{n, strings.TrimSpace(comment)}
+	}
+	return property{name: f.name, node: n, comment: comment, outputName: tag.name, fieldName: f.field.Name}
+}
+
+// fieldTags returns the parsed gotypes and validate tags of the given field of struct type t. A gotypes tag that
+// can't be parsed is reported and ignored.
+func (b nodeBuilder) fieldTags(t goinsp.Type, field goinsp.StructField) (fieldTag, validation) {
+	tag, err := parseFieldTag(field.Tag.Get("gotypes"))
+	if err != nil {
+		b.report(err)
+		tag = fieldTag{}
+	}
+	v := b.parseValidateTag(field.Tag.Get("validate"))
+	if v.required {
+		// The zero values encoding/json omits are invalid, so the property must be present.
+		tag.required = true
+	}
+	return tag, v
+}
+
+func (b nodeBuilder) resolveFieldSchema(t goinsp.Type, a goinsp.Addressability, jsonTag string, tag fieldTag, v validation, nodes Resolver[goinsp.Type, node]) node {
+	n := b.fieldTypeSchema(t, a, tag, v, nodes)
+	if tagHasFlag(jsonTag, "string") && b.kindSupportsJSONStringFlag(t) {
+		inner, needsNullable := stripNullable(n)
+		n = quotedNode{inner, !b.acceptsDecimalStrings(t, tag.integerPolicy, tag.hasIntegerPolicy), quotedPattern(t)}
+		if needsNullable {
+			n = ensureNullable(n)
+		}
+	}
+	if tag.nullable {
+		n = ensureNullable(n)
+	}
+
+	if tag.defaultValue != nil {
+		n = defaultNode{n, tag.defaultValue}
+	} else if tagHasFlag(jsonTag, "omitempty") && !tag.required {
+		n = optionalNode{n}
+	}
+	return n
+}
+
+// fieldTypeSchema returns the node of the type of a field with the given addressability and gotypes and validate tags,
+// applying the tags' policies, constraints, brand and nonnull and readonly options.
+func (b nodeBuilder) fieldTypeSchema(t goinsp.Type, a goinsp.Addressability, tag fieldTag, v validation, nodes Resolver[goinsp.Type, node]) node {
+	refineByTag := func(t goinsp.Type, n node) node {
+		refined, err := tag.refine(t, n)
+		if err != nil {
+			b.report(err)
+		}
+		return refined
+	}
+	if tag.schema != "" {
+		return refineByTag(t, b.zodExpression(tag.schema))
+	}
+	refine := func(t goinsp.Type, n node) node {
+		return refineByTag(t, b.validate(t, v, n))
+	}
+	nilable := t.Kind() == reflect.Pointer || t.Kind() == reflect.Map || t.Kind() == reflect.Slice
+	nonnull := tag.nonnull || v.required && nilable
+	modifies := nonnull || tag.readonly || tag.brand != "" || tag.refines() || v.validates()
+	if t.Kind() == reflect.Map && tag.refines() {
+		b.report(fmt.Errorf("constraints can't be applied to fields of map type %v", t))
+		return b.resolveAt(t, a, nodes)
+	}
+	_, named := b.name(t)
+	if !named && (t.Kind() == reflect.Map || t.Kind() == reflect.Slice) && (modifies || tag.hasNilPolicy) {
+		policy := b.nilPolicy(t)
+		if tag.hasNilPolicy {
+			policy = tag.nilPolicy
+		} else if nonnull {
+			policy = NilRejected
+		}
+		elem := func() node { return b.resolveAt(t.Elem(), a.Elem(t), nodes) }
+		if v.elements != nil {
+			elem = func() node { return b.validateElements(t.Elem(), a.Elem(t), *v.elements, nodes) }
+		}
+		n, empty := b.containerSchema(t, elem, nodes)
+		return withNilPolicy(refine(t, n), empty, policy)
+	}
+	if tag.hasNilPolicy {
+		b.report(fmt.Errorf("the nil policy %v of a field can only be applied to unnamed slice and map types, not %v", tag.nilPolicy, t))
+		return b.resolveAt(t, a, nodes)
+	}
+	if !modifies {
+		return b.fieldValueSchema(t, a, tag, nodes)
+	}
+	if t.Kind() != reflect.Pointer {
+		if tag.nonnull {
+			b.report(fmt.Errorf("the nonnull option of a field can only be applied to pointers and unnamed slice and map types, not %v", t))
+			return b.resolveAt(t, a, nodes)
+		}
+		if nonnull {
+			b.reportUnsupportedRule(v, validationRule{name: "required"}, fmt.Sprintf("the declared schema of %v can't be made to reject null", t))
+		}
+		if v.elements != nil {
+			if !named && t.Kind() == reflect.Array {
+				elem := b.at("[]", func() node { return b.validateElements(t.Elem(), a.Elem(t), *v.elements, nodes) })
+				return refine(t, arrayNode{elem: elem}.length(uint(t.Len())))
+			}
+			b.reportUnsupportedRule(v, validationRule{name: "dive"}, fmt.Sprintf("only the elements of unnamed slice, array and map types can be validated, not those of %v", t))
+		}
+		return refine(t, b.fieldValueSchema(t, a, tag, nodes))
+	}
+	if v.elements != nil {
+		b.reportUnsupportedRule(v, validationRule{name: "dive"}, fmt.Sprintf("only the elements of unnamed slice, array and map types can be validated, not those of %v", t))
+	}
+	// The pointer's element is refined, since the nullable schema of the pointer can't be. The validator only requires
+	// pointers not to be nil, so their elements may be zero.
+	v.required = false
+	n := refine(t.Elem(), b.fieldValueSchema(t.Elem(), goinsp.Addressable, tag, nodes))
+	if nonnull {
+		return n
+	}
+	return ensureNullable(n)
+}
+
+// fieldValueSchema returns the node of the given type of a field with the given addressability and gotypes tag,
+// applying its integer policy.
+func (b nodeBuilder) fieldValueSchema(t goinsp.Type, a goinsp.Addressability, tag fieldTag, nodes Resolver[goinsp.Type, node]) node {
+	if tag.hasIntegerPolicy {
+		n, err := b.fieldIntegerSchema(t, tag.integerPolicy)
+		if err == nil {
+			return n
+		}
+		b.report(err)
+	}
+	return b.resolveAt(t, a, nodes)
+}
+
+// nilableSchema returns the node of the given slice or map type, representing the null that encoding/json produces
+// for nil values according to the given policy.
+func (b nodeBuilder) nilableSchema(t goinsp.Type, policy NilPolicy, nodes Resolver[goinsp.Type, node]) node {
+	n, empty := b.containerSchema(t, func() node { return b.resolveAt(t.Elem(), goinsp.Unaddressable.Elem(t), nodes) }, nodes)
+	return withNilPolicy(n, empty, policy)
+}
+
+// nilTransform is the transform replacing the null of nil slices and maps with an empty value, and its equivalent
+// Python function, which Pydantic applies before validating the value.
+type nilTransform struct {
+	typeScript ts.Source
+	python     string
+}
+
+// containerSchema returns the node of the non-nil values of the given slice or map type with elements of the node
+// returned by elem, along with the transform replacing null with an empty value.
+func (b nodeBuilder) containerSchema(t goinsp.Type, elem func() node, nodes Resolver[goinsp.Type, node]) (node, nilTransform) {
+	if t.Kind() == reflect.Map {
+		return recordNode{b.at("[key]", func() node { return nodes.Resolve(t.Key()) }), b.at("[]", elem)}, nilTransform{ts.AsSource(`r => r ?? {}`), `lambda r: {} if r is None else r`}
+	}
+	if t.Elem().Kind() == reflect.Uint8 && marshallerOf(t.Elem(), goinsp.Addressable) == noMarshaller {
+		// Go encodes non-nil byte slices as strings using base64.
+		return stringNode{}, nilTransform{ts.AsSource(`a => a ?? ""`), `lambda a: "" if a is None else a`}
+	}
+	return arrayNode{elem: b.at("[]", elem)}, nilTransform{ts.AsSource(`a => a ?? []`), `lambda a: [] if a is None else a`}
+}
+
+func withNilPolicy(n node, empty nilTransform, policy NilPolicy) node {
+	switch policy {
+	case NilAsEmpty:
+		return transformToOutputOf(nullableNode{n}, n, empty.typeScript, empty.python)
+	case NilAsNull:
+		return nullableNode{n}
+	case NilRejected:
+		return n
+	default:
+		panic(policy)
+	}
+}
+
+// quotedPattern is the pattern of the strings in which encoding/json quotes the JSON of the values of the given type, or
+// of the type it points to, for fields with the `,string` option.
+func quotedPattern(t goinsp.Type) *regexp.Regexp {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return quotedBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return signedDecimal
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return unsignedDecimal
+	case reflect.Float32, reflect.Float64:
+		return quotedFloat
+	default:
+		return quotedString
+	}
+}
+
+var (
+	quotedBool   = regexp.MustCompile(`^(?:true|false)$`)
+	quotedFloat  = regexp.MustCompile(`^-?(?:0|[1-9]\d*)(?:\.\d+)?(?:[eE][-+]?\d+)?$`)
+	quotedString = regexp.MustCompile(`^".*"$`)
+)
+
+// kindSupportsJSONStringFlag tells whether encoding/json applies the `,string` option to values of type t, reporting
+// types of unknown kinds, such as those of erroneous packages.
+func (b nodeBuilder) kindSupportsJSONStringFlag(t goinsp.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr, reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Complex64, reflect.Complex128, reflect.Array, reflect.Chan, reflect.Func, reflect.Interface, reflect.Map:
+		return false
+	case reflect.Pointer:
+		return b.kindSupportsJSONStringFlag(t.Elem())
+	case reflect.Slice:
+		return false
+	case reflect.String:
+		return true
+	case reflect.Struct, reflect.UnsafePointer:
+		return false
+	default:
+		b.report(fmt.Errorf("can't tell whether the ,string option applies to %v of kind %v", t, t.Kind()))
+		return false
+	}
+}
+
+// typeScriptDeclaration is a declaration that is output as TypeScript, such as a zod.SchemaAndTypeDeclaration.
+type typeScriptDeclaration[Self any] interface {
+	declaration[ts.Identifier, Self]
+	TypeScript() ts.Source
+}
+
+// SupportingDeclarations returns the declarations of the given mapper, grouped by Go package, so that declarations come
+// after those they depend on wherever possible.
+func SupportingDeclarations[S any, D typeScriptDeclaration[D]](mapper mapper[goinsp.Type, S, ts.Identifier, D]) ts.Source {
+	return statements(util.Map(orderedDeclarations(mapper), D.TypeScript))
+}
+
+// minSharedSubSchemaLength is the length below which sub-schemas aren't declared as constants, even with
+// WithSharedSubSchemas, since referring to them by name would hardly be shorter, e.g. `z.array(z.string())`.
+const minSharedSubSchemaLength = 64
+
+// statements separates the given declarations by blank lines, preceded by the constants declaring the sub-schemas they
+// share, see WithSharedSubSchemas.
+func statements(declarations []ts.Source) ts.Source {
+	return ts.StatementGroups(1, ts.Hoist(minSharedSubSchemaLength, declarations...)...)
+}
+
+// orderedDeclarations returns the declarations of the given mapper in the order of SupportingDeclarations.
+func orderedDeclarations[S any, D declaration[ts.Identifier, D]](mapper mapper[goinsp.Type, S, ts.Identifier, D]) []D {
+	var declarations []D
+	for _, p := range packagedDeclarations(mapper) {
+		declarations = append(declarations, p.declarations...)
+	}
+	return declarations
+}
+
+// packageDeclarations are the declarations of the types of a Go package.
+type packageDeclarations[D any] struct {
+	path         goinsp.ImportPath
+	declarations []D
+}
+
+// packagedDeclarations returns the declarations of the given mapper grouped by Go package, with packages coming after
+// those they depend on wherever possible, and declarations after those of the same package they depend on.
+func packagedDeclarations[S any, D declaration[ts.Identifier, D]](mapper mapper[goinsp.Type, S, ts.Identifier, D]) []packageDeclarations[D] {
+	type declaration = mappedValue[goinsp.Type, S, ts.Identifier, D]
+
+	declarationsByGoPackage := make(map[goinsp.ImportPath][]declaration)
+	simpleDeclarationsByGoPackage := make(map[goinsp.ImportPath][]declaration)
+	for _, decl := range mapper.declarations {
+		pkg := decl.in.PkgPath()
+		declarationsByGoPackage[pkg] = append(declarationsByGoPackage[pkg], decl)
+		if decl.in.WithoutTypeArguments() == decl.in {
+			simpleDeclarationsByGoPackage[pkg] = append(simpleDeclarationsByGoPackage[pkg], decl)
+		}
+	}
+
+	packagesToOutput := maps.Keys(declarationsByGoPackage)
+	slices.SortFunc(packagesToOutput, func(a, b goinsp.ImportPath) int {
+		group := func(pkg goinsp.ImportPath) uint {
+			if !isStandardLibrary(pkg) {
+				return 1
+			} // privilege standard library types
+			return 0
+		}
+		if r := cmp.Compare(group(a), group(b)); r != 0 {
+			return r
+		}
+		return cmp.Compare(a, b)
+	})
+
+	var packagesInOutputOrder []goinsp.ImportPath
+	ready := func(declarations map[goinsp.ImportPath][]declaration) int {
+		return slices.IndexFunc(packagesToOutput, func(path goinsp.ImportPath) bool {
+			for _, decl := range declarations[path] {
+				for depName := range decl.declaration.info.dependencies {
+					depPath := mapper.declarations[depName].in.PkgPath()
+					if depPath != path && slices.Contains(packagesToOutput, depPath) {
+						return false
+					}
+				}
+			}
+			return true
+		})
+	}
+	for len(packagesToOutput) > 0 {
+		index := ready(declarationsByGoPackage)
+		if index == -1 {
+			index = ready(simpleDeclarationsByGoPackage)
+			if index == -1 {
+				index = 0
+			}
+		}
+		packagesInOutputOrder = append(packagesInOutputOrder, packagesToOutput[index])
+		packagesToOutput = slices.Delete(packagesToOutput, index, index+1)
+	}
+
+	packages := make([]packageDeclarations[D], 0, len(packagesInOutputOrder))
+	for _, p := range packagesInOutputOrder {
+		declarations := declarationsByGoPackage[p]
+		slices.SortFunc(declarations, func(a, b declaration) int {
+			if r := cmp.Compare(a.declaration.info.depth, b.declaration.info.depth); r != 0 {
+				return r
+			}
+			return cmp.Compare(a.declaration.Value.Identifier(), b.declaration.Value.Identifier())
+		})
+		packages = append(packages, packageDeclarations[D]{p, util.Map(declarations, func(d declaration) D { return d.declaration.Value })})
+	}
+	return packages
+}
@@ -0,0 +1,315 @@
+package gozod
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/ts"
+	"github.com/softwaretechnik-berlin/goats/gotypes/util"
+)
+
+// schemaDeclaration is what the declarations of the TypeScript validator libraries other than zod have in common: a
+// schema declared along with the type of its output, or a factory along with a generic type if there are type
+// parameters.
+//
+// Unlike for zod schemas, the types of factories take the types of values rather than schemas as type arguments, e.g.
+// `Page<User>`, so that the plain types of recursive declarations can instantiate them.
+type schemaDeclaration struct {
+	comment string
+	name    ts.Identifier
+	// typeParameters are those of a factory, which is declared instead of a schema if there are any.
+	typeParameters []ts.Identifier
+	node           node
+	// recursive tells whether the schema refers to itself lazily, so that the type of its output can't be inferred.
+	recursive bool
+}
+
+func schemaDeclarationOf[S any](d declared[S]) schemaDeclaration {
+	return schemaDeclaration{d.comment, d.name, d.typeParameters, d.node, false}
+}
+
+func (d schemaDeclaration) Identifier() ts.Identifier { return d.name }
+
+// plainTypeDeclaration declares the plain type of the output of the schema, as an interface if it is an object type.
+func (d schemaDeclaration) plainTypeDeclaration() ts.Source {
+	t, _ := plainType(d.node)
+	if declaresObject(d.node) {
+		return ts.Sourcef(`export interface %s %s`, d.name, t)
+	}
+	return ts.Sourcef(`export type %s = %s;`, d.name, t)
+}
+
+// factoryTypeScript declares a factory returning the given schema, whose type parameters are schemas of the given
+// type, along with the type of the schema's output, which the given function infers from the type of an instance. The
+// type parameters of the output type are the types of values, which schemaOf turns into the types of their schemas.
+func (d schemaDeclaration) factoryTypeScript(anySchema ts.TypeExpression, schema ts.Source, infer, schemaOf func(ts.TypeExpression) ts.TypeExpression) ts.Source {
+	var signature ts.Signature
+	typeParameters := make([]ts.TypeParameter, len(d.typeParameters))
+	typeArguments := make([]ts.TypeExpression, len(d.typeParameters))
+	for i, p := range d.typeParameters {
+		signature.TypeParameters = append(signature.TypeParameters, ts.TypeParameter{Name: p, Constraint: &anySchema})
+		signature.Parameters = append(signature.Parameters, ts.Parameter{Name: parameterName(p), Type: ts.TypeName(p)})
+		typeParameters[i], typeArguments[i] = ts.TypeParameter{Name: p}, schemaOf(ts.TypeName(p))
+	}
+	if d.recursive {
+		// The type of the schema can't be inferred if the factory refers to itself.
+		signature.ReturnType = &anySchema
+	}
+	output := infer(ts.TypeName(ts.Identifier("ReturnType"), ts.TypeQuery(ts.TypeName(d.name, typeArguments...))))
+	return ts.Statements(
+		ts.DocComment(d.comment),
+		ts.Sourcef(`export const %s = %s;`, d.name, ts.ArrowFunction(signature, schema)),
+		ts.Sourcef(`export type %s%s = %s;`, d.name, ts.TypeParameters(typeParameters...), output),
+	)
+}
+
+// withEnumMaps follows the given declaration by the value and label maps of the declared schema if it is an enum, as
+// they are declared along with zod schemas.
+func (d schemaDeclaration) withEnumMaps(declaration ts.Source) ts.Source {
+	enum, ok := structureOf(d.node).(enumNode)
+	if !ok {
+		return declaration
+	}
+	values := make([]ts.Property, len(enum.members))
+	var labels []ts.Property
+	labelled := make(map[any]bool)
+	for i, member := range enum.members {
+		values[i] = ts.Property{Name: member.name, Value: enumLiteral(member.value), Comment: member.comment}
+		if member.label != "" && !labelled[member.value] {
+			labelled[member.value] = true
+			labels = append(labels, ts.Property{Name: fmt.Sprint(member.value), Value: ts.StringLiteral(member.label)})
+		}
+	}
+	valuesName := d.name + "Values"
+	declarations := []ts.Source{declaration, ts.Statements(
+		ts.DocComment(fmt.Sprintf("%s maps the names of the members of %s to their values.", valuesName, d.name)),
+		ts.Sourcef(`export const %s = %s as const;`, valuesName, ts.Object(values...)),
+	)}
+	if len(labels) > 0 {
+		labelsName := d.name + "Labels"
+		declarations = append(declarations, ts.Statements(
+			ts.DocComment(fmt.Sprintf("%s maps the values of %s to their labels.", labelsName, d.name)),
+			ts.Sourcef(`export const %s = %s as const;`, labelsName, ts.Object(labels...)),
+		))
+	}
+	return ts.StatementGroups(1, declarations...)
+}
+
+// declaresObject tells whether the plain type of the schema of n is an object type, which brands don't affect.
+func declaresObject(n node) bool {
+	switch structure := structureOf(n).(type) {
+	case brandedNode:
+		return declaresObject(structure.inner)
+	case objectNode:
+		return true
+	default:
+		return false
+	}
+}
+
+// parameterName is the name of the parameter under which a factory receives the schema for the given type parameter.
+func parameterName(typeParameter ts.Identifier) ts.Identifier {
+	first, size := utf8.DecodeRuneInString(string(typeParameter))
+	return ts.Identifier(string(unicode.ToLower(first)) + string(typeParameter[size:]))
+}
+
+// enumLiteral is the TypeScript literal of the given value of an enum member.
+func enumLiteral(value any) ts.Source {
+	switch value := value.(type) {
+	case string:
+		return ts.StringLiteral(value)
+	case int64:
+		return ts.NumberLiteral(value)
+	case uint64:
+		return ts.NumberLiteral(value)
+	default:
+		panic(fmt.Sprintf("enum value %#v is neither a string nor an integer", value))
+	}
+}
+
+// literal is the TypeScript literal of the value of the given node.
+func literal(n literalNode) ts.Source {
+	if value, ok := n.value.(float64); ok {
+		return ts.NumberLiteral(value)
+	}
+	return ts.StringLiteral(n.value.(string))
+}
+
+// renamedProperties maps the names of the renamed properties of the given object to their output names.
+func renamedProperties(n objectNode) []ts.Property {
+	var renamed []ts.Property
+	for _, p := range n.properties {
+		if p.outputName != "" {
+			renamed = append(renamed, ts.Property{Name: p.name, Value: ts.StringLiteral(string(p.outputName))})
+		}
+	}
+	return renamed
+}
+
+// renamingTransform returns the transform renaming the properties of the parsed objects according to their output
+// names, e.g. `({ user_id: userId, ...rest }) => ({ ...rest, userId })`, if there are any.
+func renamingTransform(n objectNode) (ts.Source, bool) {
+	var pattern, result []ts.Source
+	for _, p := range n.properties {
+		if p.outputName != "" {
+			pattern = append(pattern, ts.Property{Name: p.name, Value: p.outputName}.AsSource())
+			result = append(result, p.outputName)
+		}
+	}
+	if len(pattern) == 0 {
+		return nil, false
+	}
+	rest := ts.AsSource("...rest")
+	return ts.Sourcef("(%s) => (%s)", ts.InlineObject(append(pattern, rest)...), ts.InlineObject(append([]ts.Source{rest}, result...)...)), true
+}
+
+// plainType is the TypeScript type of the values the schema of n produces, without the brands of the library, and
+// tells whether undefined is assignable to it, in which case the properties of the type are optional. Declared
+// schemas stand for the types declared along with them, e.g. `User`.
+func plainType(n node) (t ts.TypeExpression, undefinable bool) {
+	switch n := n.(type) {
+	case anyNode:
+		return ts.AnyType, true
+	case unknownNode, factoryNode:
+		return ts.UnknownType, true
+	case booleanNode:
+		return ts.BooleanType, false
+	case bigIntNode:
+		return ts.BigIntType, false
+	case nullNode:
+		return ts.NullType, false
+	case stringNode:
+		if n.base != nil {
+			return plainType(n.base)
+		}
+		return ts.StringType, false
+	case numberNode:
+		if n.base != nil {
+			return plainType(n.base)
+		}
+		return ts.NumberType, false
+	case arrayNode:
+		if n.base != nil {
+			return plainType(n.base)
+		}
+		elem, _ := plainType(n.elem)
+		return ts.ArrayType(elem), false
+	case recordNode:
+		key, _ := plainType(n.key)
+		value, _ := plainType(n.value)
+		return ts.TypeName(ts.Identifier("Record"), key, value), false
+	case literalNode:
+		return ts.LiteralType(literal(n)), false
+	case stringEnumNode:
+		return ts.UnionType(util.Map(n.values, func(v string) ts.TypeExpression { return ts.LiteralType(ts.StringLiteral(v)) })...), false
+	case enumNode:
+		return ts.UnionType(util.Map(n.values(), func(v any) ts.TypeExpression { return ts.LiteralType(enumLiteral(v)) })...), false
+	case objectNode:
+		return objectPlainType(n), false
+	case unionNode:
+		return unionPlainType(n.members...)
+	case orNode:
+		return unionPlainType(n.inner, n.other)
+	case nullableNode:
+		inner, undefinable := plainType(n.inner)
+		return ts.UnionType(inner, ts.NullType), undefinable
+	case optionalNode:
+		inner, _ := plainType(n.inner)
+		return ts.UnionType(inner, ts.UndefinedType), true
+	case readonlyNode:
+		inner, undefinable := plainType(n.inner)
+		return ts.TypeName(ts.Identifier("Readonly"), inner), undefinable
+	case describedNode:
+		return plainType(n.inner)
+	case brandedNode:
+		return plainType(n.inner)
+	case hoistableNode:
+		return plainType(n.inner)
+	case refinedNode:
+		return plainType(n.inner)
+	case defaultNode:
+		inner, _ := plainType(n.inner)
+		return inner, false
+	case transformNode:
+		switch {
+		case n.outputType != nil:
+			return *n.outputType, false
+		case n.outputOf != nil:
+			output, _ := plainType(n.outputOf)
+			return output, false
+		default:
+			return ts.UnknownType, true
+		}
+	case quotedNode:
+		return plainType(n.inner)
+	case templateNode:
+		inner, _ := plainType(n.inner)
+		return inner, false
+	case largeIntegerNode:
+		return plainType(n.transformed())
+	case typeParameterNode:
+		return ts.TypeName(n.name), false
+	case lazyNode:
+		return ts.TypeName(n.name), false
+	case invocationNode:
+		switch factory := referredFactory(n.factory).(type) {
+		case factoryNode:
+			return instanceType(factory.name, n.arguments), false
+		case lazyNode:
+			return instanceType(factory.name, n.arguments), false
+		default:
+			return ts.UnknownType, true
+		}
+	case reference:
+		target, name := n.referent()
+		t, undefinable := plainType(target)
+		if name != "" {
+			t = ts.TypeName(name)
+		}
+		return t, undefinable
+	default:
+		panic(unknownNodeError(n))
+	}
+}
+
+// objectPlainType is the plain type of the objects of the given node, in which later properties override earlier
+// ones of the same name. Plain types are declared on their own, so they carry the comments of the properties.
+func objectPlainType(n objectNode) ts.TypeExpression {
+	var properties []ts.Property
+	indices := make(map[string]int)
+	for _, p := range n.properties {
+		t, undefinable := plainType(p.node)
+		name := p.name
+		if p.outputName != "" {
+			name = string(p.outputName)
+		}
+		property := ts.Property{Name: name, Value: t, Comment: p.comment, Optional: undefinable}
+		if i, ok := indices[p.name]; ok {
+			properties[i] = property
+			continue
+		}
+		indices[p.name] = len(properties)
+		properties = append(properties, property)
+	}
+	return ts.ObjectType(properties...)
+}
+
+func unionPlainType(members ...node) (ts.TypeExpression, bool) {
+	types := make([]ts.TypeExpression, len(members))
+	undefinable := false
+	for i, member := range members {
+		var memberUndefinable bool
+		types[i], memberUndefinable = plainType(member)
+		undefinable = undefinable || memberUndefinable
+	}
+	return ts.UnionType(types...), undefinable
+}
+
+// instanceType is the plain type of the instances of the factory declared under the given name, e.g. `Page<User>`.
+func instanceType(name ts.Identifier, arguments []node) ts.TypeExpression {
+	return ts.TypeName(name, util.Map(arguments, func(n node) ts.TypeExpression {
+		t, _ := plainType(n)
+		return t
+	})...)
+}
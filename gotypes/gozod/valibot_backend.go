@@ -0,0 +1,299 @@
+package gozod
+
+import (
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
+	"github.com/softwaretechnik-berlin/goats/gotypes/ts"
+	"github.com/softwaretechnik-berlin/goats/gotypes/util"
+	"github.com/softwaretechnik-berlin/goats/gotypes/valibot"
+)
+
+type goToValibotMapper = mapper[goinsp.Type, valibot.Schema, ts.Identifier, ValibotDeclaration]
+
+// NewValibotMapper returns a mapper like NewMapper, whose declarations are Valibot schemas rather than zod schemas,
+// e.g. `export const User = v.object({ … })`.
+//
+// Hand-written schemas, e.g. those of gotypes:schema directives, are reported by Err unless their Valibot equivalents
+// are given with WithValibotSchema, since they would accept any value.
+func NewValibotMapper(options ...Option) goToValibotMapper {
+	c := newConfig(options...)
+	return newBackendMapper(newNodeBuilder(c), valibotBackend{c.valibotSchemas})
+}
+
+// WithValibotSchema gives the Valibot schema of the given type, which NewValibotMapper uses in place of the one derived
+// from the type, e.g. for types whose zod schemas are given with WithSchema.
+func WithValibotSchema(t goinsp.GenType, schema valibot.Schema) Option {
+	return funcOption(func(c *config) {
+		if c.valibotSchemas == nil {
+			c.valibotSchemas = make(map[typeKey]valibot.Schema)
+		}
+		c.valibotSchemas[keyFor(t)] = schema
+	})
+}
+
+// ValibotDeclaration declares a Valibot schema under the name of the declared type, along with the type of its output,
+// e.g. `export const User = v.object({ … }); export type User = v.InferOutput<typeof User>;`.
+//
+// The value and label maps of enums are declared along with them, as for zod schemas.
+type ValibotDeclaration struct {
+	schemaDeclaration
+	schema valibot.Schema
+}
+
+// Recursive returns the declaration of a schema that refers to itself lazily.
+//
+// Since Valibot can't infer the type of such a schema, its plain type is declared and the schema is annotated with it.
+func (d ValibotDeclaration) Recursive() ValibotDeclaration {
+	d.recursive = true
+	return d
+}
+
+func (d ValibotDeclaration) TypeScript() ts.Source {
+	schema := d.schema.TypeScript()
+	genericSchema := ts.Sourcef("%s.GenericSchema", valibot.V)
+	schemaOf := func(t ts.TypeExpression) ts.TypeExpression { return ts.TypeName(genericSchema, ts.UnknownType, t) }
+	if len(d.typeParameters) > 0 {
+		return d.factoryTypeScript(ts.TypeName(genericSchema), schema, func(instance ts.TypeExpression) ts.TypeExpression {
+			return ts.TypeName(ts.Sourcef("%s.InferOutput", valibot.V), instance)
+		}, schemaOf)
+	}
+	var declaration ts.Source
+	if d.recursive {
+		declaration = ts.Statements(
+			ts.DocComment(d.comment),
+			d.plainTypeDeclaration(),
+			ts.Sourcef(`export const %s: %s = %s;`, d.name, schemaOf(ts.TypeName(d.name)), schema),
+		)
+	} else {
+		declaration = ts.Statements(
+			ts.DocComment(d.comment),
+			ts.Sourcef(`export const %s = %s;`, d.name, schema),
+			ts.Sourcef(`export type %s = %s.InferOutput<typeof %s>;`, d.name, valibot.V, d.name),
+		)
+	}
+	return d.withEnumMaps(declaration)
+}
+
+// valibotBackend renders nodes as Valibot schemas.
+type valibotBackend struct {
+	schemas map[typeKey]valibot.Schema
+}
+
+var _ backend[valibot.Schema, ValibotDeclaration] = valibotBackend{}
+
+func (b valibotBackend) name() string   { return "Valibot" }
+func (b valibotBackend) option() string { return "WithValibotSchema" }
+
+func (b valibotBackend) configures(t goinsp.Type) bool {
+	_, ok := lookupConfig(b.schemas, t)
+	return ok
+}
+
+func (b valibotBackend) handWritten(t goinsp.Type, _ Resolver[goinsp.Type, node]) (node, bool) {
+	schema, ok := lookupConfig(b.schemas, t)
+	if !ok {
+		return nil, false
+	}
+	return resolved[valibot.Schema]{schema, unknownNode{}, ""}, true
+}
+
+func (b valibotBackend) zodExpression(string) (node, bool) {
+	return nil, false
+}
+
+func (b valibotBackend) render(n node, nodes Resolver[goinsp.Type, node]) valibot.Schema {
+	return renderValibot(n, nodes)
+}
+
+func (b valibotBackend) reference(name ts.Identifier, _ valibot.Schema) valibot.Schema {
+	return valibot.Expr(name)
+}
+
+func (b valibotBackend) declare(d declared[valibot.Schema]) ValibotDeclaration {
+	return ValibotDeclaration{schemaDeclarationOf(d), d.schema}
+}
+
+// renderValibot renders the given node as a Valibot schema.
+func renderValibot(n node, nodes Resolver[goinsp.Type, node]) valibot.Schema {
+	render := func(n node) valibot.Schema { return renderValibot(n, nodes) }
+	switch n := n.(type) {
+	case anyNode:
+		return valibot.Any()
+	case unknownNode:
+		return valibot.Unknown()
+	case booleanNode:
+		return valibot.Boolean()
+	case bigIntNode:
+		return valibot.BigInt()
+	case nullNode:
+		return valibot.Null()
+	case stringNode:
+		s := valibot.String()
+		if n.base != nil {
+			s = render(n.base)
+		}
+		for _, c := range n.checks {
+			s = valibotStringCheck(s, c)
+		}
+		return s
+	case numberNode:
+		number := valibot.Number()
+		if n.base != nil {
+			number = render(n.base)
+		}
+		for _, c := range n.checks {
+			number = valibotNumberCheck(number, c)
+		}
+		return number
+	case arrayNode:
+		var array valibot.Schema
+		if n.base != nil {
+			array = render(n.base)
+		} else {
+			array = valibot.Array(render(n.elem))
+		}
+		for _, c := range n.checks {
+			switch c.kind {
+			case arrayMinLength:
+				array = array.MinLength(int(c.length))
+			case arrayMaxLength:
+				array = array.MaxLength(int(c.length))
+			case arrayExactLength:
+				array = array.Length(int(c.length))
+			}
+		}
+		return array
+	case recordNode:
+		return valibot.Record(render(n.key), render(n.value))
+	case literalNode:
+		return valibot.Literal(literal(n))
+	case stringEnumNode:
+		return valibot.Picklist(util.Map(n.values, ts.StringLiteral)...)
+	case enumNode:
+		literals := util.Map(n.values(), enumLiteral)
+		if _, isString := n.members[0].value.(string); !isString && len(literals) == 1 {
+			return valibot.Literal(literals[0])
+		}
+		return valibot.Picklist(literals...)
+	case objectNode:
+		object := valibot.Object(util.Map(n.properties, func(p property) valibot.Entry {
+			return valibot.Entry{Name: p.name, Schema: render(p.node), Comment: p.comment}
+		})...)
+		if renaming, ok := renamingTransform(n); ok {
+			object = object.Transform(renaming)
+		}
+		return object
+	case unionNode:
+		members := util.Map(n.members, render)
+		if n.discriminator != "" {
+			return valibot.Variant(n.discriminator, members...)
+		}
+		return valibot.Union(members...)
+	case nullableNode:
+		return render(n.inner).Nullable()
+	case optionalNode:
+		return render(n.inner).Optional()
+	case readonlyNode:
+		return render(n.inner).Readonly()
+	case describedNode:
+		return render(n.inner).Description(n.description)
+	case brandedNode:
+		return render(n.inner).Brand(n.brand)
+	case defaultNode:
+		return render(n.inner).Default(n.value)
+	case hoistableNode:
+		return render(n.inner)
+	case orNode:
+		return render(n.inner).Or(render(n.other))
+	case refinedNode:
+		return render(n.inner).Check(n.check.typeScript)
+	case transformNode:
+		return render(n.inner).Transform(n.fn(sources(nodes, render, valibot.Schema.TypeScript)))
+	case quotedNode:
+		if n.parse {
+			return valibot.String().Transform(ts.AsSource("s => JSON.parse(s)")).PipeTo(render(n.inner))
+		}
+		return render(n.inner)
+	case templateNode:
+		// The strings are checked against the template's pattern first, so the transformation can rely on them
+		// matching.
+		return valibot.String().Regex(n.pattern).Transform(ts.Sourcef(`(s) => {
+    const match = %s.exec(s)!;
+    return %s;
+}`, ts.RegexLiteral(n.pattern), n.match(func(n node, value ts.Source) ts.Source { return valibot.Parse(render(n), value) })))
+	case largeIntegerNode:
+		return render(n.transformed())
+	case typeParameterNode:
+		return valibot.Expr(parameterName(n.name))
+	case factoryNode:
+		return valibot.Expr(n.name)
+	case lazyNode:
+		return valibot.Lazy(n.name)
+	case invocationNode:
+		arguments := util.Map(n.arguments, func(n node) ts.Source { return render(n).TypeScript() })
+		if lazy, ok := referredFactory(n.factory).(lazyNode); ok {
+			// The factory is still being declared, so the invocation has to be deferred.
+			return valibot.Lazy(ts.InvokeFunction(lazy.name, arguments...))
+		}
+		return valibot.Expr(ts.InvokeFunction(render(n.factory).TypeScript(), arguments...))
+	case resolved[valibot.Schema]:
+		return n.schema
+	default:
+		panic(unknownNodeError(n))
+	}
+}
+
+func valibotStringCheck(s valibot.Schema, c stringCheck) valibot.Schema {
+	switch c.kind {
+	case stringMin:
+		return s.MinLength(c.length)
+	case stringMax:
+		return s.MaxLength(c.length)
+	case stringLength:
+		return s.Length(c.length)
+	case stringEmail:
+		return s.Email()
+	case stringURL:
+		return s.URL()
+	case stringUUID:
+		return s.UUID()
+	case stringDatetime:
+		return s.IsoTimestamp()
+	case stringIP:
+		return s.IP()
+	case stringIPv4:
+		return s.IPv4()
+	case stringIPv6:
+		return s.IPv6()
+	case stringIncludes:
+		return s.Includes(c.text)
+	case stringStartsWith:
+		return s.StartsWith(c.text)
+	case stringEndsWith:
+		return s.EndsWith(c.text)
+	case stringRegex:
+		return s.Regex(c.pattern)
+	default:
+		panic(c.kind)
+	}
+}
+
+func valibotNumberCheck(n valibot.Schema, c numberCheck) valibot.Schema {
+	switch c.kind {
+	case numberInt:
+		return n.Integer()
+	case numberNonNegative:
+		return n.MinValue(ts.NumberLiteral(0))
+	case numberMin:
+		return n.MinValue(ts.NumberLiteral(c.bound))
+	case numberMax:
+		return n.MaxValue(ts.NumberLiteral(c.bound))
+	case numberGt:
+		return n.GtValue(ts.NumberLiteral(c.bound))
+	case numberLt:
+		return n.LtValue(ts.NumberLiteral(c.bound))
+	case numberSafe:
+		return n.MinValue(ts.AsSource("Number.MIN_SAFE_INTEGER")).MaxValue(ts.AsSource("Number.MAX_SAFE_INTEGER"))
+	default:
+		panic(c.kind)
+	}
+}
@@ -0,0 +1,469 @@
+package gozod
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/exp/constraints"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
+	"github.com/softwaretechnik-berlin/goats/gotypes/pydantic"
+	"github.com/softwaretechnik-berlin/goats/gotypes/ts"
+	"github.com/softwaretechnik-berlin/goats/gotypes/util"
+)
+
+// WithValidateTags refines the schemas of struct fields according to their `validate` tags, as used by
+// github.com/go-playground/validator, so that clients validate values like servers do.
+//
+// The rules that can't be represented in the schemas, e.g. custom ones, are reported by the mapper's Err, unless
+// they're among the given ignored rules.
+//
+// Note that zod measures the length of strings in UTF-16 code units, whereas the validator counts runes, so that for
+// strings with characters outside the Basic Multilingual Plane, the schemas' max and len are stricter, and min laxer.
+func WithValidateTags(ignoredRules ...string) Option {
+	return funcOption(func(c *config) {
+		c.validateTags = true
+		if c.ignoredValidateRules == nil {
+			c.ignoredValidateRules = make(map[string]bool)
+		}
+		for _, rule := range ignoredRules {
+			c.ignoredValidateRules[rule] = true
+		}
+	})
+}
+
+// validation is the parsed validate tag of a struct field.
+type validation struct {
+	// tag is the validate tag.
+	tag                 string
+	required, omitempty bool
+	rules               []validationRule
+	// elements validates the elements of slices, arrays and maps, i.e. it holds the rules following `dive`.
+	elements *validation
+}
+
+type validationRule struct {
+	name, param string
+}
+
+func (r validationRule) String() string {
+	if r.param == "" {
+		return r.name
+	}
+	return r.name + "=" + r.param
+}
+
+// parseValidateTag parses the validate tag of a field, unless WithValidateTags wasn't given.
+func (b nodeBuilder) parseValidateTag(tag string) validation {
+	v := validation{tag: tag}
+	if !b.validateTags || tag == "" || tag == "-" {
+		return v
+	}
+	current := &v
+	inKeys := false
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		// The validator escapes commas and pipes in parameters.
+		param = strings.NewReplacer("0x2C", ",", "0x7C", "|").Replace(param)
+		switch {
+		case inKeys:
+			inKeys = name != "endkeys"
+		case name == "keys":
+			b.reportUnsupportedRule(*current, validationRule{name: name}, "map keys can't be validated")
+			inKeys = true
+		case name == "dive":
+			current.elements = &validation{tag: tag}
+			current = current.elements
+		case name == "required":
+			current.required = true
+		case name == "omitempty":
+			current.omitempty = true
+		case name == "omitnil":
+			// Nil is represented by null, which the schemas accept unless the value is required anyway.
+		default:
+			current.rules = append(current.rules, validationRule{name, param})
+		}
+	}
+	return v
+}
+
+// validates tells whether v refines the schemas it is applied to.
+func (v validation) validates() bool {
+	return v.required || len(v.rules) > 0 || v.elements != nil
+}
+
+func (b nodeBuilder) reportUnsupportedRule(v validation, rule validationRule, reason string) {
+	if b.ignoredValidateRules[rule.name] {
+		return
+	}
+	b.report(fmt.Errorf("the rule %v in the validate tag %q isn't supported: %s; represent it with the field's gotypes tag or ignore it with WithValidateTags", rule, v.tag, reason))
+}
+
+// validate refines the given node of the non-nil values of type t according to v, reporting the rules it can't
+// represent.
+//
+// The rules following `dive` are applied by the caller, which builds the nodes of the elements.
+func (b nodeBuilder) validate(t goinsp.Type, v validation, n node) node {
+	unrefined := n
+	refined := false
+	var oneOf []validationRule
+	applied := make([]validationRule, 0, len(v.rules))
+	for _, rule := range v.rules {
+		if rule.name == "oneof" {
+			// The values are restricted last, so that they don't discard the other rules, see restrictToOneOf.
+			oneOf = append(oneOf, rule)
+			continue
+		}
+		validated, err := applyValidationRule(t, rule, n)
+		if err != nil {
+			b.reportUnsupportedRule(v, rule, err.Error())
+			continue
+		}
+		n, refined = validated, true
+		applied = append(applied, rule)
+	}
+	// The zero value is rejected after the other rules, whose schemas its refinement would hide, and only if none of
+	// the values the oneof rules restrict the schema to, which exclude it if required, were accepted.
+	restricted := false
+	for _, rule := range oneOf {
+		validated, err := restrictToOneOf(t, v.required, applied, rule, unrefined, n)
+		if err != nil {
+			b.reportUnsupportedRule(v, rule, err.Error())
+			continue
+		}
+		n, refined, restricted = validated, true, true
+		applied = append(applied, rule)
+	}
+	if v.required && !restricted {
+		var required bool
+		n, required = requireNonZero(t, n)
+		refined = refined || required
+	}
+	if v.omitempty && refined {
+		// The validator doesn't validate zero values of fields with the omitempty rule.
+		if zero, ok := zeroLiteral(t); ok {
+			n = orNode{n, zero}
+		}
+	}
+	return n
+}
+
+// validateElements returns the node of the elements of type t, refined according to v, the rules following `dive`.
+func (b nodeBuilder) validateElements(t goinsp.Type, a goinsp.Addressability, v validation, nodes Resolver[goinsp.Type, node]) node {
+	if _, named := b.name(t); v.elements != nil || !named && (t.Kind() == reflect.Slice || t.Kind() == reflect.Map) && v.validates() {
+		b.reportUnsupportedRule(v, validationRule{name: "dive"}, fmt.Sprintf("the elements of type %v can't be validated", t))
+		return b.resolveAt(t, a, nodes)
+	}
+	if t.Kind() == reflect.Pointer {
+		// The validator only requires pointers not to be nil, so their elements may be zero.
+		elem := v
+		elem.required = false
+		n := b.validate(t.Elem(), elem, b.resolveAt(t.Elem(), goinsp.Addressable, nodes))
+		if v.required {
+			return n
+		}
+		return ensureNullable(n)
+	}
+	return b.validate(t, v, b.resolveAt(t, a, nodes))
+}
+
+// requireNonZero refines the given node of type t to reject the zero value, as the validator's required rule does.
+// Nil pointers, slices and maps are rejected by the caller instead.
+func requireNonZero(t goinsp.Type, n node) (node, bool) {
+	switch t.Kind() {
+	case reflect.String:
+		if s, ok := asString(n); ok {
+			return s.min(1), true
+		}
+		return refinedNode{n, refinement{ts.AsSource(`s => s !== ""`), `lambda s: s != ""`}}, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		// The loose comparison also rejects the zero bigints and decimal strings produced by integer policies.
+		return refinedNode{n, refinement{ts.AsSource(`n => n != 0`), `lambda n: n != 0 and n != "0"`}}, true
+	case reflect.Bool:
+		return refinedNode{n, refinement{ts.AsSource(`b => b`), `lambda b: b`}}, true
+	case reflect.Interface:
+		return refinedNode{n, refinement{ts.AsSource(`v => v != null`), `lambda v: v is not None`}}, true
+	default:
+		return n, false
+	}
+}
+
+func zeroLiteral(t goinsp.Type) (node, bool) {
+	switch t.Kind() {
+	case reflect.String:
+		return literalNode{""}, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return literalNode{float64(0)}, true
+	default:
+		return nil, false
+	}
+}
+
+// applyValidationRule refines the given node of type t according to the given rule.
+func applyValidationRule(t goinsp.Type, rule validationRule, n node) (node, error) {
+	switch structureOf(n).(type) {
+	case stringNode:
+		s, _ := asString(n)
+		return applyStringRule(rule, s)
+	case numberNode:
+		number, _ := asNumber(n)
+		return applyNumberRule(rule, number)
+	case arrayNode, recordNode:
+		if t.Kind() == reflect.Map {
+			return nil, fmt.Errorf("the number of entries of map type %v can't be validated", t)
+		}
+		a, _ := asArray(n)
+		return applyArrayRule(rule, a)
+	default:
+		return nil, fmt.Errorf("the schema of %v is neither a string, number nor array schema, e.g. because it is declared", t)
+	}
+}
+
+// validationPatterns are the regular expressions the validator uses for the rules that don't take parameters.
+var validationPatterns = map[string]*regexp.Regexp{
+	"alpha":       regexp.MustCompile(`^[a-zA-Z]+$`),
+	"alphanum":    regexp.MustCompile(`^[a-zA-Z0-9]+$`),
+	"numeric":     regexp.MustCompile(`^[-+]?[0-9]+(?:\.[0-9]+)?$`),
+	"number":      regexp.MustCompile(`^[0-9]+$`),
+	"hexadecimal": regexp.MustCompile(`^(0[xX])?[0-9a-fA-F]+$`),
+	"e164":        regexp.MustCompile(`^\+[1-9]?[0-9]{7,14}$`),
+	"uuid":        regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`),
+	"uuid3":       regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-3[0-9a-f]{3}-[0-9a-f]{4}-[0-9a-f]{12}$`),
+	"uuid4":       regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`),
+	"uuid5":       regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`),
+}
+
+var httpURL = regexp.MustCompile(`^https?://`)
+
+var validationFormats = map[string]func(stringNode) stringNode{
+	"email":    stringNode.email,
+	"url":      stringNode.url,
+	"http_url": func(s stringNode) stringNode { return s.url().regex(httpURL) },
+	"ip":       stringNode.ip,
+	"ipv4":     stringNode.ipv4,
+	"ipv6":     stringNode.ipv6,
+}
+
+func applyStringRule(rule validationRule, s stringNode) (node, error) {
+	if pattern, ok := validationPatterns[rule.name]; ok && rule.param == "" {
+		return s.regex(pattern), nil
+	}
+	if format, ok := validationFormats[rule.name]; ok && rule.param == "" {
+		return format(s), nil
+	}
+	switch rule.name {
+	case "contains":
+		return s.includes(rule.param), nil
+	case "startswith":
+		return s.startsWith(rule.param), nil
+	case "endswith":
+		return s.endsWith(rule.param), nil
+	}
+	length, err := lengthParam(rule)
+	if err != nil {
+		return nil, err
+	}
+	switch rule.name {
+	case "min", "gte":
+		return s.min(int(length)), nil
+	case "max", "lte":
+		return s.max(int(length)), nil
+	case "len":
+		return s.length(int(length)), nil
+	case "gt":
+		return s.min(int(length) + 1), nil
+	case "lt":
+		if length == 0 {
+			return nil, fmt.Errorf("no string is shorter than 0")
+		}
+		return s.max(int(length) - 1), nil
+	}
+	panic(rule)
+}
+
+func applyNumberRule(rule validationRule, n numberNode) (node, error) {
+	bound, err := strconv.ParseFloat(rule.param, 64)
+	if err != nil {
+		return nil, fmt.Errorf("it doesn't constrain numbers by a number")
+	}
+	switch rule.name {
+	case "min", "gte":
+		return n.min(bound), nil
+	case "max", "lte":
+		return n.max(bound), nil
+	case "gt":
+		return n.gt(bound), nil
+	case "lt":
+		return n.lt(bound), nil
+	default:
+		return nil, fmt.Errorf("numbers can only be validated by min, max, gt, gte, lt, lte and oneof")
+	}
+}
+
+// restrictToOneOf restricts the node of type t, which is the unrefined one refined according to the applied rules,
+// to the values of the given oneof rule.
+//
+// Unless applied contains rules that can't be evaluated here, such as email, the values that don't satisfy them are
+// dropped, so that an enum or a union of literals replaces the refined node. Otherwise, the values are checked by a
+// refinement.
+func restrictToOneOf(t goinsp.Type, required bool, applied []validationRule, rule validationRule, unrefined, refined node) (node, error) {
+	values := oneOfValues(rule.param)
+	if len(values) == 0 {
+		return nil, fmt.Errorf("it has no values")
+	}
+	switch structureOf(unrefined).(type) {
+	case stringNode:
+		var allowed []string
+		for _, value := range values {
+			satisfied, evaluable := satisfiesStringRules(value, required, applied)
+			if !evaluable {
+				if required {
+					values = slices.DeleteFunc(values, func(v string) bool { return v == "" })
+				}
+				return refinedNode{refined, refinement{
+					ts.Sourcef("v => %s.includes(v)", ts.Array(util.Map(values, ts.StringLiteral)...)),
+					"lambda v: v in " + pydantic.Repr(util.Map(values, func(v string) any { return v })),
+				}}, nil
+			}
+			if satisfied {
+				allowed = append(allowed, value)
+			}
+		}
+		if len(allowed) == 0 {
+			return nil, fmt.Errorf("none of its values satisfies the other rules")
+		}
+		return stringEnumNode{allowed}, nil
+	case numberNode:
+		var literals []node
+		for _, value := range values {
+			number, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%q isn't a number", value)
+			}
+			if satisfiesNumberRules(number, required, applied) {
+				literals = append(literals, literalNode{number})
+			}
+		}
+		switch len(literals) {
+		case 0:
+			return nil, fmt.Errorf("none of its values satisfies the other rules")
+		case 1:
+			return literals[0], nil
+		}
+		return unionNode{members: literals}, nil
+	default:
+		return nil, fmt.Errorf("the schema of %v is neither a string nor number schema, e.g. because it is declared", t)
+	}
+}
+
+// satisfiesStringRules tells whether the given string satisfies the given rules, as far as they're evaluable here,
+// measuring lengths in runes like the validator does.
+func satisfiesStringRules(s string, required bool, rules []validationRule) (satisfied, evaluable bool) {
+	satisfied = !required || s != ""
+	for _, rule := range rules {
+		if pattern, ok := validationPatterns[rule.name]; ok && rule.param == "" {
+			satisfied = satisfied && pattern.MatchString(s)
+			continue
+		}
+		switch rule.name {
+		case "contains":
+			satisfied = satisfied && strings.Contains(s, rule.param)
+		case "startswith":
+			satisfied = satisfied && strings.HasPrefix(s, rule.param)
+		case "endswith":
+			satisfied = satisfied && strings.HasSuffix(s, rule.param)
+		default:
+			length, err := lengthParam(rule)
+			if err != nil {
+				return false, false
+			}
+			satisfied = satisfied && compare(rule.name, uint(utf8.RuneCountInString(s)), length)
+		}
+	}
+	return satisfied, true
+}
+
+// satisfiesNumberRules tells whether the given number satisfies the given rules.
+func satisfiesNumberRules(n float64, required bool, rules []validationRule) bool {
+	satisfied := !required || n != 0
+	for _, rule := range rules {
+		bound, _ := strconv.ParseFloat(rule.param, 64)
+		satisfied = satisfied && compare(rule.name, n, bound)
+	}
+	return satisfied
+}
+
+// compare tells whether the given value satisfies the rule with the given name bounding it by bound.
+func compare[N constraints.Integer | constraints.Float](name string, value, bound N) bool {
+	switch name {
+	case "min", "gte":
+		return value >= bound
+	case "max", "lte":
+		return value <= bound
+	case "len":
+		return value == bound
+	case "gt":
+		return value > bound
+	case "lt":
+		return value < bound
+	default:
+		panic(name)
+	}
+}
+
+func applyArrayRule(rule validationRule, a arrayNode) (node, error) {
+	length, err := lengthParam(rule)
+	if err != nil {
+		return nil, err
+	}
+	switch rule.name {
+	case "min", "gte":
+		return a.min(length), nil
+	case "max", "lte":
+		return a.max(length), nil
+	case "len":
+		return a.length(length), nil
+	case "gt":
+		return a.min(length + 1), nil
+	case "lt":
+		if length == 0 {
+			return nil, fmt.Errorf("no array is shorter than 0")
+		}
+		return a.max(length - 1), nil
+	}
+	panic(rule)
+}
+
+// lengthParam returns the length given by a rule constraining the length of strings or arrays.
+func lengthParam(rule validationRule) (uint, error) {
+	switch rule.name {
+	case "min", "max", "len", "gt", "gte", "lt", "lte":
+	default:
+		return 0, fmt.Errorf("strings and arrays can't be validated by it")
+	}
+	length, err := strconv.ParseUint(rule.param, 10, 0)
+	if err != nil {
+		return 0, fmt.Errorf("it doesn't constrain the length by a non-negative integer")
+	}
+	return uint(length), nil
+}
+
+// oneOfValue matches the values of the oneof rule, which are separated by spaces and may be quoted with single quotes.
+var oneOfValue = regexp.MustCompile(`'[^']*'|\S+`)
+
+func oneOfValues(param string) []string {
+	values := oneOfValue.FindAllString(param, -1)
+	for i, value := range values {
+		values[i] = strings.Trim(value, "'")
+	}
+	return values
+}
@@ -0,0 +1,324 @@
+package gozod
+
+import (
+	"github.com/softwaretechnik-berlin/goats/gotypes/goinsp"
+	"github.com/softwaretechnik-berlin/goats/gotypes/ts"
+	"github.com/softwaretechnik-berlin/goats/gotypes/util"
+	"github.com/softwaretechnik-berlin/goats/gotypes/zod"
+)
+
+type goToZodMapper = mapper[goinsp.Type, zod.ZodType, ts.Identifier, zod.SchemaAndTypeDeclaration]
+
+func NewMapper(options ...Option) goToZodMapper {
+	c := newConfig(options...)
+	return newZodMapper(newNodeBuilder(c), zodLibraryOf(c), func(d zod.SchemaAndTypeDeclaration) zod.SchemaAndTypeDeclaration { return d })
+}
+
+func NewMapperWithSupport(options ...Option) goToZodMapper {
+	return NewMapper(options...)
+}
+
+type goToTypesMapper = mapper[goinsp.Type, zod.ZodType, ts.Identifier, zod.TypeDeclaration]
+
+// NewTypesMapper returns a mapper like NewMapper, whose declarations are plain TypeScript types rather than zod schemas,
+// e.g. `export interface User { … }`. They don't depend on zod, so zod's brands are left out of them, and since nothing
+// transforms the values, they are the types of the values the schemas accept, i.e. of the JSON on the wire.
+func NewTypesMapper(options ...Option) goToTypesMapper {
+	c := newConfig(options...)
+	b := newNodeBuilder(c)
+	b.genericTypes = true
+	return newZodMapper(b, zodLibraryOf(c), zod.SchemaAndTypeDeclaration.TypeOnly)
+}
+
+func newZodMapper[D declaration[ts.Identifier, D]](b nodeBuilder, library zodLibrary, as func(zod.SchemaAndTypeDeclaration) D) mapper[goinsp.Type, zod.ZodType, ts.Identifier, D] {
+	return newBackendMapper(b, zodBackend[D]{library, b.explicitTypes, as})
+}
+
+// zodBackend renders nodes as zod schemas, which it declares as converted by as, e.g. into plain TypeScript types.
+type zodBackend[D any] struct {
+	zodLibrary
+	explicitTypes bool
+	as            func(zod.SchemaAndTypeDeclaration) D
+}
+
+var _ backend[zod.ZodType, zod.TypeDeclaration] = zodBackend[zod.TypeDeclaration]{}
+
+func (b zodBackend[D]) render(n node, nodes Resolver[goinsp.Type, node]) zod.ZodType {
+	return renderZod(n, nodes)
+}
+
+func (b zodBackend[D]) reference(name ts.Identifier, schema zod.ZodType) zod.ZodType {
+	return schema.DeclaredAs(name)
+}
+
+func (b zodBackend[D]) declare(d declared[zod.ZodType]) D {
+	if len(d.typeParameters) > 0 {
+		return b.as(zod.NewFactoryDeclaration(d.comment, d.name, d.typeParameters, d.schema))
+	}
+	declaration := zod.NewSchemaAndTypeDeclaration(d.comment, d.name, d.schema)
+	if b.explicitTypes {
+		declaration = declaration.Explicit()
+	}
+	return b.as(declaration)
+}
+
+// zodLibrary is zod, in which schemas are hand-written with WithSchema.
+type zodLibrary struct {
+	schemas map[typeKey]func(resolver Resolver[goinsp.Type, zod.ZodType]) zod.ZodType
+}
+
+func zodLibraryOf(c config) zodLibrary {
+	return zodLibrary{c.schemas}
+}
+
+func (l zodLibrary) name() string   { return "zod" }
+func (l zodLibrary) option() string { return "WithSchema" }
+
+func (l zodLibrary) configures(t goinsp.Type) bool {
+	_, ok := lookupConfig(l.schemas, t)
+	return ok
+}
+
+func (l zodLibrary) handWritten(t goinsp.Type, nodes Resolver[goinsp.Type, node]) (node, bool) {
+	schema, ok := lookupConfig(l.schemas, t)
+	if !ok {
+		return nil, false
+	}
+	s := schema(resolverFunc[goinsp.Type, zod.ZodType](func(t goinsp.Type) zod.ZodType { return renderZod(nodes.Resolve(t), nodes) }))
+	return resolved[zod.ZodType]{s, zodStructure(s), ""}, true
+}
+
+func (l zodLibrary) zodExpression(expr string) (node, bool) {
+	return resolved[zod.ZodType]{zod.ZodTypeText(expr), unknownNode{}, ""}, true
+}
+
+// zodStructure returns the node telling what kind of schema the given hand-written zod schema is, as far as it is known,
+// so that it can be refined like the schemas built from Go types.
+func zodStructure(s zod.ZodType) node {
+	switch s := s.(type) {
+	case zod.ZodString:
+		return stringNode{}
+	case zod.ZodNumber:
+		number := numberNode{}
+		if s.IsInt() {
+			number = number.int()
+		}
+		if s.IsNonNegative() {
+			number = number.nonNegative()
+		}
+		return number
+	case zod.ZodArray:
+		return arrayNode{}
+	case zod.ZodObject:
+		return objectNode{util.Map(s.Shape(), func(p zod.ShapeProperty) property {
+			return property{name: p.Name, node: resolved[zod.ZodType]{p.Schema, zodStructure(p.Schema), ""}, comment: p.Comment, outputName: p.OutputName}
+		})}
+	case zod.ZodEnum:
+		return enumNode{util.Map(s.Members(), func(m zod.EnumMember) enumMember {
+			return enumMember{m.Name, m.Value, m.Comment, m.Label}
+		})}
+	case zod.ZodBranded:
+		// Nullable and optional schemas can be unwrapped like branded ones.
+		inner := resolved[zod.ZodType]{s.Unwrap(), zodStructure(s.Unwrap()), ""}
+		if _, ok := zod.StripNullable(s); ok {
+			return nullableNode{inner}
+		}
+		return brandedNode{inner, ""}
+	default:
+		return unknownNode{}
+	}
+}
+
+// renderZod renders the given node as a zod schema.
+func renderZod(n node, nodes Resolver[goinsp.Type, node]) zod.ZodType {
+	render := func(n node) zod.ZodType { return renderZod(n, nodes) }
+	switch n := n.(type) {
+	case anyNode:
+		return zod.Any()
+	case unknownNode:
+		return zod.Unknown()
+	case booleanNode:
+		return zod.Boolean()
+	case bigIntNode:
+		return zod.BigInt()
+	case nullNode:
+		return zod.Null()
+	case stringNode:
+		s := zod.String()
+		if n.base != nil {
+			s = render(n.base).(zod.ZodString)
+		}
+		for _, c := range n.checks {
+			s = zodStringCheck(s, c)
+		}
+		return s
+	case numberNode:
+		number := zod.Number()
+		if n.base != nil {
+			number = render(n.base).(zod.ZodNumber)
+		}
+		for _, c := range n.checks {
+			number = zodNumberCheck(number, c)
+		}
+		return number
+	case arrayNode:
+		var array zod.ZodArray
+		if n.base != nil {
+			array = render(n.base).(zod.ZodArray)
+		} else {
+			array = zod.Array(render(n.elem))
+		}
+		for _, c := range n.checks {
+			switch c.kind {
+			case arrayMinLength:
+				array = array.Min(c.length)
+			case arrayMaxLength:
+				array = array.Max(c.length)
+			case arrayExactLength:
+				array = array.Length(c.length)
+			}
+		}
+		return array
+	case recordNode:
+		return zod.Record(render(n.key), render(n.value))
+	case literalNode:
+		if value, ok := n.value.(float64); ok {
+			return zod.NumberLiteral(value)
+		}
+		return zod.Literal(n.value.(string))
+	case stringEnumNode:
+		return zod.Enum(n.values...)
+	case enumNode:
+		return zod.EnumOf(util.Map(n.members, func(m enumMember) zod.EnumMember {
+			return zod.EnumMember{Name: m.name, Value: m.value, Comment: m.comment, Label: m.label}
+		})...)
+	case objectNode:
+		return zod.Object(util.Map(n.properties, func(p property) zod.ShapeProperty {
+			return zod.ShapeProperty{Name: p.name, Schema: render(p.node), Comment: p.comment, OutputName: p.outputName}
+		})...)
+	case unionNode:
+		members := util.Map(n.members, render)
+		if n.discriminator != "" {
+			return zod.DiscriminatedUnion(n.discriminator, members...)
+		}
+		return zod.Union(members...)
+	case nullableNode:
+		return render(n.inner).Nullable()
+	case optionalNode:
+		return render(n.inner).Optional()
+	case readonlyNode:
+		return render(n.inner).Readonly()
+	case describedNode:
+		return render(n.inner).Describe(n.description)
+	case brandedNode:
+		return render(n.inner).Brand(n.brand)
+	case defaultNode:
+		return render(n.inner).Default(n.value)
+	case hoistableNode:
+		return render(n.inner).Hoistable()
+	case orNode:
+		return render(n.inner).Or(render(n.other))
+	case refinedNode:
+		return render(n.inner).Refine(n.check.typeScript)
+	case transformNode:
+		fn := n.fn(sources(nodes, render, zod.ZodType.TypeScript))
+		switch {
+		case n.outputType != nil:
+			return render(n.inner).TransformTo(*n.outputType, fn)
+		case n.outputOf != nil:
+			return render(n.inner).TransformToOutputOf(render(n.outputOf), fn)
+		default:
+			return render(n.inner).Transform(fn)
+		}
+	case quotedNode:
+		if n.parse {
+			return zod.String().Transformf("s => JSON.parse(s)").Pipe(render(n.inner))
+		}
+		return render(n.inner)
+	case templateNode:
+		return zodTemplate(n, render)
+	case largeIntegerNode:
+		return render(n.transformed())
+	case typeParameterNode:
+		return zod.TypeParameter(n.name)
+	case factoryNode:
+		return zod.Factory(n.name)
+	case lazyNode:
+		return zod.Lazy(n.name)
+	case invocationNode:
+		return zod.Invoke(render(n.factory), util.Map(n.arguments, render)...)
+	case resolved[zod.ZodType]:
+		return n.schema
+	default:
+		panic(unknownNodeError(n))
+	}
+}
+
+func zodStringCheck(s zod.ZodString, c stringCheck) zod.ZodString {
+	switch c.kind {
+	case stringMin:
+		return s.Min(c.length)
+	case stringMax:
+		return s.Max(c.length)
+	case stringLength:
+		return s.Length(c.length)
+	case stringEmail:
+		return s.Email()
+	case stringURL:
+		return s.URL()
+	case stringUUID:
+		return s.UUID()
+	case stringDatetime:
+		return s.DatetimeWithOffset()
+	case stringIP:
+		return s.IP()
+	case stringIPv4:
+		return s.IPv4()
+	case stringIPv6:
+		return s.IPv6()
+	case stringIncludes:
+		return s.Includes(c.text)
+	case stringStartsWith:
+		return s.StartsWith(c.text)
+	case stringEndsWith:
+		return s.EndsWith(c.text)
+	case stringRegex:
+		return s.Regex(c.pattern)
+	default:
+		panic(c.kind)
+	}
+}
+
+func zodNumberCheck(n zod.ZodNumber, c numberCheck) zod.ZodNumber {
+	switch c.kind {
+	case numberInt:
+		return n.Int()
+	case numberNonNegative:
+		return n.NonNegative()
+	case numberMin:
+		return n.Min(c.bound)
+	case numberMax:
+		return n.Max(c.bound)
+	case numberGt:
+		return n.Gt(c.bound)
+	case numberLt:
+		return n.Lt(c.bound)
+	case numberSafe:
+		return n.Safe()
+	default:
+		panic(c.kind)
+	}
+}
+
+// zodTemplate renders the schema parsing strings of the template of the given node into values of its inner schema.
+func zodTemplate(n templateNode, render func(node) zod.ZodType) zod.ZodType {
+	return zod.String().TransformToOutputOf(render(n.inner), ts.Sourcef(`(s, ctx) => {
+    const re = %s;
+    const match = re.exec(s);
+    if (!match) {
+        ctx.addIssue({ code: %s.ZodIssueCode.custom, message: "expected string of the form %s matching " + re });
+        return z.NEVER;
+    }
+    return %s;
+}`, ts.RegexLiteral(n.pattern), ts.ImportedName("zod", "z"), ts.StringEscape(ts.StringLiteral(n.template).String()), n.match(func(n node, value ts.Source) ts.Source { return render(n).Parse(value) })))
+}
@@ -0,0 +1,23 @@
+// Package formats holds the regular expressions with which backends lacking built-in checks for string formats
+// check them.
+//
+// The expressions are approximations in the case of IP addresses and URLs. They only use syntax that Go, JavaScript,
+// Python and Rust regular expressions share.
+package formats
+
+import "regexp"
+
+var (
+	UUID               = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	Email              = regexp.MustCompile(`^[^\s@]+@[^\s@]+\.[^\s@]+$`)
+	URL                = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.\-]*:[^\s]*$`)
+	DatetimeWithOffset = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}(?::\d{2}(?:\.\d+)?)?(?:Z|[+-]\d{2}:\d{2})$`)
+	IPv4               = regexp.MustCompile(`^` + ipv4Body + `$`)
+	IPv6               = regexp.MustCompile(`^` + ipv6Body + `$`)
+	IP                 = regexp.MustCompile(`^(?:` + ipv4Body + `|` + ipv6Body + `)$`)
+)
+
+const (
+	ipv4Body = `(?:(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\.){3}(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)`
+	ipv6Body = `(?:[0-9a-fA-F]{0,4}:){2,7}(?:[0-9a-fA-F]{0,4}|` + ipv4Body + `)`
+)
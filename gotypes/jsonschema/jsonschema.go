@@ -0,0 +1,266 @@
+// Package jsonschema models JSON Schema (draft 2020-12) documents, see https://json-schema.org/draft/2020-12.
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+)
+
+// Dialect is the URI of the JSON Schema dialect of the documents modelled by this package.
+const Dialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema. Its zero value accepts any JSON value.
+//
+// Schemas are values: the functions and methods of this package return modified copies rather than modifying the
+// schemas they are given.
+type Schema struct {
+	Schema      string `json:"$schema,omitempty"`
+	Ref         string `json:"$ref,omitempty"`
+	Description string `json:"description,omitempty"`
+	Type        Types  `json:"type,omitempty"`
+	Enum        []any  `json:"enum,omitempty"`
+	// Const is the only value the schema accepts, unless it is nil. Use Type NullType to only accept null.
+	Const   any             `json:"const,omitempty"`
+	Default json.RawMessage `json:"default,omitempty"`
+
+	Format    string `json:"format,omitempty"`
+	Pattern   string `json:"pattern,omitempty"`
+	MinLength *int   `json:"minLength,omitempty"`
+	MaxLength *int   `json:"maxLength,omitempty"`
+
+	Minimum          *float64 `json:"minimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty"`
+
+	Items    *Schema `json:"items,omitempty"`
+	MinItems *uint   `json:"minItems,omitempty"`
+	MaxItems *uint   `json:"maxItems,omitempty"`
+
+	Properties           Properties `json:"properties,omitempty"`
+	Required             []string   `json:"required,omitempty"`
+	PropertyNames        *Schema    `json:"propertyNames,omitempty"`
+	AdditionalProperties *Schema    `json:"additionalProperties,omitempty"`
+
+	AllOf []Schema `json:"allOf,omitempty"`
+	AnyOf []Schema `json:"anyOf,omitempty"`
+	OneOf []Schema `json:"oneOf,omitempty"`
+	// Discriminator names the property telling apart the alternatives of OneOf, as in OpenAPI.
+	Discriminator *Discriminator `json:"discriminator,omitempty"`
+
+	Defs map[string]Schema `json:"$defs,omitempty"`
+}
+
+// The types of JSON values.
+const (
+	NullType    = "null"
+	BooleanType = "boolean"
+	ObjectType  = "object"
+	ArrayType   = "array"
+	NumberType  = "number"
+	IntegerType = "integer"
+	StringType  = "string"
+)
+
+// Types are the types of JSON values a schema accepts. A single type is marshalled as a string, e.g. `"string"`,
+// and multiple types as an array, e.g. `["string", "null"]`.
+type Types []string
+
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// Property is a property of an object schema.
+type Property struct {
+	Name   string
+	Schema Schema
+}
+
+// Properties are the properties of an object schema, which are marshalled in order.
+type Properties []Property
+
+func (p Properties) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, property := range p {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := marshal(property.Name, "")
+		if err != nil {
+			return nil, err
+		}
+		schema, err := marshal(property.Schema, "")
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(schema)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Discriminator names the property whose value tells apart the alternatives of a oneOf.
+type Discriminator struct {
+	PropertyName string `json:"propertyName"`
+}
+
+// Of is the schema accepting values of the given types.
+func Of(types ...string) Schema {
+	return Schema{Type: types}
+}
+
+// DefsPrefix is the prefix of the references to the schemas defined in the `$defs` of the document.
+const DefsPrefix = "#/$defs/"
+
+// Ref refers to the schema defined in the `$defs` of the document under the given name.
+func Ref(name string) Schema {
+	return Schema{Ref: DefsPrefix + name}
+}
+
+// Nullable accepts null in addition to the values the given schema accepts.
+func Nullable(s Schema) Schema {
+	restricted := s.Ref != "" || s.Const != nil || s.Enum != nil || s.AllOf != nil || s.AnyOf != nil || s.OneOf != nil
+	switch {
+	case slices.Contains(s.Type, NullType) || len(s.Type) == 0 && !restricted:
+		// The schema accepts null already.
+		return s
+	case !restricted:
+		s.Type = append(slices.Clip(s.Type), NullType)
+		return s
+	default:
+		return AnyOf(s, Of(NullType))
+	}
+}
+
+// AnyOf accepts the values any of the given schemas accepts.
+func AnyOf(schemas ...Schema) Schema {
+	return Schema{AnyOf: schemas}
+}
+
+// OneOf accepts the values the object schemas with the given discriminator property accept, of which at most one
+// accepts any given value.
+func OneOf(discriminator string, schemas ...Schema) Schema {
+	return Schema{OneOf: schemas, Discriminator: &Discriminator{discriminator}}
+}
+
+// WithPattern restricts the strings the schema accepts to those matching the given regular expression, in addition to
+// any pattern it has already.
+func (s Schema) WithPattern(pattern string) Schema {
+	if s.Pattern == "" {
+		s.Pattern = pattern
+		return s
+	}
+	s.AllOf = append(slices.Clip(s.AllOf), Schema{Pattern: pattern})
+	return s
+}
+
+// WithFormat restricts the strings the schema accepts to those of the given format, in addition to any format it has
+// already.
+func (s Schema) WithFormat(format string) Schema {
+	if s.Format == "" {
+		s.Format = format
+		return s
+	}
+	s.AllOf = append(slices.Clip(s.AllOf), Schema{Format: format})
+	return s
+}
+
+// AtLeast restricts the numbers the schema accepts to those greater than or equal to min.
+func (s Schema) AtLeast(min float64) Schema {
+	if s.Minimum == nil || *s.Minimum < min {
+		s.Minimum = &min
+	}
+	return s
+}
+
+// AtMost restricts the numbers the schema accepts to those less than or equal to max.
+func (s Schema) AtMost(max float64) Schema {
+	if s.Maximum == nil || *s.Maximum > max {
+		s.Maximum = &max
+	}
+	return s
+}
+
+// Above restricts the numbers the schema accepts to those greater than min.
+func (s Schema) Above(min float64) Schema {
+	if s.ExclusiveMinimum == nil || *s.ExclusiveMinimum < min {
+		s.ExclusiveMinimum = &min
+	}
+	return s
+}
+
+// Below restricts the numbers the schema accepts to those less than max.
+func (s Schema) Below(max float64) Schema {
+	if s.ExclusiveMaximum == nil || *s.ExclusiveMaximum > max {
+		s.ExclusiveMaximum = &max
+	}
+	return s
+}
+
+// Relocated returns the schema with its references to the schemas defined in `$defs` replaced by references to the same
+// names under the given prefix, e.g. `#/components/schemas/` for the schemas of an OpenAPI document.
+func (s Schema) Relocated(prefix string) Schema {
+	if name, ok := strings.CutPrefix(s.Ref, DefsPrefix); ok {
+		s.Ref = prefix + name
+	}
+	relocate := func(schema *Schema) *Schema {
+		if schema == nil {
+			return nil
+		}
+		relocated := schema.Relocated(prefix)
+		return &relocated
+	}
+	relocateAll := func(schemas []Schema) []Schema {
+		if schemas == nil {
+			return nil
+		}
+		relocated := make([]Schema, len(schemas))
+		for i, schema := range schemas {
+			relocated[i] = schema.Relocated(prefix)
+		}
+		return relocated
+	}
+	s.Items, s.PropertyNames, s.AdditionalProperties = relocate(s.Items), relocate(s.PropertyNames), relocate(s.AdditionalProperties)
+	s.AllOf, s.AnyOf, s.OneOf = relocateAll(s.AllOf), relocateAll(s.AnyOf), relocateAll(s.OneOf)
+	if s.Properties != nil {
+		properties := make(Properties, len(s.Properties))
+		for i, p := range s.Properties {
+			properties[i] = Property{p.Name, p.Schema.Relocated(prefix)}
+		}
+		s.Properties = properties
+	}
+	if s.Defs != nil {
+		defs := make(map[string]Schema, len(s.Defs))
+		for name, schema := range s.Defs {
+			defs[name] = schema.Relocated(prefix)
+		}
+		s.Defs = defs
+	}
+	return s
+}
+
+// Marshal returns the indented JSON of the schema.
+func (s Schema) Marshal() ([]byte, error) {
+	return marshal(s, "  ")
+}
+
+// marshal returns the JSON of the given value with the given indent, leaving the characters of regular expressions like
+// `<` and `&` unescaped.
+func marshal(v any, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", indent)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
@@ -0,0 +1,141 @@
+// Package openapi models OpenAPI 3.1 documents, see https://spec.openapis.org/oas/v3.1.0.
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/jsonschema"
+)
+
+// Version is the version of the OpenAPI Specification the documents modelled by this package follow.
+const Version = "3.1.0"
+
+// SchemasPrefix is the prefix of the references to the schemas of the components of the document.
+const SchemasPrefix = "#/components/schemas/"
+
+// Document is an OpenAPI document.
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+// Info is the metadata of the API described by a document.
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem holds the operations on a path, by their HTTP method.
+type PathItem struct {
+	Get     *Operation `json:"get,omitempty"`
+	Put     *Operation `json:"put,omitempty"`
+	Post    *Operation `json:"post,omitempty"`
+	Delete  *Operation `json:"delete,omitempty"`
+	Options *Operation `json:"options,omitempty"`
+	Head    *Operation `json:"head,omitempty"`
+	Patch   *Operation `json:"patch,omitempty"`
+	Trace   *Operation `json:"trace,omitempty"`
+}
+
+// Operation is an operation of the API.
+type Operation struct {
+	OperationID string              `json:"operationId,omitempty"`
+	Summary     string              `json:"summary,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// The locations of parameters.
+const (
+	InPath   = "path"
+	InQuery  = "query"
+	InHeader = "header"
+	InCookie = "cookie"
+)
+
+// Parameter is a parameter of an operation.
+type Parameter struct {
+	Name        string            `json:"name"`
+	In          string            `json:"in"`
+	Description string            `json:"description,omitempty"`
+	Required    bool              `json:"required,omitempty"`
+	Schema      jsonschema.Schema `json:"schema"`
+}
+
+// RequestBody is the body of the requests of an operation.
+type RequestBody struct {
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+// Response is a response of an operation.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType is the content of a request body or response of a media type.
+type MediaType struct {
+	Schema jsonschema.Schema `json:"schema"`
+}
+
+// Components holds the schemas the rest of the document refers to with references starting with SchemasPrefix.
+type Components struct {
+	Schemas map[string]jsonschema.Schema `json:"schemas,omitempty"`
+}
+
+// Marshal returns the indented JSON of the document.
+func (d Document) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	// Leave the characters of patterns like `<` and `&` unescaped.
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(d); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// Add adds the given operation to the path item under the given HTTP method, e.g. http.MethodGet, unless there is one
+// already.
+func (p *PathItem) Add(method string, operation *Operation) error {
+	var slot **Operation
+	switch strings.ToUpper(method) {
+	case http.MethodGet:
+		slot = &p.Get
+	case http.MethodPut:
+		slot = &p.Put
+	case http.MethodPost:
+		slot = &p.Post
+	case http.MethodDelete:
+		slot = &p.Delete
+	case http.MethodOptions:
+		slot = &p.Options
+	case http.MethodHead:
+		slot = &p.Head
+	case http.MethodPatch:
+		slot = &p.Patch
+	case http.MethodTrace:
+		slot = &p.Trace
+	default:
+		return fmt.Errorf("unsupported HTTP method %#v", method)
+	}
+	if *slot != nil {
+		return errors.New("more than one operation is declared for the method and path")
+	}
+	*slot = operation
+	return nil
+}
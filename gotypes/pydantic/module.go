@@ -0,0 +1,321 @@
+package pydantic
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Declaration is a top-level definition of a Python module, i.e. a model class or a type alias.
+type Declaration struct {
+	name, doc      string
+	typeParameters []string
+	t              Type
+}
+
+// Declare declares the given type under the given name, with the given docstring: models as classes, which are generic
+// if there are type parameters, e.g. `class Page(BaseModel, Generic[T]):`, and other types as aliases, e.g.
+// `UserID = Annotated[int, Field(ge=0)]`.
+//
+// Models apply the functions of their BeforeValidators with a model validator, and their other metadata is left out.
+func Declare(name, doc string, typeParameters []string, t Type) Declaration {
+	return Declaration{name, doc, typeParameters, t}
+}
+
+// render renders the declaration. Attributes of the given names are renamed, since Pydantic would resolve the
+// annotations of the model's other fields to them rather than to the module's names.
+func (d Declaration) render(shadowing map[string]bool) (string, requirements) {
+	t := d.t
+	if !t.model {
+		return fmt.Sprintf("%s = %s\n%s", d.name, t.Annotation(), docstring(d.doc, "")), t.requires
+	}
+	requires := importing(pydantic("BaseModel"))
+	bases := "BaseModel"
+	if len(d.typeParameters) > 0 {
+		bases += ", Generic[" + strings.Join(d.typeParameters, ", ") + "]"
+		requires = requires.and(requirements{imports: []Import{typing("Generic")}, typeVars: d.typeParameters})
+	}
+	fields := slices.Clone(t.fields)
+	for i, f := range fields {
+		if shadowing[f.Name] {
+			fields[i].Name += "_"
+			fields[i].Alias = cmp.Or(f.Alias, f.Name)
+		}
+	}
+	var body []string
+	if doc := docstring(d.doc, "    "); doc != "" {
+		body = append(body, doc)
+	}
+	var config []string
+	if slices.ContainsFunc(fields, func(f Field) bool { return f.Alias != "" }) {
+		config = append(config, "populate_by_name=True")
+	}
+	if slices.ContainsFunc(fields, func(f Field) bool { return strings.TrimSpace(f.Doc) != "" }) {
+		config = append(config, "use_attribute_docstrings=True")
+	}
+	if len(config) > 0 {
+		body = append(body, "    model_config = ConfigDict("+strings.Join(config, ", ")+")")
+		requires = requires.and(importing(pydantic("ConfigDict")))
+	}
+	var attributes []string
+	for _, f := range fields {
+		source, fieldRequires := f.declaration()
+		attributes = append(attributes, source)
+		requires = requires.and(fieldRequires)
+	}
+	if len(attributes) > 0 {
+		body = append(body, strings.Join(attributes, "\n"))
+	}
+	if validator, ok := t.modelValidator(); ok {
+		body = append(body, validator)
+		requires = requires.and(t.requires).and(importing(pydantic("model_validator"), typing("Any")))
+	}
+	if len(body) == 0 {
+		body = append(body, "    pass")
+	}
+	return fmt.Sprintf("class %s(%s):\n%s", d.name, bases, strings.Join(body, "\n\n")), requires
+}
+
+// declaration declares the field in the body of a class.
+func (f Field) declaration() (string, requirements) {
+	requires := f.Type.requires
+	var arguments []string
+	if f.Type.optional {
+		value := cmp.Or(f.Type.value, "None")
+		if f.Alias == "" {
+			arguments = append(arguments, value)
+		} else {
+			arguments = append(arguments, "default="+value)
+		}
+	}
+	if f.Alias != "" {
+		arguments = append(arguments, "alias="+Repr(f.Alias))
+	}
+	source := fmt.Sprintf("    %s: %s", f.Name, f.Type.Annotation())
+	if f.Alias != "" {
+		source += " = Field(" + strings.Join(arguments, ", ") + ")"
+		requires = requires.and(importing(pydantic("Field")))
+	} else if len(arguments) > 0 {
+		source += " = " + arguments[0]
+	}
+	if doc := docstring(f.Doc, "    "); doc != "" {
+		source += "\n" + doc
+	}
+	return source, requires
+}
+
+// modelValidator returns the model validator applying the functions of the model's BeforeValidators, if it has any.
+// The functions of later validators are applied first, as Pydantic does.
+func (t Type) modelValidator() (string, bool) {
+	value := "value"
+	for _, m := range slices.Backward(t.metadata) {
+		if m.before != "" {
+			value = fmt.Sprintf("%s(%s)", m.before, value)
+		}
+	}
+	if value == "value" {
+		return "", false
+	}
+	return `    @model_validator(mode="before")
+    @classmethod
+    def parse_input(cls, value: Any) -> Any:
+        return ` + value, true
+}
+
+// docstring renders the given text as a docstring with the given indentation, unless it is blank.
+func docstring(text, indent string) string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return ""
+	}
+	text = strings.ReplaceAll(strings.ReplaceAll(text, `\`, `\\`), `"""`, `\"\"\"`)
+	if strings.HasSuffix(text, `"`) {
+		text = text[:len(text)-1] + `\"`
+	}
+	lines := strings.Split(text, "\n")
+	if len(lines) == 1 {
+		return indent + `"""` + text + `"""`
+	}
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = indent + line
+		}
+	}
+	return indent + `"""` + strings.TrimPrefix(strings.Join(lines, "\n"), indent) + "\n" + indent + `"""`
+}
+
+// Module renders a Python module defining the given declarations in order, preceded by the imports, type variables and
+// helper functions they require.
+//
+// Annotations are evaluated lazily, i.e. `from __future__ import annotations`, so that the fields of models may refer
+// to models declared after them. Aliases are evaluated right away, so they use Forward references for that.
+func Module(declarations ...Declaration) string {
+	shadowing := maps.Clone(builtinNames)
+	for _, d := range declarations {
+		shadowing[d.name] = true
+		for _, p := range d.typeParameters {
+			shadowing[p] = true
+		}
+	}
+	var requires requirements
+	sources := make([]string, len(declarations))
+	for i, d := range declarations {
+		var declarationRequires requirements
+		sources[i], declarationRequires = d.render(shadowing)
+		requires = requires.and(declarationRequires)
+	}
+	var typeVars []string
+	for _, name := range requires.typeVars {
+		if !slices.Contains(typeVars, name) {
+			typeVars = append(typeVars, name)
+			requires = requires.and(importing(typing("TypeVar")))
+		}
+	}
+	var helpers []string
+	for _, name := range requires.helpers {
+		if helper := helperSources[name]; !slices.Contains(helpers, helper.source) {
+			helpers = append(helpers, helper.source)
+			requires = requires.and(importing(helper.imports...))
+		}
+	}
+
+	blocks := []string{"from __future__ import annotations"}
+	blocks = append(blocks, importBlocks(requires.imports)...)
+	if len(typeVars) > 0 {
+		lines := make([]string, len(typeVars))
+		for i, name := range typeVars {
+			lines[i] = fmt.Sprintf("%s = TypeVar(%s)", name, Repr(name))
+		}
+		blocks = append(blocks, strings.Join(lines, "\n"))
+	}
+	definitions := slices.Clip(helpers)
+	definitions = append(definitions, sources...)
+	module := strings.Join(blocks, "\n\n")
+	if len(definitions) > 0 {
+		module += "\n\n\n" + strings.Join(definitions, "\n\n\n")
+	}
+	return module + "\n"
+}
+
+// importBlocks renders the given imports, the standard library's before others, with the modules imported as a whole
+// before the names imported from modules.
+func importBlocks(imports []Import) []string {
+	names := make(map[string][]string)
+	for _, i := range imports {
+		if !slices.Contains(names[i.Module], i.Name) {
+			names[i.Module] = append(names[i.Module], i.Name)
+		}
+	}
+	var standard, others []string
+	for _, module := range slices.Sorted(maps.Keys(names)) {
+		var lines []string
+		moduleNames := slices.Sorted(slices.Values(names[module]))
+		if moduleNames[0] == "" {
+			lines = append(lines, "import "+module)
+			moduleNames = moduleNames[1:]
+		}
+		if len(moduleNames) > 0 {
+			lines = append(lines, fmt.Sprintf("from %s import %s", module, strings.Join(moduleNames, ", ")))
+		}
+		if standardModules[module] {
+			standard = append(standard, lines...)
+		} else {
+			others = append(others, lines...)
+		}
+	}
+	var blocks []string
+	for _, lines := range [][]string{standard, others} {
+		slices.SortStableFunc(lines, func(a, b string) int {
+			isFrom := func(line string) int {
+				if strings.HasPrefix(line, "from ") {
+					return 1
+				}
+				return 0
+			}
+			return cmp.Compare(isFrom(a), isFrom(b))
+		})
+		if len(lines) > 0 {
+			blocks = append(blocks, strings.Join(lines, "\n"))
+		}
+	}
+	return blocks
+}
+
+// builtinNames are the lowercase names of builtins and imports that annotations and validators may refer to, see
+// Declaration.render.
+var builtinNames = map[string]bool{
+	"bool": true, "bytes": true, "datetime": true, "dict": true, "float": true, "int": true, "list": true, "re": true,
+	"str": true,
+}
+
+// standardModules are the modules of the standard library that annotations and helpers import from.
+var standardModules = map[string]bool{"collections.abc": true, "datetime": true, "re": true, "typing": true}
+
+// templateHelper is the name of the helper function returning the functions parsing templated strings, see
+// Type.Template.
+const templateHelper = "_template"
+
+var helperSources = map[string]struct {
+	source  string
+	imports []Import
+}{
+	templateHelper: {`def _template(pattern: str, template: str, *names: str) -> Callable[[Any], Any]:
+    regex = re.compile(pattern)
+
+    def parse(value: Any) -> Any:
+        match = regex.fullmatch(value) if isinstance(value, str) else None
+        if match is None:
+            raise ValueError(f"expected string of the form {template} matching {pattern}")
+        return dict(zip(names, match.groups())) if names else match.group(1)
+
+    return parse`, []Import{{Module: "re"}, {"collections.abc", "Callable"}, typing("Any")}},
+}
+
+// AttributeName returns the name of a model's attribute for the field of the given name, e.g. the name of the Go field
+// it corresponds to, in snake case, e.g. `order_id` for `OrderID`.
+//
+// Names that Pydantic reserves are suffixed with an underscore, e.g. `json_`.
+func AttributeName(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			r = '_'
+		}
+		if unicode.IsUpper(r) && i > 0 {
+			previous := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || unicode.IsUpper(previous) && nextIsLower {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	attribute := strings.TrimLeft(b.String(), "_")
+	for strings.Contains(attribute, "__") {
+		attribute = strings.ReplaceAll(attribute, "__", "_")
+	}
+	if attribute == "" || unicode.IsDigit([]rune(attribute)[0]) {
+		attribute = "field_" + attribute
+	}
+	if reservedNames[attribute] || strings.HasPrefix(attribute, "model_") {
+		attribute += "_"
+	}
+	return attribute
+}
+
+// reservedNames are Python's lowercase keywords and the names of the attributes of Pydantic's BaseModel, which fields mustn't
+// shadow, as well as the name of the model validator declared by Declare.
+var reservedNames = map[string]bool{
+	"and": true, "as": true, "assert": true, "async": true, "await": true,
+	"break": true, "class": true, "continue": true, "def": true, "del": true, "elif": true, "else": true,
+	"except": true, "finally": true, "for": true, "from": true, "global": true, "if": true, "import": true, "in": true,
+	"is": true, "lambda": true, "nonlocal": true, "not": true, "or": true, "pass": true, "raise": true, "return": true,
+	"try": true, "while": true, "with": true, "yield": true,
+	"construct": true, "copy": true, "dict": true, "from_orm": true, "json": true, "parse_file": true, "parse_obj": true,
+	"parse_raw": true, "schema": true, "schema_json": true, "update_forward_refs": true, "validate": true,
+	"parse_input": true,
+}
@@ -0,0 +1,364 @@
+// Package pydantic is a thin builder for the type annotations of Pydantic v2 models (https://docs.pydantic.dev) and
+// the Python modules declaring them.
+//
+// Constraints and validators are Annotated metadata, e.g. `Annotated[int, Field(ge=0)]`, so that they apply wherever
+// the annotation is used, e.g. to the items of lists.
+package pydantic
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/internal/formats"
+	"github.com/softwaretechnik-berlin/goats/gotypes/util"
+)
+
+// Type is the annotation of a Pydantic field, along with what the module declaring it has to import and define.
+//
+// Types are values: their methods return modified copies rather than modifying the types they are called on.
+type Type struct {
+	expr     string
+	requires requirements
+	metadata []metadatum
+	nullable bool
+	// optional tells whether fields of the type may be left out, in which case they default to value, or to None if
+	// value is empty.
+	optional bool
+	value    string
+	// model tells whether the type is a model with the given fields, which is declared as a class. Until then, it is
+	// a dict.
+	model  bool
+	fields []Field
+}
+
+// metadatum is an element of the metadata of an Annotated type.
+type metadatum struct {
+	// constraint is a keyword argument of Field, e.g. `ge=0`, unless it is empty.
+	constraint string
+	// validator is a validator or annotated_types predicate, e.g. `AfterValidator(str)`, unless it is empty.
+	validator string
+	// before is the function of a BeforeValidator, which models apply with a model validator instead, unless it is empty.
+	before string
+}
+
+// Import is a name imported from a Python module, e.g. `from typing import Any`, or the module itself, e.g.
+// `import re`, if Name is empty.
+type Import struct {
+	Module, Name string
+}
+
+// requirements are what a module has to import and define at its top level for an annotation to be valid.
+type requirements struct {
+	imports  []Import
+	typeVars []string
+	helpers  []string
+}
+
+func (r requirements) and(other requirements) requirements {
+	return requirements{
+		append(slices.Clip(r.imports), other.imports...),
+		append(slices.Clip(r.typeVars), other.typeVars...),
+		append(slices.Clip(r.helpers), other.helpers...),
+	}
+}
+
+func importing(imports ...Import) requirements {
+	return requirements{imports: imports}
+}
+
+func typing(name string) Import   { return Import{"typing", name} }
+func pydantic(name string) Import { return Import{"pydantic", name} }
+
+// Expr is an escape hatch to create a Type from a hand-written Python annotation, which may refer to the given imports.
+func Expr(expr string, imports ...Import) Type {
+	return Type{expr: expr, requires: importing(imports...)}
+}
+
+func Any() Type      { return Expr("Any", typing("Any")) }
+func Bool() Type     { return Expr("bool") }
+func Datetime() Type { return Expr("datetime", Import{"datetime", "datetime"}) }
+func Float() Type    { return Expr("float") }
+func Int() Type      { return Expr("int") }
+func None() Type     { return Expr("None") }
+func Str() Type      { return Expr("str") }
+
+// Literal accepts only the given values, see Repr.
+func Literal(values ...any) Type {
+	return Expr(fmt.Sprintf("Literal[%s]", strings.Join(util.Map(values, Repr), ", ")), typing("Literal"))
+}
+
+func List(item Type) Type {
+	return wrap("list[%s]", item)
+}
+
+func Dict(key, value Type) Type {
+	return wrap("dict[%s, %s]", key, value)
+}
+
+func Union(members ...Type) Type {
+	return wrap("Union["+placeholders(len(members))+"]", members...).requiring(typing("Union"))
+}
+
+// DiscriminatedUnion is the union of the given models, which Pydantic tells apart by the field of the given name.
+func DiscriminatedUnion(discriminator string, members ...Type) Type {
+	return Union(members...).constrained("discriminator", Repr(discriminator))
+}
+
+// Field is a field of a model.
+type Field struct {
+	// Name is the name of the field's attribute, see AttributeName.
+	Name string
+	// Alias is the name of the field in the JSON values of the model, unless it is empty.
+	Alias string
+	Type  Type
+	// Doc is rendered as the docstring of the attribute.
+	Doc string
+}
+
+// Model is a model with the given fields, which is declared as a class with Model. It is a dict until then.
+func Model(fields ...Field) Type {
+	t := Dict(Str(), Any())
+	t.model, t.fields = true, fields
+	return t
+}
+
+// Forward quotes the annotation of the given type, which refers to a declaration that may not be defined yet, e.g.
+// `"User"`.
+func Forward(t Type) Type {
+	return Type{expr: Repr(t.Annotation()), requires: t.requires}
+}
+
+// TypeVar is the type variable of the given name, which the module declares for generic models and aliases.
+func TypeVar(name string) Type {
+	t := Expr(name)
+	t.requires.typeVars = []string{name}
+	return t
+}
+
+// Generic instantiates the given generic model or alias with the given type arguments, e.g. `Page[User]`.
+func Generic(generic Type, arguments ...Type) Type {
+	return wrap("%s["+placeholders(len(arguments))+"]", append([]Type{generic}, arguments...)...)
+}
+
+// placeholders returns the given number of comma-separated placeholders for the annotations formatted by wrap.
+func placeholders(n int) string {
+	return strings.Join(slices.Repeat([]string{"%s"}, n), ", ")
+}
+
+// wrap formats the annotations of the given types into an annotation, which requires what the types require.
+func wrap(format string, types ...Type) Type {
+	var t Type
+	annotations := make([]any, len(types))
+	for i, u := range types {
+		annotations[i] = u.Annotation()
+		t.requires = t.requires.and(u.requires)
+	}
+	t.expr = fmt.Sprintf(format, annotations...)
+	return t
+}
+
+func (t Type) requiring(imports ...Import) Type {
+	t.requires = t.requires.and(importing(imports...))
+	return t
+}
+
+// Annotation renders the type, annotated with its metadata and made Optional if it is nullable.
+func (t Type) Annotation() string {
+	annotation := t.annotated()
+	if t.nullable {
+		return "Optional[" + annotation + "]"
+	}
+	return annotation
+}
+
+func (t Type) annotated() string {
+	var metadata []string
+	var constraints []string
+	for _, m := range t.metadata {
+		if m.constraint != "" {
+			constraints = append(constraints, m.constraint)
+			continue
+		}
+		if len(constraints) > 0 {
+			metadata = append(metadata, "Field("+strings.Join(constraints, ", ")+")")
+			constraints = nil
+		}
+		if m.before != "" {
+			metadata = append(metadata, "BeforeValidator("+m.before+")")
+		} else {
+			metadata = append(metadata, m.validator)
+		}
+	}
+	if len(constraints) > 0 {
+		metadata = append(metadata, "Field("+strings.Join(constraints, ", ")+")")
+	}
+	if len(metadata) == 0 {
+		return t.expr
+	}
+	return "Annotated[" + t.expr + ", " + strings.Join(metadata, ", ") + "]"
+}
+
+// withMetadata adds the given metadatum, which requires the given imports, leaving the type's optionality as it is.
+func (t Type) withMetadata(m metadatum, imports ...Import) Type {
+	t.metadata = append(slices.Clip(t.metadata), m)
+	t.requires = t.requires.and(importing(append(imports, typing("Annotated"))...))
+	return t
+}
+
+// inner is the type of the values the type's fields accept apart from None, as an annotation that can be wrapped.
+func (t Type) inner() Type {
+	return Type{expr: t.annotated(), requires: t.requires}
+}
+
+// Nullable accepts None in addition to the values the type accepts, i.e. `Optional[…]`.
+func (t Type) Nullable() Type {
+	if t.nullable {
+		return t
+	}
+	nullable := t.inner().requiring(typing("Optional"))
+	nullable.nullable = true
+	return nullable
+}
+
+// Optional makes fields of the type default to None when they are left out, which also makes them accept None.
+func (t Type) Optional() Type {
+	optional := t.Nullable()
+	optional.optional = true
+	return optional
+}
+
+// Default makes fields of the type default to the given Python value when they are left out, see Repr.
+func (t Type) Default(value string) Type {
+	t.optional, t.value = true, value
+	return t
+}
+
+// Or accepts the values either the type or the given one accepts.
+func (t Type) Or(other Type) Type {
+	return Union(t, other)
+}
+
+// Json accepts JSON strings encoding the values the type accepts, i.e. `Json[…]`.
+func (t Type) Json() Type {
+	return wrap("Json[%s]", t).requiring(pydantic("Json"))
+}
+
+// Integer accepts only integers, making a float into an int.
+func (t Type) Integer() Type {
+	if t.expr == "float" {
+		t.expr = "int"
+	}
+	return t
+}
+
+func (t Type) constrained(name, value string) Type {
+	return t.withMetadata(metadatum{constraint: name + "=" + value}, pydantic("Field"))
+}
+
+func (t Type) Description(text string) Type { return t.constrained("description", Repr(text)) }
+func (t Type) Ge(min float64) Type          { return t.constrained("ge", number(min)) }
+func (t Type) Le(max float64) Type          { return t.constrained("le", number(max)) }
+func (t Type) Gt(min float64) Type          { return t.constrained("gt", number(min)) }
+func (t Type) Lt(max float64) Type          { return t.constrained("lt", number(max)) }
+func (t Type) MinLength(length int) Type    { return t.constrained("min_length", strconv.Itoa(length)) }
+func (t Type) MaxLength(length int) Type    { return t.constrained("max_length", strconv.Itoa(length)) }
+func (t Type) Length(length int) Type       { return t.MinLength(length).MaxLength(length) }
+
+// Pattern requires strings to contain a match of the given regular expression. Pydantic only checks one pattern
+// given to Field, so further patterns are checked with predicates.
+func (t Type) Pattern(re *regexp.Regexp) Type {
+	if slices.ContainsFunc(t.metadata, func(m metadatum) bool { return strings.HasPrefix(m.constraint, "pattern=") }) {
+		return t.Check(fmt.Sprintf("lambda s: re.search(%s, s) is not None", RegexLiteral(re))).requiring(Import{Module: "re"})
+	}
+	return t.constrained("pattern", RegexLiteral(re))
+}
+
+func (t Type) Includes(substring string) Type {
+	return t.Pattern(regexp.MustCompile(regexp.QuoteMeta(substring)))
+}
+func (t Type) StartsWith(prefix string) Type {
+	return t.Pattern(regexp.MustCompile("^" + regexp.QuoteMeta(prefix)))
+}
+func (t Type) EndsWith(suffix string) Type {
+	return t.Pattern(regexp.MustCompile(regexp.QuoteMeta(suffix) + "$"))
+}
+
+// Pydantic checks the following formats with regular expressions, so that strings stay strings, as in the other
+// backends.
+func (t Type) UUID() Type  { return t.Pattern(formats.UUID) }
+func (t Type) Email() Type { return t.Pattern(formats.Email) }
+func (t Type) URL() Type   { return t.Pattern(formats.URL) }
+func (t Type) IP() Type    { return t.Pattern(formats.IP) }
+func (t Type) IPv4() Type  { return t.Pattern(formats.IPv4) }
+func (t Type) IPv6() Type  { return t.Pattern(formats.IPv6) }
+
+// DatetimeWithOffset requires an ISO 8601 date and time with a time zone offset, e.g. `2006-01-02T15:04:05+07:00`.
+func (t Type) DatetimeWithOffset() Type { return t.Pattern(formats.DatetimeWithOffset) }
+
+// Check rejects the values for which the given Python predicate returns false, i.e. `Predicate(…)`.
+func (t Type) Check(predicate string) Type {
+	return t.withMetadata(metadatum{validator: "Predicate(" + predicate + ")"}, Import{"annotated_types", "Predicate"})
+}
+
+// Before applies the given Python function to the values before validating them, i.e. `BeforeValidator(…)`.
+func (t Type) Before(fn string) Type {
+	return t.withMetadata(metadatum{before: fn}, pydantic("BeforeValidator"))
+}
+
+// After applies the given Python function to the values after validating them, i.e. `AfterValidator(…)`.
+func (t Type) After(fn string) Type {
+	return t.withMetadata(metadatum{validator: "AfterValidator(" + fn + ")"}, pydantic("AfterValidator"))
+}
+
+// Template parses strings of the given template matching the given regular expression before validating them, into
+// the string matched by the template's placeholder or, if names are given, into a dict mapping the names to the strings
+// matched by the groups of the expression in order.
+func (t Type) Template(re *regexp.Regexp, template string, names ...string) Type {
+	args := append([]string{RegexLiteral(re), Repr(template)}, util.Map(names, func(name string) string { return Repr(name) })...)
+	parsed := t.Before(fmt.Sprintf("%s(%s)", templateHelper, strings.Join(args, ", ")))
+	parsed.requires = parsed.requires.and(requirements{helpers: []string{templateHelper}})
+	return parsed
+}
+
+// Repr renders the given value, which is nil or a bool, number, string, or slice or map of them, as a Python literal.
+func Repr(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "None"
+	case bool:
+		if value {
+			return "True"
+		}
+		return "False"
+	case string:
+		return strconv.Quote(value)
+	case float64:
+		return number(value)
+	case int64, uint64, int:
+		return fmt.Sprint(value)
+	case []any:
+		return "[" + strings.Join(util.Map(value, Repr), ", ") + "]"
+	case map[string]any:
+		keys := slices.Sorted(maps.Keys(value))
+		return "{" + strings.Join(util.Map(keys, func(key string) string { return Repr(key) + ": " + Repr(value[key]) }), ", ") + "}"
+	default:
+		panic(fmt.Sprintf("%#v has no Python literal", value))
+	}
+}
+
+func number(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// RegexLiteral renders the given regular expression as a Python string literal, which is raw if possible.
+func RegexLiteral(re *regexp.Regexp) string {
+	pattern := re.String()
+	if strings.ContainsAny(pattern, "\"\n") || strings.HasSuffix(pattern, `\`) {
+		return strconv.Quote(pattern)
+	}
+	return `r"` + pattern + `"`
+}
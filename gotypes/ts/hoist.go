@@ -0,0 +1,152 @@
+package ts
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// hoistable is an expression that Hoist may declare as a constant of its own.
+type hoistable struct {
+	expr Source
+	// text is the expression without its imports, which identifies it.
+	text string
+}
+
+// hoistedNames are the names of the constants declared by Hoist, by the text of their expressions.
+type hoistedNames struct {
+	names map[string]Identifier
+	// except is the text of the expression of the constant being declared, which mustn't refer to itself.
+	except string
+}
+
+// hoisting renders the source, referring to the hoisted expressions in it by name.
+type hoisting struct {
+	source  Source
+	hoisted hoistedNames
+}
+
+var _ Source = (*hoistable)(nil)
+var _ Source = hoisting{}
+
+// Hoistable marks the given expression as one that Hoist may declare as a constant of its own, e.g. an inline schema
+// that occurs repeatedly.
+func Hoistable(expr Source) Source {
+	if h, ok := expr.(*hoistable); ok {
+		return h
+	}
+	var buf bytes.Buffer
+	expr.writeSourceTo(sourceWriter{&indentationAwareWriter{&buf, false}, "", hoistedNames{}})
+	return &hoistable{expr, buf.String()}
+}
+
+func (h *hoistable) String() string             { return toString(h) }
+func (h *hoistable) addToImports(imps *imports) { h.expr.addToImports(imps) }
+func (h *hoistable) subsources() []Source       { return []Source{h.expr} }
+
+func (h *hoistable) writeSourceTo(w sourceWriter) {
+	if name, ok := w.hoisted.names[h.text]; ok && h.text != w.hoisted.except {
+		name.writeSourceTo(w)
+		return
+	}
+	h.expr.writeSourceTo(w)
+}
+
+func (h hoisting) String() string             { return toString(h) }
+func (h hoisting) addToImports(imps *imports) { h.source.addToImports(imps) }
+func (h hoisting) subsources() []Source       { return []Source{h.source} }
+
+func (h hoisting) writeSourceTo(w sourceWriter) {
+	w.hoisted = h.hoisted
+	h.source.writeSourceTo(w)
+}
+
+// Hoist declares the Hoistable expressions of at least the given length that occur more than once in the given
+// statements as constants, e.g. `const shared1 = z.object({ … });`, and refers to them by name instead. Each constant
+// precedes the first statement that refers to it, and those of expressions within other hoisted expressions precede
+// those of the other expressions.
+//
+// The occurrences of an expression within another hoisted expression count once, however often the other expression
+// occurs, since it is only declared once.
+func Hoist(minLength int, statements ...Source) []Source {
+	type occurrence struct {
+		*hoistable
+		parent *occurrence
+	}
+	// occurrences holds the occurrences in each statement, with those within an expression preceding the expression's.
+	occurrences := make([][]*occurrence, len(statements))
+	taken := make(map[Identifier]bool)
+	var walk func(statement int, s Source, parent *occurrence)
+	walk = func(statement int, s Source, parent *occurrence) {
+		switch s := s.(type) {
+		case Identifier:
+			taken[s] = true
+		case *hoistable:
+			o := &occurrence{s, parent}
+			walk(statement, s.expr, o)
+			occurrences[statement] = append(occurrences[statement], o)
+			return
+		}
+		for _, sub := range s.subsources() {
+			walk(statement, sub, parent)
+		}
+	}
+	for i, s := range statements {
+		walk(i, s, nil)
+	}
+
+	var texts []string
+	occurrencesByText := make(map[string][]*occurrence)
+	for _, o := range slices.Concat(occurrences...) {
+		if _, ok := occurrencesByText[o.text]; !ok {
+			texts = append(texts, o.text)
+		}
+		occurrencesByText[o.text] = append(occurrencesByText[o.text], o)
+	}
+	// Expressions are decided on before those within them, which are shorter.
+	slices.SortStableFunc(texts, func(a, b string) int { return cmp.Compare(len(b), len(a)) })
+	hoisted := make(map[string]bool)
+	counts := func(o *occurrence) bool {
+		for p := o.parent; p != nil; p = p.parent {
+			if hoisted[p.text] {
+				// Only the first occurrence of the hoisted expression is declared.
+				return p == occurrencesByText[p.text][0]
+			}
+		}
+		return true
+	}
+	for _, text := range texts {
+		if len(text) < minLength {
+			continue
+		}
+		count := 0
+		for _, o := range occurrencesByText[text] {
+			if counts(o) {
+				count++
+			}
+		}
+		hoisted[text] = count > 1
+	}
+
+	names := make(map[string]Identifier)
+	var result []Source
+	for i, statement := range statements {
+		for _, o := range occurrences[i] {
+			if _, ok := names[o.text]; ok || !hoisted[o.text] {
+				continue
+			}
+			name := Identifier(fmt.Sprintf("shared%d", len(names)+1))
+			for suffix := 1; taken[name]; suffix++ {
+				name = Identifier(fmt.Sprintf("shared%d_%d", len(names)+1, suffix))
+			}
+			names[o.text] = name
+			result = append(result, hoisting{Sourcef("const %s = %s;", name, o.expr), hoistedNames{names, o.text}})
+		}
+		result = append(result, hoisting{statement, hoistedNames{names, ""}})
+	}
+	if len(names) == 0 {
+		return statements
+	}
+	return result
+}
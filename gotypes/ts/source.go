@@ -5,6 +5,7 @@ import (
 	"cmp"
 	"fmt"
 	"io"
+	"math"
 	"slices"
 	"strings"
 
@@ -14,10 +15,17 @@ import (
 type tsImport struct {
 	module string
 	name   Identifier
+	// namespace marks an import of the whole module under the name, i.e. `import * as name from "module"`.
+	namespace bool
+	// typeOnly marks an import that is only referred to in types, i.e. `import type { name } from "module"`, which is
+	// left out if the name is imported as a value anyway.
+	typeOnly bool
 }
 
 type imports struct {
 	byName map[tsImport]struct{}
+	// identifiers are those that the source refers to, see Module.
+	identifiers map[Identifier]struct{}
 }
 
 func (i *imports) Add(imp tsImport) {
@@ -32,6 +40,7 @@ type sourceText string
 func (s sourceText) String() string               { return string(s) }
 func (s sourceText) addToImports(_ *imports)      {}
 func (s sourceText) writeSourceTo(w sourceWriter) { w.WriteString(string(s)) }
+func (s sourceText) subsources() []Source         { return nil }
 
 type sourceWithImport struct {
 	tsImport tsImport
@@ -41,6 +50,7 @@ type sourceWithImport struct {
 func (s sourceWithImport) String() string               { return s.text }
 func (s sourceWithImport) addToImports(imps *imports)   { imps.Add(s.tsImport) }
 func (s sourceWithImport) writeSourceTo(w sourceWriter) { w.WriteString(s.text) }
+func (s sourceWithImport) subsources() []Source         { return nil }
 
 type sourceGroup struct {
 	style    groupStyle
@@ -61,23 +71,34 @@ func (s sourceGroup) writeSourceTo(w sourceWriter) {
 	s.style.writeGroupTo(w, s.elements)
 }
 
+func (s sourceGroup) subsources() []Source {
+	return s.elements
+}
+
 type groupStyle interface {
 	writeGroupTo(w sourceWriter, elements []Source)
 }
 
 var _ groupStyle = (*bracedStyle)(nil)
 var _ groupStyle = statementsStyle(0)
+var _ groupStyle = linesStyle{}
 var _ groupStyle = sourcef{}
 
 type bracedStyle struct {
 	open, singleLinePadding, closed sourceText
 	multilineThreshold              int
+	separator                       sourceText
 }
 
 var (
-	invocation = bracedStyle{"(", "", ")", 5}
-	array      = bracedStyle{"[", "", "]", 2}
-	object     = bracedStyle{"{", " ", "}", 2}
+	invocation          = bracedStyle{"(", "", ")", 5, ","}
+	typeArgumentList    = bracedStyle{"<", "", ">", 5, ","}
+	array               = bracedStyle{"[", "", "]", 2, ","}
+	object              = bracedStyle{"{", " ", "}", 2, ","}
+	multilineObject     = bracedStyle{"{", " ", "}", 1, ","}
+	inlineObject        = bracedStyle{"{", " ", "}", math.MaxInt, ","}
+	objectType          = bracedStyle{"{", " ", "}", 2, ";"}
+	multilineObjectType = bracedStyle{"{", " ", "}", 1, ";"}
 )
 
 func (s bracedStyle) writeGroupTo(w sourceWriter, elements []Source) {
@@ -87,7 +108,8 @@ func (s bracedStyle) writeGroupTo(w sourceWriter, elements []Source) {
 			s.singleLinePadding.writeSourceTo(w)
 			for i, element := range elements {
 				if i != 0 {
-					w.WriteString(", ")
+					s.separator.writeSourceTo(w)
+					w.WriteString(" ")
 				}
 				element.writeSourceTo(w)
 			}
@@ -97,7 +119,7 @@ func (s bracedStyle) writeGroupTo(w sourceWriter, elements []Source) {
 			for _, e := range elements {
 				inner.WriteNewline()
 				e.writeSourceTo(inner)
-				inner.WriteString(",")
+				s.separator.writeSourceTo(inner)
 			}
 			w.WriteNewline()
 		}
@@ -119,6 +141,18 @@ func (s statementsStyle) writeGroupTo(w sourceWriter, elements []Source) {
 	}
 }
 
+// linesStyle puts each element on its own line, without a newline after the last one.
+type linesStyle struct{}
+
+func (s linesStyle) writeGroupTo(w sourceWriter, elements []Source) {
+	for i, e := range elements {
+		if i != 0 {
+			w.WriteNewline()
+		}
+		e.writeSourceTo(w)
+	}
+}
+
 type sourcef struct {
 	format string
 }
@@ -187,6 +221,8 @@ func (w *indentationAwareWriter) EnsureNewline() {
 type sourceWriter struct {
 	*indentationAwareWriter
 	indentation string
+	// hoisted are the names of the hoisted expressions to refer to, see Hoist.
+	hoisted hoistedNames
 }
 
 func (w sourceWriter) WriteString(s string) {
@@ -198,26 +234,67 @@ func (w sourceWriter) indent() sourceWriter {
 }
 
 func (w sourceWriter) indentBy(additional string) sourceWriter {
-	return sourceWriter{w.indentationAwareWriter, w.indentation + additional}
+	return sourceWriter{w.indentationAwareWriter, w.indentation + additional, w.hoisted}
 }
 
 func toString(s Source) string {
+	return toModuleString(s, func(Identifier) (string, bool) { return "", false })
+}
+
+func toModuleString(s Source, importedFrom func(Identifier) (string, bool)) string {
 	var buf bytes.Buffer
 	var w io.StringWriter = &buf
 	var imps imports
 	s.addToImports(&imps)
+	for identifier := range imps.identifiers {
+		if module, ok := importedFrom(identifier); ok {
+			imps.Add(tsImport{module, identifier, false, false})
+		}
+	}
+	for imp := range imps.byName {
+		if _, imported := imps.byName[tsImport{imp.module, imp.name, false, false}]; imp.typeOnly && imported {
+			delete(imps.byName, imp)
+		}
+	}
 	sortedImports := maps.Keys(imps.byName)
 	slices.SortFunc(sortedImports, func(a, b tsImport) int {
 		if r := cmp.Compare(a.module, b.module); r != 0 {
 			return r
 		}
+		if a.namespace != b.namespace {
+			// Namespace imports come first, so that the names imported from a module are adjacent.
+			if a.namespace {
+				return -1
+			}
+			return 1
+		}
+		if a.typeOnly != b.typeOnly {
+			// Type-only imports come last, since they are imported separately.
+			if b.typeOnly {
+				return -1
+			}
+			return 1
+		}
 		return cmp.Compare(a.name, b.name)
 	})
-	sw := sourceWriter{&indentationAwareWriter{w, false}, ""}
+	sw := sourceWriter{&indentationAwareWriter{w, false}, "", hoistedNames{}}
 	if len(sortedImports) > 0 {
-		// TODO group by module
-		for _, imp := range sortedImports {
-			sw.WriteString(fmt.Sprintf("import { %s } from %s;\n", imp.name, StringLiteral(imp.module)))
+		var names []string
+		for i, imp := range sortedImports {
+			if imp.namespace {
+				sw.WriteString(fmt.Sprintf("import * as %s from %s;\n", imp.name, StringLiteral(imp.module)))
+				continue
+			}
+			// The names imported from a module are adjacent, so they are imported together.
+			names = append(names, string(imp.name))
+			if next := i + 1; next == len(sortedImports) || sortedImports[next].module != imp.module || sortedImports[next].typeOnly != imp.typeOnly {
+				keyword := "import"
+				if imp.typeOnly {
+					keyword = "import type"
+				}
+				sw.WriteString(fmt.Sprintf("%s { %s } from %s;\n", keyword, strings.Join(names, ", "), StringLiteral(imp.module)))
+				names = nil
+			}
 		}
 		sw.WriteString("\n")
 	}
@@ -4,6 +4,8 @@ package ts
 import (
 	"fmt"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 
 	"golang.org/x/exp/constraints"
@@ -22,6 +24,8 @@ type Source interface {
 
 	addToImports(imps *imports)
 	writeSourceTo(w sourceWriter)
+	// subsources returns the sources that the source is composed of, e.g. the elements of a group.
+	subsources() []Source
 }
 
 var _ Source = Identifier("")
@@ -46,16 +50,19 @@ func DocComment(comment string) Source {
 	if len(comment) == 0 {
 		return sourceText("")
 	}
-	return Statements(
-		sourceText("/**"),
-		Statements(util.Map(strings.Split(comment, "\n"), func(line string) Source {
-			if len(line) == 0 {
-				return sourceText(" *")
-			}
-			return sourceText(" * " + line)
-		})...),
-		sourceText(" */"),
-	)
+	return Statements(docCommentLines(comment)...)
+}
+
+func docCommentLines(comment string) []Source {
+	lines := []Source{sourceText("/**")}
+	for _, line := range strings.Split(comment, "\n") {
+		if len(line) == 0 {
+			lines = append(lines, sourceText(" *"))
+		} else {
+			lines = append(lines, sourceText(" * "+line))
+		}
+	}
+	return append(lines, sourceText(" */"))
 }
 
 // Identifier is a TypeScript identifier string.
@@ -64,12 +71,70 @@ func DocComment(comment string) Source {
 type Identifier string
 
 func (i Identifier) String() string               { return sourceText(i).String() }
-func (i Identifier) addToImports(imps *imports)   { sourceText(i).addToImports(imps) }
 func (i Identifier) writeSourceTo(w sourceWriter) { sourceText(i).writeSourceTo(w) }
+func (i Identifier) subsources() []Source         { return nil }
+
+// addToImports records the identifier, so that Module can import it if it is declared by another module.
+func (i Identifier) addToImports(imps *imports) {
+	if imps.identifiers == nil {
+		imps.identifiers = make(map[Identifier]struct{})
+	}
+	imps.identifiers[i] = struct{}{}
+}
 
 // ImportedName returns a Source representing a name that has been imported from a module.
 func ImportedName(module string, name Identifier) Source {
-	return sourceWithImport{tsImport{module, name}, string(name)}
+	return sourceWithImport{tsImport{module, name, false, false}, string(name)}
+}
+
+// ImportedType is like ImportedName, but imports the name only for use in types, i.e. `import type { name } from
+// "module"`, unless it is imported as a value anyway.
+func ImportedType(module string, name Identifier) Source {
+	return sourceWithImport{tsImport{module, name, false, true}, string(name)}
+}
+
+// ImportedNamespace returns a Source representing the name under which all exports of a module have been imported,
+// i.e. `import * as name from "module"`.
+func ImportedNamespace(module string, name Identifier) Source {
+	return sourceWithImport{tsImport{module, name, true, false}, string(name)}
+}
+
+// Importing returns source rendering as the given source, which additionally requires the given names to be imported
+// from the given module. It is useful for source text that refers to imported names, such as hand-written expressions.
+func Importing(source Source, module string, names ...Identifier) Source {
+	elements := make([]Source, 0, len(names)+1)
+	for _, name := range names {
+		elements = append(elements, sourceWithImport{tsImport{module, name, false, false}, ""})
+	}
+	elements = append(elements, source)
+	return sourceGroup{sourcef{strings.Repeat("%s", len(elements))}, elements}
+}
+
+// ImportingNamespace is like Importing, but requires all exports of the given module to be imported under the given
+// name, i.e. `import * as name from "module"`.
+func ImportingNamespace(source Source, module string, name Identifier) Source {
+	return Sourcef("%s%s", sourceWithImport{tsImport{module, name, true, false}, ""}, source)
+}
+
+// Module renders the given source as a module, like String, but additionally imports the identifiers it refers to from
+// the modules that the given function returns for them, e.g. `import { Money } from "./billing";`. Identifiers for which
+// the function returns false, such as those declared in the module itself, aren't imported.
+func Module(source Source, importedFrom func(Identifier) (module string, ok bool)) string {
+	return toModuleString(source, importedFrom)
+}
+
+var exportedDeclaration = regexp.MustCompile(`(?m)^export (?:declare )?(?:const|let|var|type|interface|function|class|enum) ([A-Za-z_$][\w$]*)`)
+
+// ExportedNames returns the names of the top-level declarations that the given source exports, e.g. `User` for
+// `export const User = …`, in order.
+func ExportedNames(source Source) []Identifier {
+	var names []Identifier
+	for _, match := range exportedDeclaration.FindAllStringSubmatch(source.String(), -1) {
+		if name := Identifier(match[1]); !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
 }
 
 // InvokeFunction follows the function by a parenthesized comma-separated list of arguments.
@@ -86,23 +151,49 @@ func InvokeMethod(receiver Source, name Identifier, arguments ...Source) Source
 
 // NumberLiteral returns literal Source for the given value.
 func NumberLiteral[N constraints.Integer | constraints.Float](value N) Source {
-	// TODO logic
-	return sourceText(fmt.Sprintf(`%v`, value))
+	switch value := any(value).(type) {
+	case float32:
+		return sourceText(strconv.FormatFloat(float64(value), 'f', -1, 32))
+	case float64:
+		return sourceText(strconv.FormatFloat(value, 'f', -1, 64))
+	default:
+		return sourceText(fmt.Sprintf(`%d`, value))
+	}
 }
 
 // Object outputs the given properties as `name: value`-pairs surrounded by `{` and `}` and interspersed with `,`.
 // It gives reasonable line-breaking, whitespace and indentation.
+// Properties with comments are always put on lines of their own.
 func Object(properties ...Property) Source {
-	return sourceGroup{&object, util.Map(properties, Property.AsSource)}
+	style := &object
+	if hasComments(properties) {
+		style = &multilineObject
+	}
+	return sourceGroup{style, util.Map(properties, Property.AsSource)}
+}
+
+// InlineObject outputs the given members, such as properties, shorthand properties and spread elements, surrounded by
+// `{` and `}` and interspersed with `,` on a single line. Unlike Object, it never has a trailing `,`, so it is suitable
+// for destructuring patterns with rest elements.
+func InlineObject(members ...Source) Source {
+	return sourceGroup{&inlineObject, members}
+}
+
+func hasComments(properties []Property) bool {
+	return slices.ContainsFunc(properties, func(p Property) bool { return strings.TrimSpace(p.Comment) != "" })
 }
 
 // Property is a named value for use with Object.
 type Property struct {
 	Name  string
 	Value Source
+	// Comment is rendered as a doc comment preceding the property, if it is not blank.
+	Comment string
+	// Optional marks the property with a `?`, which is only meaningful for property signatures in an ObjectType.
+	Optional bool
 }
 
-// AsSource represents the property as a `name: value` pair.
+// AsSource represents the property as a `name: value` pair, preceded by its doc comment.
 func (p Property) AsSource() Source {
 	var name Source
 	if isValidIdentifier(p.Name) {
@@ -110,7 +201,15 @@ func (p Property) AsSource() Source {
 	} else {
 		name = StringLiteral(p.Name)
 	}
-	return Sourcef(`%s: %s`, name, p.Value)
+	separator := ": "
+	if p.Optional {
+		separator = "?: "
+	}
+	pair := Sourcef(`%s`+separator+`%s`, name, p.Value)
+	if comment := strings.TrimSpace(p.Comment); comment != "" {
+		return sourceGroup{linesStyle{}, append(docCommentLines(comment), pair)}
+	}
+	return pair
 }
 
 // RegexLiteral represents the given regexp as a TypeScript regex literal.
@@ -142,16 +241,22 @@ func StatementGroups(blankLinesBetweenGroups int, groups ...Source) Source {
 	return sourceGroup{statementsStyle(blankLinesBetweenGroups), groups}
 }
 
-var stringEscaper = strings.NewReplacer(`"`, `\"`)
+var stringEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\t", `\t`,
+	"\u2028", `\u2028`,
+	"\u2029", `\u2029`,
+)
 
-// StringEscape escapes the given string for inclusion in a TypeScript string literal.
-// TODO there is missing escaping.
+// StringEscape escapes the given string for inclusion in a double-quoted TypeScript string literal.
 func StringEscape(str string) Source {
 	return sourceText(stringEscaper.Replace(str))
 }
 
 // StringLiteral represents the given string as a TypeScript string literal.
-// TODO there is missing escaping.
 func StringLiteral(str string) Source {
 	return sourceText(fmt.Sprintf(`"%s"`, StringEscape(str)))
 }
@@ -0,0 +1,163 @@
+package ts
+
+import (
+	"github.com/softwaretechnik-berlin/goats/gotypes/util"
+)
+
+// TypeExpression is Source representing a TypeScript type.
+//
+// Unlike plain Source, it knows enough about its own structure to be parenthesized correctly when combined into other
+// type expressions, e.g. when a union type is used as the element type of an array type.
+type TypeExpression struct {
+	source     Source
+	precedence typePrecedence
+}
+
+var _ Source = TypeExpression{}
+
+func (t TypeExpression) String() string               { return t.source.String() }
+func (t TypeExpression) addToImports(imps *imports)   { t.source.addToImports(imps) }
+func (t TypeExpression) writeSourceTo(w sourceWriter) { t.source.writeSourceTo(w) }
+func (t TypeExpression) subsources() []Source         { return []Source{t.source} }
+
+// typePrecedence orders type operators by how tightly they bind.
+type typePrecedence int
+
+const (
+	unionPrecedence typePrecedence = iota
+	intersectionPrecedence
+	primaryPrecedence
+)
+
+func (t TypeExpression) atLeast(precedence typePrecedence) Source {
+	if t.precedence < precedence {
+		return Sourcef("(%s)", t.source)
+	}
+	return t.source
+}
+
+// Keyword types.
+var (
+	AnyType       = TypeName(Identifier("any"))
+	BigIntType    = TypeName(Identifier("bigint"))
+	BooleanType   = TypeName(Identifier("boolean"))
+	NeverType     = TypeName(Identifier("never"))
+	NullType      = TypeName(Identifier("null"))
+	NumberType    = TypeName(Identifier("number"))
+	StringType    = TypeName(Identifier("string"))
+	UndefinedType = TypeName(Identifier("undefined"))
+	UnknownType   = TypeName(Identifier("unknown"))
+)
+
+// TypeName refers to a named type, e.g. `string`, `Foo` or `z.BRAND<"Foo">`,
+// instantiating it with the given type arguments if there are any.
+func TypeName(name Source, typeArguments ...TypeExpression) TypeExpression {
+	if len(typeArguments) == 0 {
+		return TypeExpression{name, primaryPrecedence}
+	}
+	arguments := make([]Source, len(typeArguments))
+	for i, argument := range typeArguments {
+		arguments[i] = argument
+	}
+	return TypeExpression{sourceGroup{sourcef{"%s%s"}, []Source{name, sourceGroup{&typeArgumentList, arguments}}}, primaryPrecedence}
+}
+
+// LiteralType is the type of the given literal, e.g. `"foo"`.
+func LiteralType(literal Source) TypeExpression {
+	return TypeExpression{literal, primaryPrecedence}
+}
+
+// TypeQuery is the type of the given value, i.e. `typeof value`.
+func TypeQuery(value Source) TypeExpression {
+	return TypeExpression{Sourcef("typeof %s", value), primaryPrecedence}
+}
+
+// ArrayType is the type of arrays with elements of the given type, i.e. `T[]`.
+func ArrayType(element TypeExpression) TypeExpression {
+	return TypeExpression{Sourcef("%s[]", element.atLeast(primaryPrecedence)), primaryPrecedence}
+}
+
+// UnionType is the union of the given types, i.e. `A | B`. The empty union is `never`.
+func UnionType(types ...TypeExpression) TypeExpression {
+	return combineTypes(NeverType, " | ", unionPrecedence, types)
+}
+
+// IntersectionType is the intersection of the given types, i.e. `A & B`. The empty intersection is `unknown`.
+func IntersectionType(types ...TypeExpression) TypeExpression {
+	return combineTypes(UnknownType, " & ", intersectionPrecedence, types)
+}
+
+func combineTypes(empty TypeExpression, operator string, precedence typePrecedence, types []TypeExpression) TypeExpression {
+	switch len(types) {
+	case 0:
+		return empty
+	case 1:
+		return types[0]
+	}
+	elements := make([]Source, len(types))
+	format := ""
+	for i, t := range types {
+		if i != 0 {
+			format += operator
+		}
+		format += "%s"
+		elements[i] = t.atLeast(precedence)
+	}
+	return TypeExpression{sourceGroup{sourcef{format}, elements}, precedence}
+}
+
+// ObjectType is the type of objects with the given property signatures, whose values must be TypeExpressions.
+func ObjectType(properties ...Property) TypeExpression {
+	style := &objectType
+	if hasComments(properties) {
+		style = &multilineObjectType
+	}
+	return TypeExpression{sourceGroup{style, util.Map(properties, Property.AsSource)}, primaryPrecedence}
+}
+
+// TypeParameter declares a type parameter of a generic function or type, e.g. `T extends Foo`.
+type TypeParameter struct {
+	Name Identifier
+	// Constraint is optional.
+	Constraint *TypeExpression
+}
+
+func (p TypeParameter) asSource() Source {
+	if p.Constraint == nil {
+		return p.Name
+	}
+	return Sourcef("%s extends %s", p.Name, *p.Constraint)
+}
+
+// TypeParameters declares the given type parameters, e.g. `<T extends Foo, U>`, or is empty if there are none.
+func TypeParameters(parameters ...TypeParameter) Source {
+	if len(parameters) == 0 {
+		return sourceText("")
+	}
+	return sourceGroup{&typeArgumentList, util.Map(parameters, TypeParameter.asSource)}
+}
+
+// Parameter declares a parameter of a function, e.g. `t: T`.
+type Parameter struct {
+	Name Identifier
+	Type TypeExpression
+}
+
+// Signature is the signature of a function, e.g. `<T>(t: T): T`.
+type Signature struct {
+	TypeParameters []TypeParameter
+	Parameters     []Parameter
+	// ReturnType is optional.
+	ReturnType *TypeExpression
+}
+
+// ArrowFunction is an arrow function with the given signature and body, e.g. `<T>(t: T): T => t`.
+func ArrowFunction(signature Signature, body Source) Source {
+	parameters := sourceGroup{&invocation, util.Map(signature.Parameters, func(p Parameter) Source {
+		return Sourcef("%s: %s", p.Name, p.Type)
+	})}
+	if signature.ReturnType == nil {
+		return Sourcef("%s%s => %s", TypeParameters(signature.TypeParameters...), parameters, body)
+	}
+	return Sourcef("%s%s: %s => %s", TypeParameters(signature.TypeParameters...), parameters, *signature.ReturnType, body)
+}
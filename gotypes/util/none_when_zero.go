@@ -21,7 +21,7 @@ import (
 // This type is appropriate to use with relatively small data types.
 // Larger data types should use [Optional].
 type NoneWhenZero[A comparable] struct {
-	V A `tsgen:",value,nullable"`
+	V A `gotypes:",value,nullable"`
 }
 
 var _ driver.Valuer = (*NoneWhenZero[any])(nil)
@@ -11,7 +11,7 @@ import (
 
 type Optional[A any] struct {
 	HasValue bool
-	V        A `tsgen:",value,nullable"`
+	V        A `gotypes:",value,nullable"`
 }
 
 var _ json.Marshaler = (*Optional[any])(nil)
@@ -0,0 +1,168 @@
+// Package valibot is a thin builder for Valibot schemas (https://valibot.dev) as TypeScript source.
+//
+// The schemas refer to the functions of the valibot module imported as `v`, i.e. `import * as v from "valibot"`.
+package valibot
+
+import (
+	"regexp"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/ts"
+	"github.com/softwaretechnik-berlin/goats/gotypes/util"
+)
+
+// V is the namespace under which the valibot module is imported.
+var V = ts.ImportedNamespace("valibot", "v")
+
+// Schema is a Valibot schema along with the actions it is piped through, e.g. `v.pipe(v.string(), v.email())`.
+//
+// Schemas are values: their methods return modified copies rather than modifying the schemas they are called on.
+type Schema struct {
+	schema  ts.Source
+	actions []ts.Source
+}
+
+// Expr is an escape hatch to create a Schema from an arbitrary ts.Source, e.g. the name of a declared schema.
+func Expr(schema ts.Source) Schema {
+	return Schema{schema: schema}
+}
+
+// Text is an escape hatch to create a Schema from a hand-written TypeScript expression, which may refer to `v`.
+func Text(expr string) Schema {
+	return Expr(ts.ImportingNamespace(ts.AsSource(expr), "valibot", "v"))
+}
+
+func call(name ts.Identifier, args ...ts.Source) ts.Source {
+	return ts.InvokeMethod(V, name, args...)
+}
+
+func schemaFunc(name ts.Identifier, args ...ts.Source) Schema {
+	return Expr(call(name, args...))
+}
+
+func Any() Schema     { return schemaFunc("any") }
+func BigInt() Schema  { return schemaFunc("bigint") }
+func Boolean() Schema { return schemaFunc("boolean") }
+func Null() Schema    { return schemaFunc("null") }
+func Number() Schema  { return schemaFunc("number") }
+func String() Schema  { return schemaFunc("string") }
+func Unknown() Schema { return schemaFunc("unknown") }
+
+// Literal accepts only the given literal value.
+func Literal(value ts.Source) Schema {
+	return schemaFunc("literal", value)
+}
+
+// Picklist accepts only the given literal values.
+func Picklist(values ...ts.Source) Schema {
+	return schemaFunc("picklist", ts.Array(values...))
+}
+
+func Array(item Schema) Schema {
+	return schemaFunc("array", item.TypeScript())
+}
+
+func Record(key, value Schema) Schema {
+	return schemaFunc("record", key.TypeScript(), value.TypeScript())
+}
+
+// Entry is an entry of an Object.
+type Entry struct {
+	Name   string
+	Schema Schema
+	// Comment is rendered as a doc comment on the entry.
+	Comment string
+}
+
+func Object(entries ...Entry) Schema {
+	return schemaFunc("object", ts.Object(util.Map(entries, func(e Entry) ts.Property {
+		return ts.Property{Name: e.Name, Value: e.Schema.TypeScript(), Comment: e.Comment}
+	})...))
+}
+
+func Union(options ...Schema) Schema {
+	return schemaFunc("union", ts.Array(util.Map(options, Schema.TypeScript)...))
+}
+
+// Variant is the union of the given object schemas, which it tells apart by the given discriminator key.
+func Variant(key string, options ...Schema) Schema {
+	return schemaFunc("variant", ts.StringLiteral(key), ts.Array(util.Map(options, Schema.TypeScript)...))
+}
+
+// Lazy defers the evaluation of the given expression, e.g. the name of a schema that is still being declared.
+func Lazy(getter ts.Source) Schema {
+	return schemaFunc("lazy", ts.Sourcef("() => %s", getter))
+}
+
+// Parse parses the given value with the schema, i.e. `v.parse(schema, value)`.
+func Parse(schema Schema, value ts.Source) ts.Source {
+	return call("parse", schema.TypeScript(), value)
+}
+
+// TypeScript renders the schema, piped through its actions if it has any.
+func (s Schema) TypeScript() ts.Source {
+	if len(s.actions) == 0 {
+		return s.schema
+	}
+	return call("pipe", append([]ts.Source{s.schema}, s.actions...)...)
+}
+
+// pipe adds the action of the given function of v to the actions of the schema.
+func (s Schema) pipe(name ts.Identifier, args ...ts.Source) Schema {
+	return Schema{s.schema, append(s.actions[:len(s.actions):len(s.actions)], call(name, args...))}
+}
+
+// Nullable accepts null in addition to the values the schema accepts.
+func (s Schema) Nullable() Schema {
+	return schemaFunc("nullable", s.TypeScript())
+}
+
+// Optional accepts undefined in addition to the values the schema accepts.
+func (s Schema) Optional() Schema {
+	return schemaFunc("optional", s.TypeScript())
+}
+
+// Default replaces undefined with the given value.
+func (s Schema) Default(value ts.Source) Schema {
+	return schemaFunc("optional", s.TypeScript(), value)
+}
+
+// Or accepts the values either the schema or the given one accepts.
+func (s Schema) Or(other Schema) Schema {
+	return Union(s, other)
+}
+
+// PipeTo passes the output of the schema to the given one.
+func (s Schema) PipeTo(target Schema) Schema {
+	return Schema{s.schema, append(s.actions[:len(s.actions):len(s.actions)], target.TypeScript())}
+}
+
+func (s Schema) Brand(brand string) Schema      { return s.pipe("brand", ts.StringLiteral(brand)) }
+func (s Schema) Description(text string) Schema { return s.pipe("description", ts.StringLiteral(text)) }
+func (s Schema) Readonly() Schema               { return s.pipe("readonly") }
+func (s Schema) Check(check ts.Source) Schema   { return s.pipe("check", check) }
+func (s Schema) Transform(fn ts.Source) Schema  { return s.pipe("transform", fn) }
+func (s Schema) Integer() Schema                { return s.pipe("integer") }
+func (s Schema) MinValue(min ts.Source) Schema  { return s.pipe("minValue", min) }
+func (s Schema) MaxValue(max ts.Source) Schema  { return s.pipe("maxValue", max) }
+func (s Schema) GtValue(min ts.Source) Schema   { return s.pipe("gtValue", min) }
+func (s Schema) LtValue(max ts.Source) Schema   { return s.pipe("ltValue", max) }
+func (s Schema) MinLength(length int) Schema    { return s.pipe("minLength", ts.NumberLiteral(length)) }
+func (s Schema) MaxLength(length int) Schema    { return s.pipe("maxLength", ts.NumberLiteral(length)) }
+func (s Schema) Length(length int) Schema       { return s.pipe("length", ts.NumberLiteral(length)) }
+func (s Schema) UUID() Schema                   { return s.pipe("uuid") }
+func (s Schema) Email() Schema                  { return s.pipe("email") }
+func (s Schema) URL() Schema                    { return s.pipe("url") }
+func (s Schema) IP() Schema                     { return s.pipe("ip") }
+func (s Schema) IPv4() Schema                   { return s.pipe("ipv4") }
+func (s Schema) IPv6() Schema                   { return s.pipe("ipv6") }
+func (s Schema) Includes(substring string) Schema {
+	return s.pipe("includes", ts.StringLiteral(substring))
+}
+func (s Schema) StartsWith(prefix string) Schema {
+	return s.pipe("startsWith", ts.StringLiteral(prefix))
+}
+func (s Schema) EndsWith(suffix string) Schema  { return s.pipe("endsWith", ts.StringLiteral(suffix)) }
+func (s Schema) Regex(re *regexp.Regexp) Schema { return s.pipe("regex", ts.RegexLiteral(re)) }
+
+// IsoTimestamp requires an ISO 8601 date and time with a time zone offset, e.g. `2006-01-02T15:04:05+07:00`.
+func (s Schema) IsoTimestamp() Schema { return s.pipe("isoTimestamp") }
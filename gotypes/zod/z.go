@@ -6,6 +6,7 @@ import (
 
 var z = ts.ImportedName("zod", "z")
 
+// zTypeFunc invokes the given function of z. The resulting schema's types are to be set using zodAnyType.typed.
 func zTypeFunc(name ts.Identifier, args ...ts.Source) zodAnyType {
-	return zodAnyType{ts.InvokeMethod(z, name, args...)}
+	return zodAnyType{source: ts.InvokeMethod(z, name, args...)}
 }
@@ -1,26 +1,59 @@
 package zod
 
 import (
+	"regexp"
+
 	"github.com/softwaretechnik-berlin/goats/gotypes/ts"
 	"github.com/softwaretechnik-berlin/goats/gotypes/util"
 )
 
 type ZodType interface {
 	Brand(brand string) ZodBranded
+	// Default replaces undefined with the given value, making undefined part of the schema's input type.
+	Default(value ts.Source) ZodType
+	Describe(description string) ZodType
 	Nullable() ZodNullable
 	Optional() ZodOptional
+	// Or accepts the values either the schema or the given one accepts, i.e. `.or(…)`.
+	Or(other ZodType) ZodType
 	Parse(str ts.Source) ts.Source
 	Parsef(format string, a ...ts.Source) ts.Source
 	Pipe(target ZodType) ZodType
+	// Readonly freezes the values the schema produces, making its output type Readonly.
+	Readonly() ZodType
+	// Refine rejects the values for which the given TypeScript function returns false.
+	Refine(check ts.Source) ZodType
 	Transform(transform ts.Source) ZodType
 	Transformf(format string, a ...ts.Source) ZodType
 
+	// TransformTo is like Transform, but also states the TypeScript type of the transformation's output,
+	// which is unknown for schemas created with Transform.
+	TransformTo(output ts.TypeExpression, transform ts.Source) ZodType
+	// TransformToOutputOf is like TransformTo, but states that the transformation's output has the output type of the
+	// given schema. Unlike TransformTo with the schema's OutputType, it keeps track of the schema's PlainType.
+	TransformToOutputOf(schema ZodType, transform ts.Source) ZodType
+
 	DeclaredAs(name ts.Identifier) ZodType
+	// Hoistable marks the schema as one that may be declared as a constant of its own where it occurs repeatedly, see
+	// ts.Hoist.
+	Hoistable() ZodType
 	TypeScript() ts.Source
+	// OutputType is the TypeScript type of the values the schema produces, i.e. `z.output<typeof schema>`.
+	OutputType() ts.TypeExpression
+	// InputType is the TypeScript type of the values the schema accepts, i.e. `z.input<typeof schema>`.
+	InputType() ts.TypeExpression
+	// PlainType is OutputType without zod's brands, so that the type can be declared without depending on zod.
+	PlainType() ts.TypeExpression
+	// PlainInputType is InputType without references to zod, in which declared schemas stand for their plain input
+	// types, as declared by TypeDeclaration, e.g. `User` rather than `z.input<typeof User>`.
+	PlainInputType() ts.TypeExpression
+
+	types() (output, input tsType)
 }
 
 var _ ZodType = ZodArray(nil)
 var _ ZodType = ZodBranded(nil)
+var _ ZodType = ZodEnum(nil)
 var _ ZodType = ZodNumber(nil)
 var _ ZodType = ZodNullable(nil)
 var _ ZodType = ZodObject(nil)
@@ -28,6 +61,8 @@ var _ ZodType = ZodString(nil)
 
 type ZodArray interface {
 	ZodType
+	Min(len uint) ZodArray
+	Max(len uint) ZodArray
 	Length(len uint) ZodArray
 }
 
@@ -37,11 +72,22 @@ type ZodBranded interface {
 	Unwrap() ZodType
 }
 
+type ZodEnum interface {
+	ZodType
+
+	Members() []EnumMember
+}
+
 type ZodNumber interface {
 	ZodType
 
 	Int() ZodNumber
 	NonNegative() ZodNumber
+	Min(min float64) ZodNumber
+	Max(max float64) ZodNumber
+	Gt(min float64) ZodNumber
+	Lt(max float64) ZodNumber
+	Safe() ZodNumber
 
 	IsInt() bool
 	IsNonNegative() bool
@@ -59,14 +105,23 @@ type ZodOptional interface {
 	Unwrap() ZodType
 }
 
-type ShapeProperty = struct {
+// ShapeProperty is a property of a ZodObject's shape.
+type ShapeProperty struct {
 	Name   string
 	Schema ZodType
+	// Comment is rendered as a doc comment on the property.
+	Comment string
+	// OutputName renames the property in the schema's output, unless it is empty.
+	// Objects with renamed properties transform the parsed objects, so they can't be used in discriminated unions.
+	OutputName ts.Identifier
 }
 
 type ZodObject interface {
 	ZodType
+	// Extend adds the given properties to the object. If any of its properties are renamed, the extended object is
+	// spelled out in full rather than extending the original one.
 	Extend(shape ...ShapeProperty) ZodObject
+	// Merge adds the properties of the given object to the object, spelling the merged one out in full like Extend.
 	Merge(schema ZodObject) ZodObject
 
 	Shape() []ShapeProperty
@@ -75,26 +130,60 @@ type ZodObject interface {
 type ZodString interface {
 	ZodType
 	UUID() ZodString
+	Min(length int) ZodString
+	Max(length int) ZodString
+	Length(length int) ZodString
+	Email() ZodString
+	URL() ZodString
+	Includes(substring string) ZodString
+	StartsWith(prefix string) ZodString
+	EndsWith(suffix string) ZodString
+	DatetimeWithOffset() ZodString
+	IP() ZodString
+	IPv4() ZodString
+	IPv6() ZodString
+	Regex(re *regexp.Regexp) ZodString
 }
 
 func Any() ZodType {
-	return zTypeFunc("any")
+	return zTypeFunc("any").typed(plainType(ts.AnyType, true), plainType(ts.AnyType, true))
 }
 
 func Array(schema ZodType) ZodArray {
-	return zodArray{zTypeFunc("array", schema.TypeScript())}
+	output, input := schema.types()
+	return zodArray{zTypeFunc("array", schema.TypeScript()).typed(arrayType(output), arrayType(input))}
+}
+
+func BigInt() ZodType {
+	return zTypeFunc("bigint").typed(keywordType(ts.BigIntType))
 }
 
 func Boolean() ZodType {
-	return zTypeFunc("boolean")
+	return zTypeFunc("boolean").typed(keywordType(ts.BooleanType))
 }
 
 func Literal(value string) ZodType {
-	return zTypeFunc("literal", ts.StringLiteral(value))
+	literal := ts.StringLiteral(value)
+	return zTypeFunc("literal", literal).typed(keywordType(ts.LiteralType(literal)))
+}
+
+// NumberLiteral is the schema accepting only the given number.
+func NumberLiteral(value float64) ZodType {
+	literal := ts.NumberLiteral(value)
+	return zTypeFunc("literal", literal).typed(keywordType(ts.LiteralType(literal)))
+}
+
+func Lazy(name ts.Identifier) ZodType {
+	return zodLazy{zTypeFunc("lazy", ts.Sourcef("() => %s", name)).typed(plainType(ts.TypeName(name), false), tsType{ts.TypeName(inputTypeName(name)), ts.TypeName(name), false, false}), name}
+}
+
+func Null() ZodType {
+	return zTypeFunc("null").typed(keywordType(ts.NullType))
 }
 
 func Nullable(t ZodType) ZodNullable {
-	return zodNullable{zTypeFunc("nullable", t.TypeScript()), t}
+	output, input := t.types()
+	return zodNullable{zTypeFunc("nullable", t.TypeScript()).typed(nullableType(output), nullableType(input)), t}
 }
 
 // EnsureNullable is a convenience method that calls Nullable on the given schema unless it is sure that doing so will
@@ -117,7 +206,8 @@ func EnsureNullable(t ZodType) ZodType {
 
 // Enum type with the given permissible values
 func Enum(values ...string) ZodType {
-	return zTypeFunc("enum", ts.Array(util.Map(values, ts.StringLiteral)...))
+	literals := util.Map(values, ts.StringLiteral)
+	return zTypeFunc("enum", ts.Array(literals...)).typed(keywordType(ts.UnionType(util.Map(literals, ts.LiteralType)...)))
 }
 
 // StripNullable strips away any known nullable wrappers and returns a bool indicating whether nullability was stripped away.
@@ -130,32 +220,92 @@ func StripNullable(t ZodType) (ZodType, bool) {
 }
 
 func Number() ZodNumber {
-	return zodNumber{zTypeFunc("number"), false, false}
+	return zodNumber{zTypeFunc("number").typed(keywordType(ts.NumberType)), false, false}
 }
 
 func Object(shape ...ShapeProperty) ZodObject {
-	return zodObject{zTypeFunc("object", shapeTypeScript(shape)), shape}
+	object := zTypeFunc("object", shapeTypeScript(shape)).typed(shapeTypes(shape))
+	if renaming, ok := renamingTransform(shape); ok {
+		object = object.chain("transform", renaming)
+	}
+	return zodObject{object, shape}
 }
 
 func Record(keySchema, valueType ZodType) ZodType {
-	return zodArray{zTypeFunc("record", keySchema.TypeScript(), valueType.TypeScript())}
+	keyOutput, keyInput := keySchema.types()
+	valueOutput, valueInput := valueType.types()
+	return zodArray{zTypeFunc("record", keySchema.TypeScript(), valueType.TypeScript()).typed(
+		recordType(keyOutput, valueOutput),
+		recordType(keyInput, valueInput),
+	)}
 }
 
 func String() ZodString {
-	return zodString{zTypeFunc("string")}
+	return zodString{zTypeFunc("string").typed(keywordType(ts.StringType))}
+}
+
+func Unknown() ZodType {
+	return zTypeFunc("unknown").typed(unknownType, unknownType)
 }
 
 func Union(types ...ZodType) ZodType {
-	return zTypeFunc("union", ts.Array(util.Map(types, ZodType.TypeScript)...))
+	return zTypeFunc("union", ts.Array(util.Map(types, ZodType.TypeScript)...)).typed(unionTypes(types))
 }
 
 func DiscriminatedUnion(discriminator string, types ...ZodType) ZodType {
-	return zTypeFunc("discriminatedUnion", ts.StringLiteral(discriminator), ts.Array(util.Map(types, ZodType.TypeScript)...))
+	return zTypeFunc("discriminatedUnion", ts.StringLiteral(discriminator), ts.Array(util.Map(types, ZodType.TypeScript)...)).typed(unionTypes(types))
 }
 
-// ZodZtypeExpr is an escape hatch to create a ZodType from an arbitrary ts.Source.
+// ZodZtypeExpr is an escape hatch to create a ZodType from an arbitrary ts.Source, whose input and output types are
+// unknown.
 func ZodTypeExpr(expr ts.Source) ZodType {
-	return zodAnyType{expr}
+	return zodAnyType{expr, unknownType, unknownType}
+}
+
+// ZodTypeText is an escape hatch to create a ZodType from a hand-written TypeScript expression,
+// which may refer to the `z` export of the zod module.
+func ZodTypeText(expr string) ZodType {
+	return ZodTypeExpr(ts.Importing(ts.AsSource(expr), "zod", "z"))
+}
+
+// External refers to a schema that the given module exports under the given name, e.g. `Money` for
+// `import { Money } from "@acme/money";`, whose type is inferred from it, i.e. `z.infer<typeof Money>`. Its plain types
+// only import the schema and zod for use in types, i.e. `import type { Money } from "@acme/money";`.
+func External(module string, name ts.Identifier) ZodType {
+	schema := ts.ImportedName(module, name)
+	schemaType, zType := ts.ImportedType(module, name), ts.ImportedType("zod", "z")
+	output := tsType{ts.TypeName(ts.Sourcef("%s.infer", z), ts.TypeQuery(schema)), ts.TypeName(ts.Sourcef("%s.infer", zType), ts.TypeQuery(schemaType)), false, false}
+	input := tsType{ts.TypeName(ts.Sourcef("%s.input", z), ts.TypeQuery(schema)), ts.TypeName(ts.Sourcef("%s.input", zType), ts.TypeQuery(schemaType)), false, false}
+	return zodAnyType{schema, output, input}
+}
+
+func keywordType(t ts.TypeExpression) (output, input tsType) {
+	return plainType(t, false), plainType(t, false)
+}
+
+func arrayType(element tsType) tsType {
+	return tsType{ts.ArrayType(element.expr), ts.ArrayType(element.plain), false, false}
+}
+
+func recordType(key, value tsType) tsType {
+	record := ts.Identifier("Record")
+	return tsType{ts.TypeName(record, key.expr, value.expr), ts.TypeName(record, key.plain, value.plain), false, false}
+}
+
+func unionTypes(types []ZodType) (output, input tsType) {
+	outputs, plainOutputs := make([]ts.TypeExpression, len(types)), make([]ts.TypeExpression, len(types))
+	inputs, plainInputs := make([]ts.TypeExpression, len(types)), make([]ts.TypeExpression, len(types))
+	for i, t := range types {
+		tOutput, tInput := t.types()
+		outputs[i], plainOutputs[i], inputs[i], plainInputs[i] = tOutput.expr, tOutput.plain, tInput.expr, tInput.plain
+		output.undefinable = output.undefinable || tOutput.undefinable
+		output.plainUndefinable = output.plainUndefinable || tOutput.plainUndefinable
+		input.undefinable = input.undefinable || tInput.undefinable
+		input.plainUndefinable = input.plainUndefinable || tInput.plainUndefinable
+	}
+	output.expr, output.plain = ts.UnionType(outputs...), ts.UnionType(plainOutputs...)
+	input.expr, input.plain = ts.UnionType(inputs...), ts.UnionType(plainInputs...)
+	return
 }
 
 // TODO reconsider
@@ -163,19 +313,85 @@ type SchemaAndTypeDeclaration struct {
 	comment    string
 	identifier ts.Identifier
 	schema     ZodType
+	recursive  bool
+	explicit   bool
+	// typeParameters are those of a factory, which is declared instead of a schema if there are any.
+	typeParameters []ts.Identifier
 }
 
 func (d SchemaAndTypeDeclaration) Identifier() ts.Identifier { return d.identifier }
 
+// Recursive returns the declaration of a schema that refers to itself via Lazy.
+//
+// Since z.infer can't infer the types of such a schema, its output and input types are declared explicitly and the
+// schema is annotated with them.
+func (d SchemaAndTypeDeclaration) Recursive() SchemaAndTypeDeclaration {
+	d.recursive = true
+	return d
+}
+
+// Explicit returns the declaration with its types declared explicitly instead of being inferred with z.infer, which is
+// slow for TypeScript to check in large numbers. The schema is checked against them with `satisfies`.
+//
+// Factories and the schemas of recursive declarations are declared as before.
+func (d SchemaAndTypeDeclaration) Explicit() SchemaAndTypeDeclaration {
+	d.explicit = true
+	return d
+}
+
 func (d SchemaAndTypeDeclaration) TypeScript() ts.Source {
-	return ts.Statements(
+	if len(d.typeParameters) > 0 {
+		return d.factoryTypeScript()
+	}
+	if d.recursive {
+		input := inputTypeName(d.identifier)
+		annotation := ts.TypeName(ts.Sourcef("%s.ZodType", z), ts.TypeName(d.identifier), ts.TypeName(ts.Sourcef("%s.ZodTypeDef", z)), ts.TypeName(input))
+		return ts.Statements(
+			ts.DocComment(d.comment),
+			ts.Sourcef(`export type %s = %s;`, d.identifier, d.schema.OutputType()),
+			ts.Sourcef(`export type %s = %s;`, input, d.schema.InputType()),
+			ts.Sourcef(`export const %s: %s = %s;`, d.identifier, annotation, d.schema.TypeScript()),
+		)
+	}
+	declaration := ts.Statements(
 		ts.DocComment(d.comment),
 		ts.Sourcef(`export const %s = %s;`, d.identifier, d.schema.TypeScript()),
 		ts.Sourcef(`export type %s = %s.infer<typeof %s>;`, d.identifier, z, d.identifier),
 	)
+	if d.explicit {
+		declaration = d.explicitTypeScript()
+	}
+	return d.withEnumMaps(declaration)
+}
+
+// withEnumMaps follows the given declaration by the value and label maps of the declared schema if it is an enum.
+func (d SchemaAndTypeDeclaration) withEnumMaps(declaration ts.Source) ts.Source {
+	if enum, ok := d.schema.(zodEnum); ok {
+		return ts.StatementGroups(1, append([]ts.Source{declaration}, enum.mapsTypeScript(d.identifier)...)...)
+	}
+	return declaration
+}
+
+// explicitTypeScript declares the output type of the schema, and its input type if it differs, before the schema.
+func (d SchemaAndTypeDeclaration) explicitTypeScript() ts.Source {
+	output, input := d.schema.OutputType(), d.schema.InputType()
+	_, isObject := d.schema.(zodObject)
+	statements := []ts.Source{ts.DocComment(d.comment), typeDeclaration(d.identifier, nil, output, isObject)}
+	annotation := ts.TypeName(ts.Sourcef("%s.ZodType", z), ts.TypeName(d.identifier))
+	if input.String() != output.String() {
+		inputName := inputTypeName(d.identifier)
+		statements = append(statements, ts.Sourcef(`export type %s = %s;`, inputName, input))
+		annotation = ts.TypeName(ts.Sourcef("%s.ZodType", z), ts.TypeName(d.identifier), ts.TypeName(ts.Sourcef("%s.ZodTypeDef", z)), ts.TypeName(inputName))
+	}
+	return ts.Statements(append(statements, ts.Sourcef(`export const %s = %s satisfies %s;`, d.identifier, d.schema.TypeScript(), annotation))...)
+}
+
+// inputTypeName is the name under which the input type of a recursive or explicitly typed schema is declared.
+func inputTypeName(name ts.Identifier) ts.Identifier {
+	return name + "Input"
 }
 
 // NewSchemaAndTypeDeclaration TODO
 func NewSchemaAndTypeDeclaration(comment string, name ts.Identifier, schema ZodType) SchemaAndTypeDeclaration {
-	return SchemaAndTypeDeclaration{comment, name, schema}
+	return SchemaAndTypeDeclaration{comment, name, schema, false, false, nil}
 }
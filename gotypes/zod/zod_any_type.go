@@ -5,21 +5,49 @@ import (
 )
 
 type zodAnyType struct {
-	source ts.Source
+	source        ts.Source
+	output, input tsType
 }
 
 var _ ZodType = zodAnyType{}
 
+// tsType is the TypeScript type of the values a schema produces or accepts.
+type tsType struct {
+	expr ts.TypeExpression
+	// plain is expr without zod's brands, so that it can be declared without zod, e.g. `string` for
+	// `string & z.BRAND<"Foo">`. In input types, it refers to declared schemas by their names rather than by
+	// `z.input<typeof Foo>`.
+	plain ts.TypeExpression
+	// undefinable tells whether undefined is assignable to the type, in which case zod makes properties of the type optional.
+	undefinable bool
+	// plainUndefinable tells whether undefined is assignable to the plain type, which may be known where expr isn't.
+	plainUndefinable bool
+}
+
+var unknownType = plainType(ts.UnknownType, true)
+
 func (t zodAnyType) Brand(brand string) ZodBranded {
 	return chainBrand(t, brand)
 }
 
+func (t zodAnyType) Default(value ts.Source) ZodType {
+	return t.chain("default", value).typed(tsType{t.output.expr, t.output.plain, false, false}, optionalType(t.input))
+}
+
+func (t zodAnyType) Describe(description string) ZodType {
+	return t.chain("describe", ts.StringLiteral(description))
+}
+
 func (t zodAnyType) Nullable() ZodNullable {
-	return zodNullable{t.chain("nullable"), t}
+	return zodNullable{t.chain("nullable").typed(nullableType(t.output), nullableType(t.input)), t}
 }
 
 func (t zodAnyType) Optional() ZodOptional {
-	return zodOptional{t.chain("optional"), t}
+	return zodOptional{t.chain("optional").typed(optionalType(t.output), optionalType(t.input)), t}
+}
+
+func (t zodAnyType) Or(other ZodType) ZodType {
+	return t.chain("or", other.TypeScript()).typed(unionTypes([]ZodType{t, other}))
 }
 
 func (t zodAnyType) Parse(str ts.Source) ts.Source {
@@ -31,26 +59,113 @@ func (t zodAnyType) Parsef(format string, a ...ts.Source) ts.Source {
 }
 
 func (t zodAnyType) Pipe(target ZodType) ZodType {
-	return t.chain("pipe", target.TypeScript())
+	output, _ := target.types()
+	return t.chain("pipe", target.TypeScript()).typed(output, t.input)
+}
+
+func (t zodAnyType) Readonly() ZodType {
+	return t.chain("readonly").typed(mapType(t.output, func(t ts.TypeExpression) ts.TypeExpression {
+		return ts.TypeName(ts.Identifier("Readonly"), t)
+	}), t.input)
+}
+
+func (t zodAnyType) Refine(check ts.Source) ZodType {
+	return t.chain("refine", check)
 }
 
 func (t zodAnyType) Transform(transform ts.Source) ZodType {
-	return t.chain("transform", transform)
+	return t.chain("transform", transform).typed(unknownType, t.input)
 }
 
 func (t zodAnyType) Transformf(format string, a ...ts.Source) ZodType {
 	return t.Transform(ts.Sourcef(format, a...))
 }
 
+func (t zodAnyType) TransformTo(output ts.TypeExpression, transform ts.Source) ZodType {
+	return t.chain("transform", transform).typed(plainType(output, false), t.input)
+}
+
+func (t zodAnyType) TransformToOutputOf(schema ZodType, transform ts.Source) ZodType {
+	output, _ := schema.types()
+	output.undefinable, output.plainUndefinable = false, false
+	return t.chain("transform", transform).typed(output, t.input)
+}
+
 // TODO reconsider
 func (t zodAnyType) DeclaredAs(name ts.Identifier) ZodType {
-	return zodAnyType{name}
+	return t.declaredAs(name)
+}
+
+func (t zodAnyType) Hoistable() ZodType {
+	return t.hoistable()
 }
 
 func (t zodAnyType) TypeScript() ts.Source {
 	return t.source
 }
 
+func (t zodAnyType) OutputType() ts.TypeExpression {
+	return t.output.expr
+}
+
+func (t zodAnyType) InputType() ts.TypeExpression {
+	return t.input.expr
+}
+
+func (t zodAnyType) PlainType() ts.TypeExpression {
+	return t.output.plain
+}
+
+func (t zodAnyType) PlainInputType() ts.TypeExpression {
+	return t.input.plain
+}
+
+func (t zodAnyType) types() (output, input tsType) {
+	return t.output, t.input
+}
+
+// chain invokes the named method on the schema, assuming that it doesn't change the schema's types.
 func (t zodAnyType) chain(name ts.Identifier, args ...ts.Source) zodAnyType {
-	return zodAnyType{ts.InvokeMethod(t.source, name, args...)}
+	t.source = ts.InvokeMethod(t.source, name, args...)
+	return t
+}
+
+func (t zodAnyType) typed(output, input tsType) zodAnyType {
+	t.output, t.input = output, input
+	return t
+}
+
+// hoistable marks the schema's TypeScript as an expression that may be declared as a constant of its own.
+func (t zodAnyType) hoistable() zodAnyType {
+	t.source = ts.Hoistable(t.source)
+	return t
+}
+
+// declaredAs refers to the schema by the given name, under which both the schema and its output type are declared.
+func (t zodAnyType) declaredAs(name ts.Identifier) zodAnyType {
+	return zodAnyType{
+		name,
+		tsType{ts.TypeName(name), ts.TypeName(name), t.output.undefinable, t.output.plainUndefinable},
+		tsType{ts.TypeName(ts.Sourcef("%s.input", z), ts.TypeQuery(name)), ts.TypeName(name), t.input.undefinable, t.input.plainUndefinable},
+	}
+}
+
+// plainType is the given type, which doesn't refer to zod's brands.
+func plainType(expr ts.TypeExpression, undefinable bool) tsType {
+	return tsType{expr, expr, undefinable, undefinable}
+}
+
+// mapType applies the given function to both the type and its plain counterpart.
+func mapType(t tsType, f func(ts.TypeExpression) ts.TypeExpression) tsType {
+	return tsType{f(t.expr), f(t.plain), t.undefinable, t.plainUndefinable}
+}
+
+func nullableType(t tsType) tsType {
+	return mapType(t, func(t ts.TypeExpression) ts.TypeExpression { return ts.UnionType(t, ts.NullType) })
+}
+
+func optionalType(t tsType) tsType {
+	t = mapType(t, func(t ts.TypeExpression) ts.TypeExpression { return ts.UnionType(t, ts.UndefinedType) })
+	t.undefinable, t.plainUndefinable = true, true
+	return t
 }
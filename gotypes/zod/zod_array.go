@@ -14,11 +14,23 @@ func (a zodArray) Brand(brand string) ZodBranded {
 	return chainBrand(a, brand)
 }
 
+func (a zodArray) Min(len uint) ZodArray {
+	return zodArray{a.chain("min", ts.NumberLiteral(len))}
+}
+
+func (a zodArray) Max(len uint) ZodArray {
+	return zodArray{a.chain("max", ts.NumberLiteral(len))}
+}
+
 func (a zodArray) Length(len uint) ZodArray {
 	return zodArray{a.chain("length", ts.NumberLiteral(len))}
 }
 
 // TODO reconsider
 func (a zodArray) DeclaredAs(name ts.Identifier) ZodType {
-	return zodArray{zodAnyType{name}}
+	return zodArray{a.declaredAs(name)}
+}
+
+func (a zodArray) Hoistable() ZodType {
+	return zodArray{a.hoistable()}
 }
@@ -20,10 +20,16 @@ func (b zodBranded) Unwrap() ZodType {
 
 // TODO reconsider
 func (b zodBranded) DeclaredAs(name ts.Identifier) ZodType {
-	wrapped := b.wrapped.DeclaredAs(name)
-	return zodBranded{zodAnyType{wrapped.TypeScript()}, wrapped, b.brand}
+	return zodBranded{b.declaredAs(name), b.wrapped.DeclaredAs(name), b.brand}
+}
+
+func (b zodBranded) Hoistable() ZodType {
+	return zodBranded{b.hoistable(), b.wrapped, b.brand}
 }
 
 func chainBrand(t ZodType, brand string) zodBranded {
-	return zodBranded{zodAnyType{ts.InvokeMethod(t.TypeScript(), "brand", ts.StringLiteral(brand))}, t, brand}
+	output, input := t.types()
+	brandType := ts.TypeName(ts.Sourcef("%s.BRAND", z), ts.LiteralType(ts.StringLiteral(brand)))
+	output.expr = ts.IntersectionType(output.expr, brandType)
+	return zodBranded{zodAnyType{ts.InvokeMethod(t.TypeScript(), "brand", ts.StringLiteral(brand)), output, input}, t, brand}
 }
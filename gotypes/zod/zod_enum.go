@@ -0,0 +1,100 @@
+package zod
+
+import (
+	"fmt"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/ts"
+	"github.com/softwaretechnik-berlin/goats/gotypes/util"
+)
+
+// EnumMember is a permissible value of an enum created with EnumOf.
+type EnumMember struct {
+	// Name is the key of the member in the value map declared along with the enum.
+	Name string
+	// Value is a string, int64 or uint64.
+	Value any
+	// Comment is rendered as the doc comment of the member in the value map.
+	Comment string
+	// Label is the key of the member's value in the label map declared along with the enum, unless it is empty.
+	Label string
+}
+
+type zodEnum struct {
+	zodAnyType
+	members []EnumMember
+}
+
+// EnumOf is an enum of the given members, which is `z.enum([…])` if their values are strings and a union of
+// `z.literal(…)`s if they are numbers.
+//
+// When it is declared, an object mapping the names of the members to their values is declared along with it,
+// e.g. `RoleValues` for the enum `Role`, as well as an object mapping their values to their labels, e.g. `RoleLabels`,
+// if they have any.
+func EnumOf(members ...EnumMember) ZodEnum {
+	var literals []ts.Source
+	seen := make(map[any]bool)
+	for _, member := range members {
+		if !seen[member.Value] {
+			seen[member.Value] = true
+			literals = append(literals, enumLiteral(member.Value))
+		}
+	}
+	types := ts.UnionType(util.Map(literals, ts.LiteralType)...)
+	if _, isString := members[0].Value.(string); isString {
+		return zodEnum{zTypeFunc("enum", ts.Array(literals...)).typed(keywordType(types)), members}
+	}
+	if len(literals) == 1 {
+		return zodEnum{zTypeFunc("literal", literals[0]).typed(keywordType(types)), members}
+	}
+	schemas := util.Map(literals, func(literal ts.Source) ts.Source { return ts.InvokeMethod(z, "literal", literal) })
+	return zodEnum{zTypeFunc("union", ts.Array(schemas...)).typed(keywordType(types)), members}
+}
+
+func (e zodEnum) Hoistable() ZodType {
+	return zodEnum{e.hoistable(), e.members}
+}
+
+func (e zodEnum) Members() []EnumMember {
+	return e.members
+}
+
+func enumLiteral(value any) ts.Source {
+	switch value := value.(type) {
+	case string:
+		return ts.StringLiteral(value)
+	case int64:
+		return ts.NumberLiteral(value)
+	case uint64:
+		return ts.NumberLiteral(value)
+	default:
+		panic(fmt.Sprintf("enum value %#v is neither a string nor an integer", value))
+	}
+}
+
+// mapsTypeScript declares the value map and, if the members have labels, the label map of the enum declared under the
+// given name.
+func (e zodEnum) mapsTypeScript(name ts.Identifier) []ts.Source {
+	values := make([]ts.Property, len(e.members))
+	var labels []ts.Property
+	labelled := make(map[any]bool)
+	for i, member := range e.members {
+		values[i] = ts.Property{Name: member.Name, Value: enumLiteral(member.Value), Comment: member.Comment}
+		if member.Label != "" && !labelled[member.Value] {
+			labelled[member.Value] = true
+			labels = append(labels, ts.Property{Name: fmt.Sprint(member.Value), Value: ts.StringLiteral(member.Label)})
+		}
+	}
+	valuesName := name + "Values"
+	declarations := []ts.Source{ts.Statements(
+		ts.DocComment(fmt.Sprintf("%s maps the names of the members of %s to their values.", valuesName, name)),
+		ts.Sourcef(`export const %s = %s as const;`, valuesName, ts.Object(values...)),
+	)}
+	if len(labels) > 0 {
+		labelsName := name + "Labels"
+		declarations = append(declarations, ts.Statements(
+			ts.DocComment(fmt.Sprintf("%s maps the values of %s to their labels.", labelsName, name)),
+			ts.Sourcef(`export const %s = %s as const;`, labelsName, ts.Object(labels...)),
+		))
+	}
+	return declarations
+}
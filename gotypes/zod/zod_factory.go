@@ -0,0 +1,103 @@
+package zod
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/softwaretechnik-berlin/goats/gotypes/ts"
+	"github.com/softwaretechnik-berlin/goats/gotypes/util"
+)
+
+// TypeParameter is the schema passed to a factory for its type parameter of the given name,
+// for use in the schema the factory returns.
+//
+// The factory receives the schema as a parameter named like the type parameter, but starting with a lowercase letter,
+// e.g. `t` for `T`.
+func TypeParameter(name ts.Identifier) ZodType {
+	parameter := parameterName(name)
+	return zodAnyType{
+		parameter,
+		tsType{ts.TypeName(ts.Sourcef("%s.output", z), ts.TypeName(name)), ts.TypeName(name), false, false},
+		tsType{ts.TypeName(ts.Sourcef("%s.input", z), ts.TypeName(name)), ts.TypeName(name), false, false},
+	}
+}
+
+// Factory refers to the factory declared under the given name.
+// It isn't a schema itself, but produces schemas when invoked with Invoke.
+func Factory(name ts.Identifier) ZodType {
+	return zodFactory{zodAnyType{name, unknownType, unknownType}, name}
+}
+
+// zodFactory refers to the factory declared under the given name.
+type zodFactory struct {
+	zodAnyType
+	name ts.Identifier
+}
+
+func (f zodFactory) Hoistable() ZodType {
+	return zodFactory{f.hoistable(), f.name}
+}
+
+// Invoke invokes the given factory with schemas for its type parameters, e.g. `Page(User)`.
+//
+// The types of the resulting schema are unknown, except for its PlainType, which instantiates the plain type declared
+// along with the factory, e.g. `Page<User>`.
+func Invoke(factory ZodType, arguments ...ZodType) ZodType {
+	output, input := unknownType, unknownType
+	instantiate := func(name ts.Identifier) {
+		output.plain, output.plainUndefinable = ts.TypeName(name, util.Map(arguments, ZodType.PlainType)...), false
+		input.plain, input.plainUndefinable = ts.TypeName(name, util.Map(arguments, ZodType.PlainInputType)...), false
+	}
+	switch factory := factory.(type) {
+	case zodLazy:
+		// The factory is still being declared, so the invocation has to be deferred.
+		instantiate(factory.name)
+		return zTypeFunc("lazy", ts.Sourcef("() => %s", ts.InvokeFunction(factory.name, util.Map(arguments, ZodType.TypeScript)...))).typed(output, input)
+	case zodFactory:
+		instantiate(factory.name)
+	}
+	return zodAnyType{ts.InvokeFunction(factory.TypeScript(), util.Map(arguments, ZodType.TypeScript)...), output, input}
+}
+
+// zodLazy is a lazy reference to the schema or factory declared under the given name.
+type zodLazy struct {
+	zodAnyType
+	name ts.Identifier
+}
+
+func (l zodLazy) Hoistable() ZodType {
+	return zodLazy{l.hoistable(), l.name}
+}
+
+// NewFactoryDeclaration declares a factory that returns the given schema, which refers to the given type parameters
+// using TypeParameter, along with the type of the schema's output.
+func NewFactoryDeclaration(comment string, name ts.Identifier, typeParameters []ts.Identifier, schema ZodType) SchemaAndTypeDeclaration {
+	return SchemaAndTypeDeclaration{comment, name, schema, false, false, typeParameters}
+}
+
+func (d SchemaAndTypeDeclaration) factoryTypeScript() ts.Source {
+	zodTypeAny := ts.TypeName(ts.Sourcef("%s.ZodTypeAny", z))
+	var signature ts.Signature
+	typeArguments := make([]ts.TypeExpression, len(d.typeParameters))
+	for i, p := range d.typeParameters {
+		signature.TypeParameters = append(signature.TypeParameters, ts.TypeParameter{Name: p, Constraint: &zodTypeAny})
+		signature.Parameters = append(signature.Parameters, ts.Parameter{Name: parameterName(p), Type: ts.TypeName(p)})
+		typeArguments[i] = ts.TypeName(p)
+	}
+	if d.recursive {
+		// The type of the schema can't be inferred if the factory refers to itself.
+		signature.ReturnType = &zodTypeAny
+	}
+	output := ts.TypeName(ts.Sourcef("%s.infer", z), ts.TypeName(ts.Identifier("ReturnType"), ts.TypeQuery(ts.TypeName(d.identifier, typeArguments...))))
+	return ts.Statements(
+		ts.DocComment(d.comment),
+		ts.Sourcef(`export const %s = %s;`, d.identifier, ts.ArrowFunction(signature, d.schema.TypeScript())),
+		ts.Sourcef(`export type %s%s = %s;`, d.identifier, ts.TypeParameters(signature.TypeParameters...), output),
+	)
+}
+
+// parameterName is the name of the parameter under which a factory receives the schema for the given type parameter.
+func parameterName(typeParameter ts.Identifier) ts.Identifier {
+	first, size := utf8.DecodeRuneInString(string(typeParameter))
+	return ts.Identifier(string(unicode.ToLower(first)) + string(typeParameter[size:]))
+}
@@ -8,3 +8,7 @@ type zodNullable struct {
 func (n zodNullable) Unwrap() ZodType {
 	return n.wrapped
 }
+
+func (n zodNullable) Hoistable() ZodType {
+	return zodNullable{n.hoistable(), n.wrapped}
+}
@@ -23,6 +23,28 @@ func (n zodNumber) NonNegative() ZodNumber {
 	return zodNumber{n.chain("nonnegative"), n.int, true}
 }
 
+func (n zodNumber) Min(min float64) ZodNumber {
+	return zodNumber{n.chain("min", ts.NumberLiteral(min)), n.int, n.nonNegative || min >= 0}
+}
+
+func (n zodNumber) Max(max float64) ZodNumber {
+	return zodNumber{n.chain("max", ts.NumberLiteral(max)), n.int, n.nonNegative}
+}
+
+func (n zodNumber) Gt(min float64) ZodNumber {
+	return zodNumber{n.chain("gt", ts.NumberLiteral(min)), n.int, n.nonNegative || min >= 0}
+}
+
+func (n zodNumber) Lt(max float64) ZodNumber {
+	return zodNumber{n.chain("lt", ts.NumberLiteral(max)), n.int, n.nonNegative}
+}
+
+// Safe restricts the numbers to those from Number.MIN_SAFE_INTEGER to Number.MAX_SAFE_INTEGER, which JavaScript
+// represents exactly.
+func (n zodNumber) Safe() ZodNumber {
+	return zodNumber{n.chain("safe"), n.int, n.nonNegative}
+}
+
 func (n zodNumber) IsInt() bool {
 	return n.int
 }
@@ -33,5 +55,9 @@ func (n zodNumber) IsNonNegative() bool {
 
 // TODO reconsider
 func (n zodNumber) DeclaredAs(name ts.Identifier) ZodType {
-	return zodNumber{zodAnyType{name}, n.int, n.nonNegative}
+	return zodNumber{n.declaredAs(name), n.int, n.nonNegative}
+}
+
+func (n zodNumber) Hoistable() ZodType {
+	return zodNumber{n.hoistable(), n.int, n.nonNegative}
 }
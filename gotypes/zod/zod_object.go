@@ -20,11 +20,26 @@ func (o zodObject) Brand(brand string) ZodBranded {
 }
 
 func (o zodObject) Extend(shape ...ShapeProperty) ZodObject {
-	return zodObject{o.chain("extend", shapeTypeScript(shape)), append(slices.Clip(o.shape), shape...)}
+	extended := append(slices.Clip(o.shape), shape...)
+	if renames(extended) {
+		return Object(extended...)
+	}
+	return zodObject{o.chain("extend", shapeTypeScript(shape)).typed(shapeTypes(extended)), extended}
 }
 
 func (o zodObject) Merge(schema ZodObject) ZodObject {
-	return zodObject{o.chain("merge", schema.TypeScript()), append(slices.Clip(o.shape), schema.Shape()...)}
+	merged := append(slices.Clip(o.shape), schema.Shape()...)
+	if renames(merged) {
+		return Object(merged...)
+	}
+	return zodObject{o.chain("merge", schema.TypeScript()).typed(shapeTypes(merged)), merged}
+}
+
+// renames tells whether the given shape has renamed properties. The objects of such shapes are followed by transforms,
+// which can't be extended or merged, so those of extended and merged shapes are built from scratch instead.
+func renames(shape []ShapeProperty) bool {
+	_, ok := renamingTransform(shape)
+	return ok
 }
 
 func (o zodObject) Shape() []ShapeProperty {
@@ -33,9 +48,61 @@ func (o zodObject) Shape() []ShapeProperty {
 
 // TODO reconsider
 func (o zodObject) DeclaredAs(name ts.Identifier) ZodType {
-	return zodObject{zodAnyType{name}, o.shape}
+	return zodObject{o.declaredAs(name), o.shape}
+}
+
+func (o zodObject) Hoistable() ZodType {
+	return zodObject{o.hoistable(), o.shape}
 }
 
 func shapeTypeScript(shape []ShapeProperty) ts.Source {
-	return ts.Object(util.Map(shape, func(p ShapeProperty) ts.Property { return ts.Property{p.Name, p.Schema.TypeScript()} })...)
+	return ts.Object(util.Map(shape, func(p ShapeProperty) ts.Property {
+		return ts.Property{Name: p.Name, Value: p.Schema.TypeScript(), Comment: p.Comment}
+	})...)
+}
+
+// shapeTypes are the types of objects with the given shape, in which later properties override earlier ones of the same name.
+func shapeTypes(shape []ShapeProperty) (output, input tsType) {
+	var outputProperties, plainProperties, inputProperties, plainInputProperties []ts.Property
+	indices := make(map[string]int)
+	for _, p := range shape {
+		pOutput, pInput := p.Schema.types()
+		outputName := p.Name
+		if p.OutputName != "" {
+			outputName = string(p.OutputName)
+		}
+		outputProperty := ts.Property{Name: outputName, Value: pOutput.expr, Optional: pOutput.undefinable}
+		// Plain types are declared on their own, so they carry the comments of the properties.
+		plainProperty := ts.Property{Name: outputName, Value: pOutput.plain, Comment: p.Comment, Optional: pOutput.plainUndefinable}
+		inputProperty := ts.Property{Name: p.Name, Value: pInput.expr, Optional: pInput.undefinable}
+		plainInputProperty := ts.Property{Name: p.Name, Value: pInput.plain, Comment: p.Comment, Optional: pInput.plainUndefinable}
+		if i, ok := indices[p.Name]; ok {
+			outputProperties[i], plainProperties[i], inputProperties[i], plainInputProperties[i] = outputProperty, plainProperty, inputProperty, plainInputProperty
+			continue
+		}
+		indices[p.Name] = len(outputProperties)
+		outputProperties = append(outputProperties, outputProperty)
+		plainProperties = append(plainProperties, plainProperty)
+		inputProperties = append(inputProperties, inputProperty)
+		plainInputProperties = append(plainInputProperties, plainInputProperty)
+	}
+	return tsType{ts.ObjectType(outputProperties...), ts.ObjectType(plainProperties...), false, false},
+		tsType{ts.ObjectType(inputProperties...), ts.ObjectType(plainInputProperties...), false, false}
+}
+
+// renamingTransform returns the transform renaming the properties of the parsed objects according to their OutputNames,
+// e.g. `({ user_id: userId, ...rest }) => ({ ...rest, userId })`, if there are any.
+func renamingTransform(shape []ShapeProperty) (ts.Source, bool) {
+	var pattern, result []ts.Source
+	for _, p := range shape {
+		if p.OutputName != "" {
+			pattern = append(pattern, ts.Property{Name: p.Name, Value: p.OutputName}.AsSource())
+			result = append(result, p.OutputName)
+		}
+	}
+	if len(pattern) == 0 {
+		return nil, false
+	}
+	rest := ts.AsSource("...rest")
+	return ts.Sourcef("(%s) => (%s)", ts.InlineObject(append(pattern, rest)...), ts.InlineObject(append([]ts.Source{rest}, result...)...)), true
 }
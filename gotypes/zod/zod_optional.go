@@ -8,3 +8,7 @@ type zodOptional struct {
 func (n zodOptional) Unwrap() ZodType {
 	return n.wrapped
 }
+
+func (n zodOptional) Hoistable() ZodType {
+	return zodOptional{n.hoistable(), n.wrapped}
+}
@@ -1,6 +1,8 @@
 package zod
 
 import (
+	"regexp"
+
 	"github.com/softwaretechnik-berlin/goats/gotypes/ts"
 )
 
@@ -14,7 +16,68 @@ func (s zodString) UUID() ZodString {
 	return zodString{s.chain("uuid")}
 }
 
+// DatetimeWithOffset requires an ISO 8601 date and time with a time zone offset, e.g. `2006-01-02T15:04:05+07:00`,
+// i.e. `.datetime({ offset: true })`.
+func (s zodString) DatetimeWithOffset() ZodString {
+	return zodString{s.chain("datetime", ts.Object(ts.Property{Name: "offset", Value: ts.AsSource("true")}))}
+}
+
+// IP requires an IPv4 or IPv6 address.
+func (s zodString) IP() ZodString {
+	return zodString{s.chain("ip")}
+}
+
+// IPv4 requires an IPv4 address, i.e. `.ip({ version: "v4" })`.
+func (s zodString) IPv4() ZodString {
+	return zodString{s.chain("ip", ts.Object(ts.Property{Name: "version", Value: ts.StringLiteral("v4")}))}
+}
+
+// IPv6 requires an IPv6 address, i.e. `.ip({ version: "v6" })`.
+func (s zodString) IPv6() ZodString {
+	return zodString{s.chain("ip", ts.Object(ts.Property{Name: "version", Value: ts.StringLiteral("v6")}))}
+}
+
+func (s zodString) Min(length int) ZodString {
+	return zodString{s.chain("min", ts.NumberLiteral(length))}
+}
+
+func (s zodString) Max(length int) ZodString {
+	return zodString{s.chain("max", ts.NumberLiteral(length))}
+}
+
+func (s zodString) Length(length int) ZodString {
+	return zodString{s.chain("length", ts.NumberLiteral(length))}
+}
+
+func (s zodString) Email() ZodString {
+	return zodString{s.chain("email")}
+}
+
+func (s zodString) URL() ZodString {
+	return zodString{s.chain("url")}
+}
+
+func (s zodString) Includes(substring string) ZodString {
+	return zodString{s.chain("includes", ts.StringLiteral(substring))}
+}
+
+func (s zodString) StartsWith(prefix string) ZodString {
+	return zodString{s.chain("startsWith", ts.StringLiteral(prefix))}
+}
+
+func (s zodString) EndsWith(suffix string) ZodString {
+	return zodString{s.chain("endsWith", ts.StringLiteral(suffix))}
+}
+
+func (s zodString) Regex(re *regexp.Regexp) ZodString {
+	return zodString{s.chain("regex", ts.RegexLiteral(re))}
+}
+
 // TODO reconsider
 func (t zodString) DeclaredAs(name ts.Identifier) ZodType {
-	return zodString{zodAnyType{name}}
+	return zodString{t.declaredAs(name)}
+}
+
+func (t zodString) Hoistable() ZodType {
+	return zodString{t.hoistable()}
 }
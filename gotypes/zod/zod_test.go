@@ -1,10 +1,12 @@
 package zod_test
 
 import (
+	"regexp"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/softwaretechnik-berlin/goats/gotypes/ts"
 	"github.com/softwaretechnik-berlin/goats/gotypes/zod"
 )
 
@@ -17,17 +19,47 @@ func TestZodTypeScript(t *testing.T) {
 	assertTypeScriptRepresentationOf(t, zod.Literal("foo"), zImport, `z.literal("foo")`)
 	assertTypeScriptRepresentationOf(t, zod.Number(), zImport, `z.number()`)
 	assertTypeScriptRepresentationOf(t, zod.Number().Int(), zImport, `z.number().int()`)
+	assertTypeScriptRepresentationOf(t, zod.Number().Int().Min(-128).Max(127), zImport, `z.number().int().min(-128).max(127)`)
+	assertTypeScriptRepresentationOf(t, zod.Number().Int().Safe(), zImport, `z.number().int().safe()`)
+	assertTypeScriptRepresentationOf(t, zod.BigInt(), zImport, `z.bigint()`)
 	assertTypeScriptRepresentationOf(t, zod.Object(), zImport, `z.object({})`)
-	assertTypeScriptRepresentationOf(t, zod.Object(zod.ShapeProperty{"foo", zod.String()}), zImport, `z.object({ foo: z.string() })`)
+	assertTypeScriptRepresentationOf(t, zod.Object(zod.ShapeProperty{Name: "foo", Schema: zod.String()}), zImport, `z.object({ foo: z.string() })`)
 	assertTypeScriptRepresentationOf(t, zod.Object(
-		zod.ShapeProperty{"foo", zod.String()},
-		zod.ShapeProperty{"bar", zod.Number()},
+		zod.ShapeProperty{Name: "foo", Schema: zod.String()},
+		zod.ShapeProperty{Name: "bar", Schema: zod.Number()},
 	), zImport, `z.object({
     foo: z.string(),
     bar: z.number(),
 })`)
 	assertTypeScriptRepresentationOf(t, zod.String(), zImport, `z.string()`)
 	assertTypeScriptRepresentationOf(t, zod.String().UUID(), zImport, `z.string().uuid()`)
+	assertTypeScriptRepresentationOf(t, zod.String().DatetimeWithOffset(), zImport, `z.string().datetime({ offset: true })`)
+	assertTypeScriptRepresentationOf(t, zod.String().IP(), zImport, `z.string().ip()`)
+	assertTypeScriptRepresentationOf(t, zod.String().Regex(regexp.MustCompile(`^-?\d+$`)), zImport, `z.string().regex(/^-?\d+$/)`)
+	assertTypeScriptRepresentationOf(t, zod.Unknown(), zImport, `z.unknown()`)
+	assertTypeScriptRepresentationOf(t, zod.String().Min(1).Max(10).Email(), zImport, `z.string().min(1).max(10).email()`)
+	assertTypeScriptRepresentationOf(t, zod.String().Length(2).URL(), zImport, `z.string().length(2).url()`)
+	assertTypeScriptRepresentationOf(t, zod.String().IPv4().Includes("a").StartsWith("b").EndsWith("c"), zImport, `z.string().ip({ version: "v4" }).includes("a").startsWith("b").endsWith("c")`)
+	assertTypeScriptRepresentationOf(t, zod.Number().Gt(0).Lt(1.5), zImport, `z.number().gt(0).lt(1.5)`)
+	assertTypeScriptRepresentationOf(t, zod.String().Email().Or(zod.Literal("")), zImport, `z.string().email().or(z.literal(""))`)
+	assertTypeScriptRepresentationOf(t, zod.Number().Refine(ts.AsSource(`n => n != 0`)), zImport, `z.number().refine(n => n != 0)`)
+	assertTypeScriptRepresentationOf(t, zod.NumberLiteral(0.5), zImport, `z.literal(0.5)`)
+	assertTypeScriptRepresentationOf(t, zod.Array(zod.String()).Min(1).Max(3), zImport, `z.array(z.string()).min(1).max(3)`)
+	assertTypeScriptRepresentationOf(t, zod.Number().Min(0.5).Max(1e10), zImport, `z.number().min(0.5).max(10000000000)`)
+	assertTypeScriptRepresentationOf(t, zod.String().Default(ts.StringLiteral("x")), zImport, `z.string().default("x")`)
+	assertTypeScriptRepresentationOf(t, zod.Array(zod.String()).Readonly(), zImport, `z.array(z.string()).readonly()`)
+	assertTypeScriptRepresentationOf(t, zod.Object(
+		zod.ShapeProperty{Name: "user_id", Schema: zod.String(), OutputName: "userId"},
+		zod.ShapeProperty{Name: "name", Schema: zod.String()},
+	), zImport, `z.object({
+    user_id: z.string(),
+    name: z.string(),
+}).transform(({ user_id: userId, ...rest }) => ({ ...rest, userId }))`)
+	assertTypeScriptRepresentationOf(t, zod.Object(zod.ShapeProperty{Name: "user_id", Schema: zod.String(), OutputName: "userId"}).DeclaredAs("User").(zod.ZodObject).
+		Merge(zod.Object(zod.ShapeProperty{Name: "name", Schema: zod.String()})), zImport, `z.object({
+    user_id: z.string(),
+    name: z.string(),
+}).transform(({ user_id: userId, ...rest }) => ({ ...rest, userId }))`)
 	assertTypeScriptRepresentationOf(t, zod.Union(), zImport, `z.union([])`)
 	assertTypeScriptRepresentationOf(t, zod.Union(zod.String()), zImport, `z.union([z.string()])`)
 	assertTypeScriptRepresentationOf(t, zod.Union(zod.String(), zod.Number()), zImport, `z.union([
@@ -35,8 +67,8 @@ func TestZodTypeScript(t *testing.T) {
     z.number(),
 ])`)
 	assertTypeScriptRepresentationOf(t, zod.DiscriminatedUnion("foo",
-		zod.Object(zod.ShapeProperty{"foo", zod.String()}),
-		zod.Object(zod.ShapeProperty{"foo", zod.Number()}),
+		zod.Object(zod.ShapeProperty{Name: "foo", Schema: zod.String()}),
+		zod.Object(zod.ShapeProperty{Name: "foo", Schema: zod.Number()}),
 	), zImport, `z.discriminatedUnion("foo", [
     z.object({ foo: z.string() }),
     z.object({ foo: z.number() }),
@@ -59,8 +91,281 @@ func TestZodTypeScript(t *testing.T) {
 	assertTypeScriptRepresentationOf(t, zod.EnsureNullable(zod.EnsureNullable(zod.String())), zImport, `z.string().nullable()`)
 }
 
+func TestZodTypeTypes(t *testing.T) {
+	assertTypes(t, zod.String().Default(ts.StringLiteral("x")), "string", "string | undefined")
+	assertTypes(t, zod.Array(zod.String()).Readonly(), "Readonly<string[]>", "string[]")
+	assertTypes(t, zod.String().Or(zod.NumberLiteral(0)), "string | 0", "string | 0")
+	assertTypes(t, zod.Object(zod.ShapeProperty{Name: "user_id", Schema: zod.String(), OutputName: "userId"}), "{ userId: string }", "{ user_id: string }")
+	assertTypes(t, zod.Any(), `any`, `any`)
+	assertTypes(t, zod.Unknown(), `unknown`, `unknown`)
+	assertTypes(t, zod.String().Nullable(), `string | null`, `string | null`)
+	assertTypes(t, zod.Array(zod.Union(zod.String(), zod.Number())), `(string | number)[]`, `(string | number)[]`)
+	assertTypes(t, zod.Enum("a", "b").Optional(), `"a" | "b" | undefined`, `"a" | "b" | undefined`)
+	assertTypes(t, zod.Record(zod.String(), zod.Boolean()), `Record<string, boolean>`, `Record<string, boolean>`)
+	assertTypes(t, zod.Number().Int().Brand("Count"), zImport+"\n\n"+`number & z.BRAND<"Count">`, `number`)
+	assertTypes(t, zod.String().Nullable().Brand("Name"), zImport+"\n\n"+`(string | null) & z.BRAND<"Name">`, `string | null`)
+	assertTypes(t, zod.String().Transformf("s => s.length"), `unknown`, `string`)
+	assertTypes(t, zod.String().TransformTo(ts.NumberType, ts.AsSource("s => s.length")), `number`, `string`)
+	assertTypes(t, zod.String().Transformf("s => JSON.parse(s)").Pipe(zod.Number()), `number`, `string`)
+	assertTypes(t, zod.Number().DeclaredAs("Amount"), `Amount`, zImport+"\n\n"+`z.input<typeof Amount>`)
+	assertTypes(t, zod.Lazy("Node"), `Node`, `NodeInput`)
+	assertTypes(t, zod.External("@acme/money", "Money"), `import { Money } from "@acme/money";`+"\n"+zImport+"\n\n"+`z.infer<typeof Money>`, `import { Money } from "@acme/money";`+"\n"+zImport+"\n\n"+`z.input<typeof Money>`)
+	assertTypes(t, zod.Object(
+		zod.ShapeProperty{Name: "a", Schema: zod.String()},
+		zod.ShapeProperty{Name: "b", Schema: zod.Array(zod.Number()).Nullable().Transformf("a => a ?? []")},
+		zod.ShapeProperty{Name: "c", Schema: zod.Boolean().Optional()},
+	).Extend(zod.ShapeProperty{Name: "a", Schema: zod.Literal("x")}), `{
+    a: "x";
+    b?: unknown;
+    c?: boolean | undefined;
+}`, `{
+    a: "x";
+    b: number[] | null;
+    c?: boolean | undefined;
+}`)
+}
+
+func TestRecursiveDeclaration(t *testing.T) {
+	schema := zod.Object(
+		zod.ShapeProperty{Name: "name", Schema: zod.String()},
+		zod.ShapeProperty{Name: "children", Schema: zod.Array(zod.Lazy("Node"))},
+	)
+	declaration := zod.NewSchemaAndTypeDeclaration("Node is a node of a tree.", "Node", schema).Recursive()
+	assert.Equal(t, zImport+`
+
+/**
+ * Node is a node of a tree.
+ */
+export type Node = {
+    name: string;
+    children: Node[];
+};
+export type NodeInput = {
+    name: string;
+    children: NodeInput[];
+};
+export const Node: z.ZodType<Node, z.ZodTypeDef, NodeInput> = z.object({
+    name: z.string(),
+    children: z.array(z.lazy(() => Node)),
+});
+`, declaration.TypeScript().String())
+}
+
+func TestFactoryDeclaration(t *testing.T) {
+	schema := zod.Object(
+		zod.ShapeProperty{Name: "items", Schema: zod.Array(zod.TypeParameter("Item"))},
+		zod.ShapeProperty{Name: "next", Schema: zod.Invoke(zod.Lazy("Page"), zod.TypeParameter("Item"))},
+	)
+	declaration := zod.NewFactoryDeclaration("Page is a page of items.", "Page", []ts.Identifier{"Item"}, schema).Recursive()
+	assert.Equal(t, zImport+`
+
+/**
+ * Page is a page of items.
+ */
+export const Page = <Item extends z.ZodTypeAny>(item: Item): z.ZodTypeAny => z.object({
+    items: z.array(item),
+    next: z.lazy(() => Page(item)),
+});
+export type Page<Item extends z.ZodTypeAny> = z.infer<ReturnType<typeof Page<Item>>>;
+`, declaration.TypeScript().String())
+
+	assertTypeScriptRepresentationOf(t, zod.Invoke(zod.Factory("Page"), zod.String()), zImport, `Page(z.string())`)
+	assertTypes(t, zod.TypeParameter("T"), zImport+"\n\nz.output<T>", zImport+"\n\nz.input<T>")
+}
+
+func TestEnumDeclaration(t *testing.T) {
+	schema := zod.EnumOf(
+		zod.EnumMember{Name: "Pending", Value: int64(0), Comment: "Pending orders await payment.", Label: "pending"},
+		zod.EnumMember{Name: "Shipped", Value: int64(1), Label: "shipped"},
+		zod.EnumMember{Name: "Default", Value: int64(0), Label: "default"},
+	)
+	assertTypes(t, schema, "0 | 1", "0 | 1")
+	assert.Equal(t, zImport+`
+
+/**
+ * OrderState is the state of an order.
+ */
+export const OrderState = z.union([
+    z.literal(0),
+    z.literal(1),
+]);
+export type OrderState = z.infer<typeof OrderState>;
+
+/**
+ * OrderStateValues maps the names of the members of OrderState to their values.
+ */
+export const OrderStateValues = {
+    /**
+     * Pending orders await payment.
+     */
+    Pending: 0,
+    Shipped: 1,
+    Default: 0,
+} as const;
+
+/**
+ * OrderStateLabels maps the values of OrderState to their labels.
+ */
+export const OrderStateLabels = {
+    "0": "pending",
+    "1": "shipped",
+} as const;
+`, zod.NewSchemaAndTypeDeclaration("OrderState is the state of an order.", "OrderState", schema).TypeScript().String())
+
+	assertTypeScriptRepresentationOf(t, zod.EnumOf(zod.EnumMember{Name: "Admin", Value: "admin"}, zod.EnumMember{Name: "User", Value: "user"}), zImport, `z.enum([
+    "admin",
+    "user",
+])`)
+	assertTypeScriptRepresentationOf(t, zod.EnumOf(zod.EnumMember{Name: "Only", Value: uint64(7)}), zImport, `z.literal(7)`)
+}
+
+func assertTypes(t *testing.T, schema zod.ZodType, expectedOutput string, expectedInput string) {
+	assert.Equal(t, expectedOutput, schema.OutputType().String(), "output type of %s", schema.TypeScript())
+	assert.Equal(t, expectedInput, schema.InputType().String(), "input type of %s", schema.TypeScript())
+}
+
 func assertTypeScriptRepresentationOf(t *testing.T, schema zod.ZodType, expectedImports string, expectedCode string) {
 	code := schema.TypeScript()
 	//assert.Equal(t, expectedCode, code.WithoutImports())
 	assert.Equal(t, expectedImports+"\n\n"+expectedCode, code.String())
 }
+
+func TestPlainTypes(t *testing.T) {
+	assertPlainType(t, zod.Number().Int().Brand("Count"), `number`)
+	assertPlainType(t, zod.Array(zod.String().Brand("Name")).Nullable(), `string[] | null`)
+	assertPlainType(t, zod.Array(zod.String().Brand("Name")).Nullable().TransformToOutputOf(zod.Array(zod.String().Brand("Name")), ts.AsSource("a => a ?? []")), `string[]`)
+	assertPlainType(t, zod.Record(zod.String(), zod.Union(zod.Number().Brand("Count"), zod.Boolean())), `Record<string, number | boolean>`)
+	assertPlainType(t, zod.Number().DeclaredAs("Amount"), `Amount`)
+	assertPlainType(t, zod.TypeParameter("T"), `T`)
+	assertPlainType(t, zod.Invoke(zod.Factory("Page"), zod.String().Brand("Name"), zod.Lazy("Node")), `Page<string, Node>`)
+	assertPlainType(t, zod.Object(zod.ShapeProperty{Name: "user_id", Schema: zod.String().Brand("ID"), Comment: "user_id identifies the user.", OutputName: "userId"}), `{
+    /**
+     * user_id identifies the user.
+     */
+    userId: string;
+}`)
+}
+
+func TestTypeDeclaration(t *testing.T) {
+	schema := zod.Object(
+		zod.ShapeProperty{Name: "name", Schema: zod.String(), Comment: "name is shown to other users."},
+		zod.ShapeProperty{Name: "nickname", Schema: zod.String().Optional()},
+	)
+	assert.Equal(t, `/**
+ * User is a user.
+ */
+export interface User {
+    /**
+     * name is shown to other users.
+     */
+    name: string;
+    nickname?: string | undefined;
+}
+`, zod.NewSchemaAndTypeDeclaration("User is a user.", "User", schema).TypeOnly().TypeScript().String())
+
+	assert.Equal(t, `/**
+ * Name is branded only in zod.
+ */
+export type Name = string;
+`, zod.NewSchemaAndTypeDeclaration("Name is branded only in zod.", "Name", zod.String().Brand("Name")).TypeOnly().TypeScript().String())
+
+	page := zod.Object(
+		zod.ShapeProperty{Name: "items", Schema: zod.Array(zod.TypeParameter("Item"))},
+		zod.ShapeProperty{Name: "next", Schema: zod.Invoke(zod.Lazy("Page"), zod.TypeParameter("Item")).Nullable()},
+	)
+	assert.Equal(t, `/**
+ * Page is a page of items.
+ */
+export interface Page<Item> {
+    items: Item[];
+    next: Page<Item> | null;
+}
+`, zod.NewFactoryDeclaration("Page is a page of items.", "Page", []ts.Identifier{"Item"}, page).Recursive().TypeOnly().TypeScript().String())
+
+	assert.Equal(t, `/**
+ * Role is the role of a user.
+ */
+export type Role = "admin" | "user";
+
+/**
+ * RoleValues maps the names of the members of Role to their values.
+ */
+export const RoleValues = {
+    Admin: "admin",
+    User: "user",
+} as const;
+`, zod.NewSchemaAndTypeDeclaration("Role is the role of a user.", "Role", zod.EnumOf(zod.EnumMember{Name: "Admin", Value: "admin"}, zod.EnumMember{Name: "User", Value: "user"})).TypeOnly().TypeScript().String())
+
+	// the types are those of the values the schemas accept, since nothing transforms them
+	created := zod.String().DatetimeWithOffset().TransformTo(ts.TypeName(ts.Identifier("Date")), ts.AsSource(`s => new Date(s)`)).DeclaredAs("Created")
+	assert.Equal(t, `/**
+ * Event happened.
+ */
+export interface Event {
+    user_id: string;
+    created: Created;
+    tags: string[] | null;
+}
+`, zod.NewSchemaAndTypeDeclaration("Event happened.", "Event", zod.Object(
+		zod.ShapeProperty{Name: "user_id", Schema: zod.String(), OutputName: "userId"},
+		zod.ShapeProperty{Name: "created", Schema: created},
+		zod.ShapeProperty{Name: "tags", Schema: zod.Array(zod.String()).Nullable().Transform(ts.AsSource(`a => a ?? []`))},
+	)).TypeOnly().TypeScript().String())
+	assert.Equal(t, `/**
+ * Created is a timestamp.
+ */
+export type Created = string;
+`, zod.NewSchemaAndTypeDeclaration("Created is a timestamp.", "Created", zod.String().DatetimeWithOffset().TransformTo(ts.TypeName(ts.Identifier("Date")), ts.AsSource(`s => new Date(s)`))).TypeOnly().TypeScript().String())
+}
+
+func TestExplicitDeclaration(t *testing.T) {
+	schema := zod.Object(
+		zod.ShapeProperty{Name: "name", Schema: zod.String(), Comment: "name is shown to other users."},
+		zod.ShapeProperty{Name: "id", Schema: zod.String().Brand("UserID")},
+	)
+	assert.Equal(t, zImport+`
+
+/**
+ * User is a user.
+ */
+export interface User {
+    name: string;
+    id: string & z.BRAND<"UserID">;
+}
+export type UserInput = {
+    name: string;
+    id: string;
+};
+export const User = z.object({
+    /**
+     * name is shown to other users.
+     */
+    name: z.string(),
+    id: z.string().brand("UserID"),
+}) satisfies z.ZodType<User, z.ZodTypeDef, UserInput>;
+`, zod.NewSchemaAndTypeDeclaration("User is a user.", "User", schema).Explicit().TypeScript().String())
+
+	assert.Equal(t, zImport+`
+
+/**
+ * Name is a name.
+ */
+export type Name = string;
+export const Name = z.string() satisfies z.ZodType<Name>;
+`, zod.NewSchemaAndTypeDeclaration("Name is a name.", "Name", zod.String()).Explicit().TypeScript().String())
+
+	tags := zod.Array(zod.String()).Nullable().TransformToOutputOf(zod.Array(zod.String()), ts.AsSource("a => a ?? []"))
+	assert.Equal(t, zImport+`
+
+/**
+ * Tags are never null once parsed.
+ */
+export type Tags = string[];
+export type TagsInput = string[] | null;
+export const Tags = z.array(z.string()).nullable().transform(a => a ?? []) satisfies z.ZodType<Tags, z.ZodTypeDef, TagsInput>;
+`, zod.NewSchemaAndTypeDeclaration("Tags are never null once parsed.", "Tags", tags).Explicit().TypeScript().String())
+}
+
+func assertPlainType(t *testing.T, schema zod.ZodType, expected string) {
+	assert.Equal(t, expected, schema.PlainType().String(), "plain type of %s", schema.TypeScript())
+}
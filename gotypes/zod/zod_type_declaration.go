@@ -0,0 +1,58 @@
+package zod
+
+import (
+	"github.com/softwaretechnik-berlin/goats/gotypes/ts"
+	"github.com/softwaretechnik-berlin/goats/gotypes/util"
+)
+
+// TypeDeclaration declares the PlainInputType of a schema under the schema's name, without declaring the schema itself,
+// so that it doesn't depend on zod.
+//
+// Without the schema, nothing transforms the values, so the declared type is that of the values the schema accepts, i.e.
+// of the JSON values as JSON.parse returns them, e.g. `string` for a schema transforming strings into Dates, rather than
+// that of the values it produces.
+//
+// Objects are declared as interfaces, e.g. `export interface User { Name: string }`, and other types as type aliases.
+// The value and label maps of enums are declared along with their types.
+type TypeDeclaration struct {
+	declaration SchemaAndTypeDeclaration
+}
+
+// TypeOnly returns the declaration of the plain input type of the declared schema, see TypeDeclaration.
+func (d SchemaAndTypeDeclaration) TypeOnly() TypeDeclaration {
+	return TypeDeclaration{d}
+}
+
+func (d TypeDeclaration) Identifier() ts.Identifier { return d.declaration.identifier }
+
+// Recursive returns the declaration unchanged, since plain types can refer to themselves.
+func (d TypeDeclaration) Recursive() TypeDeclaration {
+	return d
+}
+
+func (d TypeDeclaration) TypeScript() ts.Source {
+	typeParameters := util.Map(d.declaration.typeParameters, func(p ts.Identifier) ts.TypeParameter {
+		return ts.TypeParameter{Name: p}
+	})
+	return d.declaration.withEnumMaps(ts.Statements(
+		ts.DocComment(d.declaration.comment),
+		typeDeclaration(d.declaration.identifier, typeParameters, d.declaration.schema.PlainInputType(), declaresObject(d.declaration.schema)),
+	))
+}
+
+// declaresObject tells whether the plain type of the given schema is an object type, which brands don't affect.
+func declaresObject(schema ZodType) bool {
+	if branded, ok := schema.(zodBranded); ok {
+		return declaresObject(branded.wrapped)
+	}
+	_, ok := schema.(zodObject)
+	return ok
+}
+
+// typeDeclaration declares the given type under the given name, as an interface if it is an object type.
+func typeDeclaration(name ts.Identifier, typeParameters []ts.TypeParameter, t ts.TypeExpression, isObject bool) ts.Source {
+	if isObject {
+		return ts.Sourcef(`export interface %s%s %s`, name, ts.TypeParameters(typeParameters...), t)
+	}
+	return ts.Sourcef(`export type %s%s = %s;`, name, ts.TypeParameters(typeParameters...), t)
+}